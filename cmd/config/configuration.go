@@ -1,15 +1,19 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"path"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"text/template"
 	"time"
 
 	"github.com/YuukanOO/seelf/cmd/serve"
+	authdomain "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/notifier"
 	"github.com/YuukanOO/seelf/pkg/config"
 	"github.com/YuukanOO/seelf/pkg/crypto"
 	"github.com/YuukanOO/seelf/pkg/id"
@@ -17,12 +21,21 @@ import (
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/ostools"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/throttle"
 	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/YuukanOO/seelf/pkg/validate/numbers"
+	vstrings "github.com/YuukanOO/seelf/pkg/validate/strings"
 )
 
 var (
+	ErrUnsupportedDatabaseUrl = errors.New("unsupported_database_url")
+	ErrLeaseDurationTooShort  = errors.New("lease_duration_too_short")
+
 	userConfigDir            = must.Panic(os.UserConfigDir())
+	hostname, _              = os.Hostname()
 	generatedSecretKey       = must.Panic(crypto.RandomKey[string](64))
 	defaultDataDirectory     = filepath.Join(userConfigDir, "seelf")
 	DefaultConfigPath        = filepath.Join(defaultDataDirectory, defaultConfigFilename) // Default configuration path
@@ -37,10 +50,25 @@ const (
 	defaultRunnersPollInterval    = "4s"
 	defaultRunnersDeploymentCount = 4
 	defaultCleanupDeploymentCount = 2
+	defaultDeploymentTimeout      = "1h"
+	defaultApprovalExpiration     = "0"
+	defaultJobsRetention          = "168h"
 	defaultBalancerDomain         = "http://docker.localhost"
 	defaultDeploymentDirTemplate  = "{{ .Environment }}"
+	defaultAuthMaxAttempts        = 5
+	defaultAuthAttemptDelay       = "1s"
+	defaultAuthLockoutDuration    = "15m"
+	defaultSessionLifetime        = "720h"
+	defaultSessionIdleTimeout     = "168h"
+	defaultSmtpPort               = 587
+	defaultClusterLeaseDuration   = "30s"
+	minClusterLeaseDuration       = 15 * time.Second // Leases are renewed every 5 seconds so leave room for a few missed renewals
 )
 
+// Private networks trusted by default when seelf is exposed through its own proxy since
+// the proxy container address will be in one of those ranges.
+var defaultTrustedProxies = []string{"10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16", "fc00::/7"}
+
 type (
 	// Configuration used to configure seelf commands.
 	Configuration interface {
@@ -53,14 +81,27 @@ type (
 	ConfigurationBuilder func(*configuration)
 
 	configuration struct {
-		Log     logConfiguration
-		Data    dataConfiguration
-		Http    httpConfiguration
-		Runners runnersConfiguration
-		Private internalConfiguration `yaml:"-"`
+		Log           logConfiguration
+		Data          dataConfiguration
+		Http          httpConfiguration
+		Auth          authConfiguration
+		Runners       runnersConfiguration
+		Metrics       metricsConfiguration
+		Notifications notificationsConfiguration
+		Cluster       clusterConfiguration
+		Private       internalConfiguration `yaml:"-"`
 
 		appExposedUrl         monad.Maybe[domain.Url]
+		dashboardUrl          monad.Maybe[domain.Url]
 		pollInterval          time.Duration
+		deploymentTimeout     time.Duration
+		approvalExpiration    time.Duration
+		jobsRetention         time.Duration
+		leaseDuration         time.Duration
+		attemptDelay          time.Duration
+		lockoutDuration       time.Duration
+		sessionLifetime       time.Duration
+		sessionIdleTimeout    time.Duration
 		deploymentDirTemplate *template.Template
 		logLevel              log.Level
 		logFormat             log.OutputFormat
@@ -76,19 +117,57 @@ type (
 		Port   int               `env:"HTTP_PORT,PORT"`
 		Secure monad.Maybe[bool] `env:"HTTP_SECURE" yaml:",omitempty"`
 		Secret string            `env:"HTTP_SECRET"`
+		// Comma separated list of proxies (ip or cidr) allowed to set the client ip with forwarded headers
+		TrustedProxies string `env:"HTTP_TRUSTED_PROXIES" yaml:"trusted_proxies,omitempty"`
+	}
+
+	// Configuration related to the authentication process, mostly to prevent brute-force attacks.
+	authConfiguration struct {
+		MaxAttempts     int    `env:"AUTH_MAX_ATTEMPTS" yaml:"max_attempts"`
+		AttemptDelay    string `env:"AUTH_ATTEMPT_DELAY" yaml:"attempt_delay"`
+		LockoutDuration string `env:"AUTH_LOCKOUT_DURATION" yaml:"lockout_duration"`
+		SessionLifetime string `env:"AUTH_SESSION_LIFETIME" yaml:"session_lifetime"`
+		SessionIdle     string `env:"AUTH_SESSION_IDLE_TIMEOUT" yaml:"session_idle_timeout"`
+	}
+
+	// Configuration needed when running multiple instances sharing the same database.
+	clusterConfiguration struct {
+		InstanceID    string `env:"INSTANCE_ID" yaml:"instance_id,omitempty"` // Defaults to the hostname, must be unique among instances
+		LeaseDuration string `env:"CLUSTER_LEASE_DURATION" yaml:"lease_duration"`
+	}
+
+	// Configuration of the Prometheus metrics endpoint.
+	metricsConfiguration struct {
+		Enabled bool   `env:"METRICS_ENABLED"`
+		Token   string `env:"METRICS_TOKEN" yaml:",omitempty"` // If set, must be given as a bearer token to retrieve metrics
+	}
+
+	// Configuration of outbound notifications sent on deployment and target events.
+	notificationsConfiguration struct {
+		// Public url of the dashboard used to build links, default to the url seelf is exposed on if any
+		DashboardUrl string `env:"NOTIFICATIONS_DASHBOARD_URL" yaml:"dashboard_url,omitempty"`
+		SmtpHost     string `env:"SMTP_HOST" yaml:"smtp_host,omitempty"`
+		SmtpPort     int    `env:"SMTP_PORT" yaml:"smtp_port"`
+		SmtpUsername string `env:"SMTP_USERNAME" yaml:"smtp_username,omitempty"`
+		SmtpPassword string `env:"SMTP_PASSWORD" yaml:"smtp_password,omitempty"`
+		SmtpFrom     string `env:"SMTP_FROM" yaml:"smtp_from,omitempty"`
 	}
 
 	// Contains configuration related to where files produced by seelf will be stored.
 	dataConfiguration struct {
 		Path                  string `env:"DATA_PATH"`
+		DatabaseUrl           string `env:"DATABASE_URL" yaml:"database_url,omitempty"` // When empty, a SQLite database stored in the data path is used
 		DeploymentDirTemplate string `env:"DEPLOYMENT_DIR_TEMPLATE" yaml:"deployment_dir_template"`
 	}
 
 	// Configuration related to the async jobs runners.
 	runnersConfiguration struct {
-		PollInterval string `env:"RUNNERS_POLL_INTERVAL" yaml:"poll_interval"`
-		Deployment   int    `env:"RUNNERS_DEPLOYMENT_COUNT" yaml:"deployment"`
-		Cleanup      int    `env:"RUNNERS_CLEANUP_COUNT" yaml:"cleanup"`
+		PollInterval       string `env:"RUNNERS_POLL_INTERVAL" yaml:"poll_interval"`
+		Deployment         int    `env:"RUNNERS_DEPLOYMENT_COUNT" yaml:"deployment"`
+		Cleanup            int    `env:"RUNNERS_CLEANUP_COUNT" yaml:"cleanup"`
+		DeploymentTimeout  string `env:"RUNNERS_DEPLOYMENT_TIMEOUT" yaml:"deployment_timeout"`   // Zero to disable it
+		ApprovalExpiration string `env:"RUNNERS_APPROVAL_EXPIRATION" yaml:"approval_expiration"` // Zero to let deployments wait for approval forever
+		JobsRetention      string `env:"RUNNERS_JOBS_RETENTION" yaml:"jobs_retention"`           // Zero to keep finished jobs forever
 	}
 
 	// internalConfiguration fields not read from the configuration file and use only during specific steps
@@ -115,10 +194,26 @@ func Default(builders ...ConfigurationBuilder) Configuration {
 			Port:   defaultPort,
 			Secret: generatedSecretKey,
 		},
+		Auth: authConfiguration{
+			MaxAttempts:     defaultAuthMaxAttempts,
+			AttemptDelay:    defaultAuthAttemptDelay,
+			LockoutDuration: defaultAuthLockoutDuration,
+			SessionLifetime: defaultSessionLifetime,
+			SessionIdle:     defaultSessionIdleTimeout,
+		},
 		Runners: runnersConfiguration{
-			PollInterval: defaultRunnersPollInterval,
-			Deployment:   defaultRunnersDeploymentCount,
-			Cleanup:      defaultCleanupDeploymentCount,
+			PollInterval:       defaultRunnersPollInterval,
+			Deployment:         defaultRunnersDeploymentCount,
+			Cleanup:            defaultCleanupDeploymentCount,
+			DeploymentTimeout:  defaultDeploymentTimeout,
+			ApprovalExpiration: defaultApprovalExpiration,
+			JobsRetention:      defaultJobsRetention,
+		},
+		Notifications: notificationsConfiguration{
+			SmtpPort: defaultSmtpPort,
+		},
+		Cluster: clusterConfiguration{
+			LeaseDuration: defaultClusterLeaseDuration,
 		},
 	}
 
@@ -179,7 +274,72 @@ func (c *configuration) Secret() []byte                            { return []by
 func (c *configuration) RunnersPollInterval() time.Duration        { return c.pollInterval }
 func (c *configuration) RunnersDeploymentCount() int               { return c.Runners.Deployment }
 func (c *configuration) RunnersCleanupCount() int                  { return c.Runners.Cleanup }
+func (c *configuration) DeploymentTimeout() time.Duration          { return c.deploymentTimeout }
+func (c *configuration) ApprovalExpiration() time.Duration         { return c.approvalExpiration }
+func (c *configuration) RunnersJobsRetention() time.Duration       { return c.jobsRetention }
+func (c *configuration) LeaseDuration() time.Duration              { return c.leaseDuration }
 func (c *configuration) IsDebug() bool                             { return c.logLevel == log.DebugLevel }
+func (c *configuration) MetricsEnabled() bool                      { return c.Metrics.Enabled }
+func (c *configuration) MetricsToken() string                      { return c.Metrics.Token }
+
+func (c *configuration) NotificationsSmtp() notifier.SmtpOptions {
+	return notifier.SmtpOptions{
+		Host:     c.Notifications.SmtpHost,
+		Port:     c.Notifications.SmtpPort,
+		Username: c.Notifications.SmtpUsername,
+		Password: c.Notifications.SmtpPassword,
+		From:     c.Notifications.SmtpFrom,
+	}
+}
+
+// Returns the public url of the dashboard if explicitly set or the url seelf is
+// exposed on.
+func (c *configuration) NotificationsDashboardUrl() monad.Maybe[domain.Url] {
+	if c.dashboardUrl.HasValue() {
+		return c.dashboardUrl
+	}
+
+	if url, isSet := c.appExposedUrl.TryGet(); isSet {
+		return monad.Value(url.WithoutUser().Root())
+	}
+
+	return c.dashboardUrl
+}
+
+func (c *configuration) LoginThrottling() throttle.Options {
+	return throttle.Options{
+		MaxAttempts: c.Auth.MaxAttempts,
+		Delay:       c.attemptDelay,
+		Lockout:     c.lockoutDuration,
+	}
+}
+
+func (c *configuration) SessionTimeouts() authdomain.SessionTimeouts {
+	return authdomain.SessionTimeouts{
+		Lifetime: c.sessionLifetime,
+		Idle:     c.sessionIdleTimeout,
+	}
+}
+
+// Returns the list of trusted proxies. When not explicitly set and seelf is exposed
+// through its own proxy, private networks will be trusted.
+func (c *configuration) TrustedProxies() []string {
+	if c.Http.TrustedProxies == "" {
+		if c.appExposedUrl.HasValue() {
+			return defaultTrustedProxies
+		}
+
+		return nil
+	}
+
+	proxies := strings.Split(c.Http.TrustedProxies, ",")
+
+	for i, proxy := range proxies {
+		proxies[i] = strings.TrimSpace(proxy)
+	}
+
+	return proxies
+}
 
 func (c *configuration) IsSecure() bool {
 	// If secure has been explicitly isSet, returns it
@@ -194,8 +354,35 @@ func (c *configuration) IsSecure() bool {
 	return false
 }
 
+// Gets the identifier of this instance, used to claim jobs and to elect a leader among
+// instances sharing the same database.
+func (c *configuration) InstanceID() string {
+	if c.Cluster.InstanceID != "" {
+		return c.Cluster.InstanceID
+	}
+
+	if hostname != "" {
+		return hostname
+	}
+
+	return "seelf"
+}
+
+// Gets the SQL dialect of the database to connect to.
+func (c *configuration) Dialect() storage.Dialect {
+	if c.Data.DatabaseUrl != "" {
+		return postgres.Dialect
+	}
+
+	return sqlite.Dialect
+}
+
 // Gets the connection string to be used.
 func (c *configuration) ConnectionString() string {
+	if c.Data.DatabaseUrl != "" {
+		return c.Data.DatabaseUrl
+	}
+
 	return "file:" + path.Join(c.Data.Path, databaseConnectionString)
 }
 
@@ -206,12 +393,43 @@ func (c *configuration) ListenAddress() string {
 
 func (c *configuration) PostLoad() error {
 	return validate.Struct(validate.Of{
-		"log.level":                    validate.Value(c.Log.Level, &c.logLevel, log.ParseLevel),
-		"log.format":                   validate.Value(c.Log.Format, &c.logFormat, log.ParseFormat),
+		"log.level":  validate.Value(c.Log.Level, &c.logLevel, log.ParseLevel),
+		"log.format": validate.Value(c.Log.Format, &c.logFormat, log.ParseFormat),
+		"data.database_url": validate.If(c.Data.DatabaseUrl != "", func() error {
+			if !strings.HasPrefix(c.Data.DatabaseUrl, "postgres://") && !strings.HasPrefix(c.Data.DatabaseUrl, "postgresql://") {
+				return ErrUnsupportedDatabaseUrl
+			}
+
+			return nil
+		}),
 		"data.deployment_dir_template": validate.Value(c.Data.DeploymentDirTemplate, &c.deploymentDirTemplate, template.New("").Parse),
 		"runners.poll_interval":        validate.Value(c.Runners.PollInterval, &c.pollInterval, time.ParseDuration),
 		"runners.deployment":           validate.Field(c.Runners.Deployment, numbers.Min(1)),
 		"runners.cleanup":              validate.Field(c.Runners.Cleanup, numbers.Min(1)),
+		"runners.deployment_timeout":   validate.Value(c.Runners.DeploymentTimeout, &c.deploymentTimeout, time.ParseDuration),
+		"runners.approval_expiration":  validate.Value(c.Runners.ApprovalExpiration, &c.approvalExpiration, time.ParseDuration),
+		"runners.jobs_retention":       validate.Value(c.Runners.JobsRetention, &c.jobsRetention, time.ParseDuration),
+		"cluster.lease_duration":       validate.Value(c.Cluster.LeaseDuration, &c.leaseDuration, parseLeaseDuration),
+		"auth.max_attempts":            validate.Field(c.Auth.MaxAttempts, numbers.Min(1)),
+		"auth.attempt_delay":           validate.Value(c.Auth.AttemptDelay, &c.attemptDelay, time.ParseDuration),
+		"auth.lockout_duration":        validate.Value(c.Auth.LockoutDuration, &c.lockoutDuration, time.ParseDuration),
+		"auth.session_lifetime":        validate.Value(c.Auth.SessionLifetime, &c.sessionLifetime, time.ParseDuration),
+		"auth.session_idle_timeout":    validate.Value(c.Auth.SessionIdle, &c.sessionIdleTimeout, time.ParseDuration),
+		"notifications.dashboard_url": validate.If(c.Notifications.DashboardUrl != "", func() error {
+			url, err := domain.UrlFrom(strings.TrimSuffix(c.Notifications.DashboardUrl, "/"))
+
+			if err != nil {
+				return err
+			}
+
+			c.dashboardUrl.Set(url)
+
+			return nil
+		}),
+		"notifications.smtp_port": validate.Field(c.Notifications.SmtpPort, numbers.Min(1)),
+		"notifications.smtp_from": validate.If(c.Notifications.SmtpHost != "", func() error {
+			return validate.Field(c.Notifications.SmtpFrom, vstrings.Required)
+		}),
 		"exposed_as": validate.If(c.Private.ExposedOn != "", func() error {
 			url, err := domain.UrlFrom(c.Private.ExposedOn)
 
@@ -228,8 +446,25 @@ func (c *configuration) PostLoad() error {
 
 // Configuration builder used to set some tests sensible defaults.
 // Generates a random data directory path to avoid conflicts with other tests.
+// If the SEELF_TEST_DATABASE_URL environment variable is set, tests will run against it
+// instead of a SQLite database.
 func WithTestDefaults() ConfigurationBuilder {
 	return func(c *configuration) {
 		c.Data.Path = "__testdata_" + id.New[string]()
+		c.Data.DatabaseUrl = os.Getenv("SEELF_TEST_DATABASE_URL")
 	}
 }
+
+func parseLeaseDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+
+	if err != nil {
+		return duration, err
+	}
+
+	if duration < minClusterLeaseDuration {
+		return duration, ErrLeaseDurationTooShort
+	}
+
+	return duration, nil
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"github.com/YuukanOO/seelf/cmd/config"
 	"github.com/YuukanOO/seelf/cmd/serve"
+	"github.com/YuukanOO/seelf/cmd/users"
 	"github.com/YuukanOO/seelf/cmd/version"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/spf13/cobra"
@@ -35,7 +36,10 @@ func Root() *cobra.Command {
 	rootCmd.PersistentFlags().StringVarP(&path, "config", "c", config.DefaultConfigPath, "config file to use")
 
 	// Add sub-commands
-	rootCmd.AddCommand(serve.Root(conf, logger))
+	rootCmd.AddCommand(
+		serve.Root(conf, logger),
+		users.Root(conf, logger),
+	)
 
 	return rootCmd
 }
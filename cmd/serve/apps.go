@@ -1,13 +1,19 @@
 package serve
 
 import (
+	"sync"
+	"time"
+
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_service_logs"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/gin-gonic/gin"
 )
 
@@ -67,7 +73,8 @@ func (s *server) listAppsHandler() gin.HandlerFunc {
 
 func (s *server) getAppByIDHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
-		app, err := bus.Send(s.bus, ctx.Request.Context(), get_app_detail.Query{
+		context := ctx.Request.Context()
+		app, err := bus.Send(s.bus, context, get_app_detail.Query{
 			ID: ctx.Param("id"),
 		})
 
@@ -75,6 +82,39 @@ func (s *server) getAppByIDHandler() gin.HandlerFunc {
 			return err
 		}
 
+		var wg sync.WaitGroup
+
+		// Targets may be slow to answer so environments are inspected concurrently and a failure
+		// is reported in the status itself instead of failing the whole request.
+		for env, config := range map[string]*get_app_detail.EnvironmentConfig{
+			"production": &app.Production,
+			"staging":    &app.Staging,
+		} {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				status, err := bus.Send(s.bus, context, get_runtime_status.Query{
+					TargetID:    config.Target.ID,
+					AppID:       monad.Value(app.ID),
+					Environment: monad.Value(env),
+				})
+
+				if err != nil {
+					status = get_runtime_status.Status{
+						Services:  []get_runtime_status.Service{},
+						Error:     monad.Value(err.Error()),
+						CheckedAt: time.Now().UTC(),
+					}
+				}
+
+				config.Runtime.Set(status)
+			}()
+		}
+
+		wg.Wait()
+
 		return http.Ok(ctx, app)
 	})
 }
@@ -90,3 +130,39 @@ func (s *server) requestAppCleanupHandler() gin.HandlerFunc {
 		return http.NoContent(ctx)
 	})
 }
+
+// FIXME: till gin support custom types in query binding...
+type getServiceLogsFilters struct {
+	Since  string `form:"since"`
+	Tail   *uint  `form:"tail"`
+	Follow bool   `form:"follow"`
+}
+
+func (s *server) getServiceLogsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request getServiceLogsFilters) error {
+		query := get_service_logs.Query{
+			AppID:       ctx.Param("id"),
+			Environment: ctx.Param("env"),
+			Service:     ctx.Param("name"),
+			Follow:      request.Follow,
+		}
+
+		if request.Since != "" {
+			query.Since.Set(request.Since)
+		}
+
+		if request.Tail != nil {
+			query.Tail.Set(*request.Tail)
+		}
+
+		logs, err := bus.Send(s.bus, ctx.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		defer logs.Close()
+
+		return http.Stream(ctx, "text/plain; charset=utf-8", logs)
+	})
+}
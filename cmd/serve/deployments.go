@@ -1,19 +1,30 @@
 package serve
 
 import (
+	"errors"
 	"mime/multipart"
+	"os"
 	"strconv"
+	"time"
 
+	"github.com/YuukanOO/seelf/internal/deployment/app/approve_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/cancel_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_timeline"
 	"github.com/YuukanOO/seelf/internal/deployment/app/promote"
 	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/reject_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/git"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
 	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/ostools"
 	"github.com/gin-gonic/gin"
 )
 
@@ -55,13 +66,15 @@ func (s *server) queueDeploymentHandler() gin.HandlerFunc {
 func (s *server) redeployHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
 		var (
-			appid     = ctx.Param("id")
-			source, _ = strconv.Atoi(ctx.Param("number"))
+			appid       = ctx.Param("id")
+			source, _   = strconv.Atoi(ctx.Param("number"))
+			override, _ = strconv.ParseBool(ctx.Query("override_freeze"))
 		)
 
 		number, err := bus.Send(s.bus, ctx.Request.Context(), redeploy.Command{
 			AppID:            appid,
 			DeploymentNumber: source,
+			OverrideFreeze:   override,
 		})
 
 		if err != nil {
@@ -72,16 +85,63 @@ func (s *server) redeployHandler() gin.HandlerFunc {
 	})
 }
 
+func (s *server) cancelDeploymentHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), cancel_deployment.Command{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) approveDeploymentHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), approve_deployment.Command{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) rejectDeploymentHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), reject_deployment.Command{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
 func (s *server) promoteHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
 		var (
-			appid     = ctx.Param("id")
-			source, _ = strconv.Atoi(ctx.Param("number"))
+			appid       = ctx.Param("id")
+			source, _   = strconv.Atoi(ctx.Param("number"))
+			override, _ = strconv.ParseBool(ctx.Query("override_freeze"))
 		)
 
 		number, err := bus.Send(s.bus, ctx.Request.Context(), promote.Command{
 			AppID:            appid,
 			DeploymentNumber: source,
+			OverrideFreeze:   override,
 		})
 
 		if err != nil {
@@ -123,7 +183,129 @@ func (s *server) getDeploymentLogsHandler() gin.HandlerFunc {
 			return err
 		}
 
-		return http.File(ctx, logpath)
+		file, err := os.Open(logpath)
+
+		if errors.Is(err, os.ErrNotExist) {
+			return apperr.ErrNotFound
+		}
+
+		if err != nil {
+			return err
+		}
+
+		defer file.Close()
+
+		logs := artifact.NewTextRenderer(file)
+		defer logs.Close()
+
+		return http.Stream(ctx, "text/plain; charset=utf-8", logs)
+	})
+}
+
+func (s *server) getDeploymentTimelineHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		number, _ := strconv.Atoi(ctx.Param("number"))
+
+		timeline, err := bus.Send(s.bus, ctx.Request.Context(), get_deployment_timeline.Query{
+			AppID:            ctx.Param("id"),
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, timeline)
+	})
+}
+
+const (
+	logsStreamPollInterval = time.Second
+	logsStreamLineEvent    = "log"
+	logsStreamEndEvent     = "end"
+)
+
+type streamDeploymentLogsFilters struct {
+	Offset int64 `form:"offset"`
+}
+
+// Stream the deployment logs as server-sent events until the deployment reaches a final
+// state. Each line is sent with the byte offset following it as the event id so the client
+// could resume with the `offset` query parameter or the `Last-Event-ID` header.
+func (s *server) streamDeploymentLogsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request streamDeploymentLogsFilters) error {
+		var (
+			c           = ctx.Request.Context()
+			offset      = request.Offset
+			appid       = ctx.Param("id")
+			number, _   = strconv.Atoi(ctx.Param("number"))
+			lastEventID = ctx.GetHeader("Last-Event-ID")
+		)
+
+		if lastEventID != "" {
+			offset, _ = strconv.ParseInt(lastEventID, 10, 64)
+		}
+
+		logpath, err := bus.Send(s.bus, c, get_deployment_log.Query{
+			AppID:            appid,
+			DeploymentNumber: number,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		http.EventStream(ctx)
+
+		ticker := time.NewTicker(logsStreamPollInterval)
+		defer ticker.Stop()
+
+		for {
+			// Retrieve the deployment status before reading the file so every line
+			// written before the deployment has ended is sent.
+			deployment, err := bus.Send(s.bus, c, get_deployment.Query{
+				AppID:            appid,
+				DeploymentNumber: number,
+			})
+
+			if err != nil {
+				s.logger.Errorw("could not retrieve deployment while streaming logs", "error", err)
+				return nil
+			}
+
+			status := domain.DeploymentStatus(deployment.State.Status)
+			lines, next, err := ostools.ReadLines(logpath, offset, status.IsFinal())
+
+			if err != nil {
+				s.logger.Errorw("could not read deployment logs", "error", err, "path", logpath)
+				return nil
+			}
+
+			for _, line := range lines {
+				offset += int64(len(line)) + 1
+
+				if offset > next {
+					offset = next // Partial line without a line feed
+				}
+
+				if err = http.SendEvent(ctx, strconv.FormatInt(offset, 10), logsStreamLineEvent, artifact.RenderLine(line)); err != nil {
+					return nil
+				}
+			}
+
+			offset = next
+
+			if status.IsFinal() {
+				_ = http.SendEvent(ctx, strconv.FormatInt(offset, 10), logsStreamEndEvent, strconv.Itoa(int(status)))
+				return nil
+			}
+
+			select {
+			case <-c.Done():
+				return nil
+			case <-ticker.C:
+			}
+		}
 	})
 }
 
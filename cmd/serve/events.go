@@ -0,0 +1,78 @@
+package serve
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	deploymentevents "github.com/YuukanOO/seelf/internal/deployment/infra/events"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+const eventsKeepAliveInterval = 30 * time.Second
+
+// Events which can be received by callers authenticated with an API key since they only
+// have access to applications related endpoints.
+var apiAccessEvents = []string{
+	deploymentevents.DeploymentStateChanged,
+	deploymentevents.AppEnvChanged,
+}
+
+type streamEventsFilters struct {
+	Types string `form:"types"`
+}
+
+func (s *server) streamEventsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(ctx *gin.Context, request streamEventsFilters) error {
+		var (
+			c     = ctx.Request.Context()
+			types []string
+		)
+
+		if request.Types != "" {
+			types = strings.Split(request.Types, ",")
+		}
+
+		withSession := domain.CurrentSession(c).HasValue()
+
+		events, unsubscribe := s.events.Subscribe(func(evt relay.Event) bool {
+			if !withSession && !slices.Contains(apiAccessEvents, evt.Name) {
+				return false
+			}
+
+			return len(types) == 0 || slices.Contains(types, evt.Name)
+		})
+		defer unsubscribe()
+
+		http.EventStream(ctx)
+
+		ticker := time.NewTicker(eventsKeepAliveInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-c.Done():
+				return nil
+			case <-ticker.C:
+				if err := http.SendComment(ctx, "ping"); err != nil {
+					return nil
+				}
+			case evt := <-events:
+				data, err := json.Marshal(evt.Data)
+
+				if err != nil {
+					s.logger.Errorw("could not serialize event", "error", err, "event", evt.Name)
+					continue
+				}
+
+				if err = http.SendEvent(ctx, "", evt.Name, string(data)); err != nil {
+					return nil
+				}
+			}
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_freeze_windows"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// FIXME: till gin support custom types in query binding...
+type getFreezeWindowsFilters struct {
+	AppID string `form:"app_id"`
+}
+
+func (s *server) createFreezeWindowHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_freeze_window.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_freeze_window.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/freeze-windows/%s", id)
+	})
+}
+
+func (s *server) deleteFreezeWindowHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_freeze_window.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listFreezeWindowsHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request getFreezeWindowsFilters) error {
+		var query get_freeze_windows.Query
+
+		if request.AppID != "" {
+			query.AppID.Set(request.AppID)
+		}
+
+		data, err := bus.Send(s.bus, c.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getFreezeWindowByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_freeze_window.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
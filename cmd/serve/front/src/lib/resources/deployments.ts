@@ -8,7 +8,8 @@ export enum DeploymentStatus {
 	Pending = 0,
 	Running = 1,
 	Failed = 2,
-	Succeeded = 3
+	Succeeded = 3,
+	AwaitingApproval = 4
 }
 
 export type SourceData =
@@ -72,6 +73,27 @@ export type Deployment = {
 
 export type DeploymentDetail = Omit<Deployment, 'state'> & {
 	state: StateWithServices;
+	approved_at?: string;
+	approved_by?: ByUserData;
+	freeze_overridden: boolean;
+	queue?: DeploymentQueue;
+};
+
+export type QueueReason =
+	| 'ready'
+	| 'target_configuring'
+	| 'waiting_for_group'
+	| 'retry_scheduled'
+	| 'scheduled'
+	| 'target_busy';
+
+export type DeploymentQueue = {
+	position: number;
+	reason: QueueReason;
+	not_before: string;
+	attempts: number;
+	error_code?: string;
+	eta?: string;
 };
 
 export type QueueDeployment =
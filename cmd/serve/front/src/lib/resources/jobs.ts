@@ -8,13 +8,21 @@ export type Job = {
 	group: string;
 	message_name: string;
 	message_data: string;
+	message_version: number;
 	queued_at: string;
 	not_before: string;
 	error_code?: string;
 	policy: number;
 	retrieved: boolean;
+	state: JobState;
+	worker_group?: string;
+	instance?: string;
+	started_at?: string;
+	finished_at?: string;
 };
 
+export type JobState = 'pending' | 'retrying' | 'running' | 'done' | 'dead';
+
 export enum JobPolicy {
 	PreserveOrder = 1,
 	WaitForOthersResourceID = 2,
@@ -32,6 +32,7 @@ export type Target = {
 	url?: string;
 	provider: ProviderConfigData;
 	state: TargetState;
+	max_concurrent_deployments?: number;
 	cleanup_requested_at?: string;
 	created_at: string;
 	created_by: ByUserData;
@@ -40,6 +41,7 @@ export type Target = {
 export type CreateTarget = {
 	name: string;
 	url?: string;
+	max_concurrent_deployments?: number;
 	docker?: {
 		host?: string;
 		user?: string;
@@ -51,6 +53,7 @@ export type CreateTarget = {
 export type UpdateTarget = {
 	name?: string;
 	url: Patch<string>;
+	max_concurrent_deployments?: Patch<number>;
 	docker?: {
 		host?: string;
 		user?: string;
@@ -7,7 +7,10 @@ import (
 )
 
 type listJobsFilters struct {
-	Page int `form:"page"`
+	Page       int    `form:"page"`
+	Message    string `form:"message"`
+	ResourceID string `form:"resource_id"`
+	State      string `form:"state"`
 }
 
 func (s *server) listJobsHandler() gin.HandlerFunc {
@@ -18,6 +21,18 @@ func (s *server) listJobsHandler() gin.HandlerFunc {
 			filters.Page.Set(request.Page)
 		}
 
+		if request.Message != "" {
+			filters.Message.Set(request.Message)
+		}
+
+		if request.ResourceID != "" {
+			filters.ResourceID.Set(request.ResourceID)
+		}
+
+		if request.State != "" {
+			filters.State.Set(bus.JobState(request.State))
+		}
+
 		jobs, err := s.scheduledJobsStore.GetAllJobs(ctx.Request.Context(), filters)
 
 		if err != nil {
@@ -28,14 +43,58 @@ func (s *server) listJobsHandler() gin.HandlerFunc {
 	})
 }
 
-func (s *server) deleteJobsHandler() gin.HandlerFunc {
+func (s *server) getJobByIDHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
-		err := s.scheduledJobsStore.Delete(ctx.Request.Context(), ctx.Param("id"))
+		job, err := s.scheduledJobsStore.GetJobByID(ctx.Request.Context(), ctx.Param("id"))
 
 		if err != nil {
 			return err
 		}
 
+		return http.Ok(ctx, job)
+	})
+}
+
+func (s *server) deleteJobsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var (
+			c  = ctx.Request.Context()
+			id = ctx.Param("id")
+		)
+
+		if err := s.scheduledJobsStore.Delete(c, id); err != nil {
+			return err
+		}
+
+		if err := s.bus.Notify(c, bus.JobQueueChanged{
+			JobID:  id,
+			Change: bus.JobChangeDeleted,
+		}); err != nil {
+			s.logger.Errorw("could not notify job deletion", "error", err, "job", id)
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) retryJobHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		var (
+			c  = ctx.Request.Context()
+			id = ctx.Param("id")
+		)
+
+		if err := s.scheduledJobsStore.Requeue(c, id); err != nil {
+			return err
+		}
+
+		if err := s.bus.Notify(c, bus.JobQueueChanged{
+			JobID:  id,
+			Change: bus.JobChangeRequeued,
+		}); err != nil {
+			s.logger.Errorw("could not notify job requeue", "error", err, "job", id)
+		}
+
 		return http.NoContent(ctx)
 	})
 }
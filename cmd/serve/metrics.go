@@ -0,0 +1,71 @@
+package serve
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path used when a request does not match any registered route (such as SPA assets)
+// to avoid a cardinality explosion.
+const unmatchedRoutePath = "unmatched"
+
+// Observe every HTTP requests handled by the router.
+func (s *server) measureRequests(registerer prometheus.Registerer) gin.HandlerFunc {
+	var (
+		requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "seelf_http_requests_total",
+			Help: "Number of HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "code"})
+		duration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "seelf_http_request_duration_seconds",
+			Help:    "Duration of HTTP requests by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"})
+	)
+
+	registerer.MustRegister(requests, duration)
+
+	return func(ctx *gin.Context) {
+		start := time.Now()
+
+		ctx.Next()
+
+		route := ctx.FullPath()
+
+		if route == "" {
+			route = unmatchedRoutePath
+		}
+
+		requests.WithLabelValues(ctx.Request.Method, route, strconv.Itoa(ctx.Writer.Status())).Inc()
+		duration.WithLabelValues(ctx.Request.Method, route).Observe(time.Since(start).Seconds())
+	}
+}
+
+// Expose gathered metrics in the Prometheus format. If a token has been configured,
+// it must be given in the Authorization header as a bearer token.
+func (s *server) metricsHandler(gatherer prometheus.Gatherer) gin.HandlerFunc {
+	var (
+		handler = promhttp.HandlerFor(gatherer, promhttp.HandlerOpts{})
+		token   = []byte(s.options.MetricsToken())
+	)
+
+	return func(ctx *gin.Context) {
+		if len(token) > 0 {
+			given := []byte(ctx.GetHeader(apiAuthHeader))
+			expected := append([]byte(apiAuthPrefix), token...)
+
+			if subtle.ConstantTimeCompare(given, expected) != 1 {
+				_ = ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
+				return
+			}
+		}
+
+		handler.ServeHTTP(ctx.Writer, ctx.Request)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/YuukanOO/seelf/internal/auth/app/check_session"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
 	httputils "github.com/YuukanOO/seelf/pkg/http"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
@@ -24,21 +27,36 @@ var errUnauthorized = errors.New("unauthorized")
 
 func (s *server) authenticate(withApiAccess bool) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
-		// First, try to find a user id in the encrypted session cookie
+		// First, try to find a session id in the signed session cookie
 		sess := sessions.Default(ctx)
-		uid, ok := sess.Get(userSessionKey).(string)
-		failed := !ok || uid == ""
 
-		// If it failed and api access is not allowed, return early
-		if failed && !withApiAccess {
-			_ = ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
-			return
+		if sid, ok := sess.Get(userSessionKey).(string); ok && sid != "" {
+			c := ctx.Request.Context()
+			uid, err := bus.Send(s.bus, c, check_session.Command{
+				ID: sid,
+			})
+
+			if err == nil {
+				// The session is valid, attach both the user and session ids to the context passed down in every usecases.
+				c = domain.WithSessionID(domain.WithUserID(c, domain.UserID(uid)), domain.SessionID(sid))
+				ctx.Request = ctx.Request.WithContext(c)
+				ctx.Next()
+				return
+			}
+
+			if _, isAppErr := apperr.As[apperr.Error](err); !isAppErr {
+				httputils.HandleError(s, ctx, err)
+				return
+			}
+
+			// The session has expired or has been revoked, clear the cookie
+			sess.Clear()
+			_ = sess.Save()
 		}
 
-		// Else, if we do not fail, the user id has been found, go on
-		if !failed {
-			ctx.Request = ctx.Request.WithContext(domain.WithUserID(ctx.Request.Context(), domain.UserID(uid)))
-			ctx.Next()
+		// If it failed and api access is not allowed, return early
+		if !withApiAccess {
+			_ = ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
 			return
 		}
 
@@ -50,9 +68,20 @@ func (s *server) authenticate(withApiAccess bool) gin.HandlerFunc {
 			return
 		}
 
+		ipKey := "ip:" + ctx.ClientIP()
+
+		if wait, allowed := s.attempts.Allowed(ipKey); !allowed {
+			httputils.HandleError(s, ctx, tooManyRequests(ctx, wait))
+			return
+		}
+
 		id, err := s.usersReader.GetIDFromAPIKey(ctx.Request.Context(), domain.APIKey(authHeader[apiAuthPrefixLength:]))
 
 		if err != nil {
+			if errors.Is(err, apperr.ErrNotFound) {
+				s.loginFailed(ctx, "", ipKey)
+			}
+
 			_ = ctx.AbortWithError(http.StatusUnauthorized, errUnauthorized)
 			return
 		}
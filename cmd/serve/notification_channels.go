@@ -0,0 +1,95 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_notification_channels"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_notification_channel"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+func (s *server) createNotificationChannelHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_notification_channel.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_notification_channel.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/notification-channels/%s", id)
+	})
+}
+
+func (s *server) updateNotificationChannelHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_notification_channel.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_notification_channel.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deleteNotificationChannelHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_notification_channel.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listNotificationChannelsHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_notification_channels.Query{})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getNotificationChannelByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_notification_channel.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
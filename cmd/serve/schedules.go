@@ -0,0 +1,106 @@
+package serve
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_schedules"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_schedule"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/gin-gonic/gin"
+)
+
+// FIXME: till gin support custom types in query binding...
+type getSchedulesFilters struct {
+	AppID string `form:"app_id"`
+}
+
+func (s *server) createScheduleHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd create_schedule.Command) error {
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_schedule.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Created(s, c, data, "/api/v1/schedules/%s", id)
+	})
+}
+
+func (s *server) updateScheduleHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, cmd update_schedule.Command) error {
+		cmd.ID = c.Param("id")
+		ctx := c.Request.Context()
+
+		id, err := bus.Send(s.bus, ctx, cmd)
+
+		if err != nil {
+			return err
+		}
+
+		data, err := bus.Send(s.bus, ctx, get_schedule.Query{
+			ID: id,
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) deleteScheduleHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		if _, err := bus.Send(s.bus, ctx.Request.Context(), delete_schedule.Command{
+			ID: ctx.Param("id"),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) listSchedulesHandler() gin.HandlerFunc {
+	return http.Bind(s, func(c *gin.Context, request getSchedulesFilters) error {
+		var query get_schedules.Query
+
+		if request.AppID != "" {
+			query.AppID.Set(request.AppID)
+		}
+
+		data, err := bus.Send(s.bus, c.Request.Context(), query)
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
+
+func (s *server) getScheduleByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(c *gin.Context) error {
+		data, err := bus.Send(s.bus, c.Request.Context(), get_schedule.Query{
+			ID: c.Param("id"),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(c, data)
+	})
+}
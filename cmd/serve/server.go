@@ -15,7 +15,9 @@ import (
 	"github.com/YuukanOO/seelf/cmd/startup"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
 	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/throttle"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-contrib/sessions/cookie"
 	"github.com/gin-gonic/gin"
@@ -36,6 +38,11 @@ type (
 		IsSecure() bool
 		IsDebug() bool
 		ListenAddress() string
+		TrustedProxies() []string
+		LoginThrottling() throttle.Options
+		SessionTimeouts() domain.SessionTimeouts
+		MetricsEnabled() bool
+		MetricsToken() string
 	}
 
 	server struct {
@@ -45,6 +52,8 @@ type (
 		logger             log.Logger
 		usersReader        domain.UsersReader
 		scheduledJobsStore bus.ScheduledJobsStore
+		events             *relay.Relay
+		attempts           *throttle.Tracker
 	}
 )
 
@@ -56,15 +65,22 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 		router:             gin.New(),
 		usersReader:        root.UsersReader(),
 		scheduledJobsStore: root.ScheduledJobsStore(),
+		events:             root.Events(),
 		bus:                root.Bus(),
 		logger:             root.Logger(),
+		attempts:           throttle.NewTracker(options.LoginThrottling()),
 	}
 
-	_ = s.router.SetTrustedProxies(nil)
+	if err := s.router.SetTrustedProxies(options.TrustedProxies()); err != nil {
+		s.logger.Warnw("invalid trusted proxies, forwarded headers will be ignored",
+			"error", err)
+		_ = s.router.SetTrustedProxies(nil)
+	}
 
 	// Configure the session store
 	store := cookie.NewStore(options.Secret())
 	store.Options(sessions.Options{
+		MaxAge:   int(options.SessionTimeouts().Lifetime.Seconds()),
 		Secure:   options.IsSecure(),
 		HttpOnly: true,
 		SameSite: http.SameSiteStrictMode,
@@ -74,8 +90,16 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 		s.router.Use(s.requestLogger)
 	}
 
+	if options.MetricsEnabled() {
+		s.router.Use(s.measureRequests(root.Metrics()))
+	}
+
 	s.router.Use(s.recoverer, sessions.Sessions(sessionName, store))
 
+	if options.MetricsEnabled() {
+		s.router.GET("/metrics", s.metricsHandler(root.Metrics()))
+	}
+
 	// Let's register every routes now!
 	v1 := s.router.Group("/api/v1")
 
@@ -86,8 +110,13 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 	// Authenticated routes
 	v1secured := v1.Group("", s.authenticate(false))
 	v1secured.DELETE("/session", s.deleteSessionHandler())
+	v1secured.GET("/sessions", s.listSessionsHandler())
+	v1secured.DELETE("/sessions", s.deleteOtherSessionsHandler())
+	v1secured.DELETE("/sessions/:id", s.deleteSessionByIDHandler())
 	v1secured.GET("/jobs", s.listJobsHandler())
+	v1secured.GET("/jobs/:id", s.getJobByIDHandler())
 	v1secured.DELETE("/jobs/:id", s.deleteJobsHandler())
+	v1secured.POST("/jobs/:id/retry", s.retryJobHandler())
 	v1secured.GET("/profile", s.getProfileHandler())
 	v1secured.PATCH("/profile", s.updateProfileHandler())
 	v1secured.PUT("/profile/key", s.refreshProfileKeyHandler())
@@ -102,6 +131,20 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 	v1secured.DELETE("/registries/:id", s.deleteRegistryHandler())
 	v1secured.GET("/registries", s.listRegistriesHandler())
 	v1secured.GET("/registries/:id", s.getRegistryByIDHandler())
+	v1secured.POST("/notification-channels", s.createNotificationChannelHandler())
+	v1secured.PATCH("/notification-channels/:id", s.updateNotificationChannelHandler())
+	v1secured.DELETE("/notification-channels/:id", s.deleteNotificationChannelHandler())
+	v1secured.GET("/notification-channels", s.listNotificationChannelsHandler())
+	v1secured.GET("/notification-channels/:id", s.getNotificationChannelByIDHandler())
+	v1secured.POST("/schedules", s.createScheduleHandler())
+	v1secured.PATCH("/schedules/:id", s.updateScheduleHandler())
+	v1secured.DELETE("/schedules/:id", s.deleteScheduleHandler())
+	v1secured.GET("/schedules", s.listSchedulesHandler())
+	v1secured.GET("/schedules/:id", s.getScheduleByIDHandler())
+	v1secured.POST("/freeze-windows", s.createFreezeWindowHandler())
+	v1secured.DELETE("/freeze-windows/:id", s.deleteFreezeWindowHandler())
+	v1secured.GET("/freeze-windows", s.listFreezeWindowsHandler())
+	v1secured.GET("/freeze-windows/:id", s.getFreezeWindowByIDHandler())
 	v1secured.GET("/apps", s.listAppsHandler())
 	v1secured.POST("/apps", s.createAppHandler())
 	v1secured.PATCH("/apps/:id", s.updateAppHandler())
@@ -111,12 +154,19 @@ func newHttpServer(options ServerOptions, root startup.ServerRoot) *server {
 	// FIXME: in the future, maybe all the API should be accessible, but not before https://github.com/YuukanOO/seelf/issues/45
 	v1securedAllowApi := v1.Group("", s.authenticate(true))
 	v1securedAllowApi.GET("/apps/:id", s.getAppByIDHandler())
+	v1securedAllowApi.GET("/apps/:id/environments/:env/services/:name/logs", s.getServiceLogsHandler())
 	v1securedAllowApi.POST("/apps/:id/deployments", s.queueDeploymentHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments", s.listDeploymentsByAppHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments/:number", s.getDeploymentByIDHandler())
 	v1securedAllowApi.POST("/apps/:id/deployments/:number/redeploy", s.redeployHandler())
 	v1securedAllowApi.POST("/apps/:id/deployments/:number/promote", s.promoteHandler())
+	v1securedAllowApi.POST("/apps/:id/deployments/:number/cancel", s.cancelDeploymentHandler())
+	v1securedAllowApi.POST("/apps/:id/deployments/:number/approve", s.approveDeploymentHandler())
+	v1securedAllowApi.POST("/apps/:id/deployments/:number/reject", s.rejectDeploymentHandler())
 	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs", s.getDeploymentLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/logs/stream", s.streamDeploymentLogsHandler())
+	v1securedAllowApi.GET("/apps/:id/deployments/:number/timeline", s.getDeploymentTimelineHandler())
+	v1securedAllowApi.GET("/events", s.streamEventsHandler())
 
 	s.useSPA()
 
@@ -1,26 +1,65 @@
 package serve
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
 	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/list_sessions"
 	"github.com/YuukanOO/seelf/internal/auth/app/login"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_sessions"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/http"
+	"github.com/YuukanOO/seelf/pkg/validate"
 	"github.com/gin-contrib/sessions"
 	"github.com/gin-gonic/gin"
 )
 
 func (s *server) createSessionHandler() gin.HandlerFunc {
 	return http.Bind(s, func(ctx *gin.Context, cmd login.Command) error {
+		emailKey := "email:" + strings.ToLower(strings.TrimSpace(cmd.Email))
+		keys := []string{"ip:" + ctx.ClientIP(), emailKey}
+
+		if wait, allowed := s.attempts.Allowed(keys...); !allowed {
+			return tooManyRequests(ctx, wait)
+		}
+
 		sess := sessions.Default(ctx)
 		context := ctx.Request.Context()
 		uid, err := bus.Send(s.bus, context, cmd)
 
+		if err != nil {
+			// Malformed requests are rejected before checking credentials, they do not count as an attempt
+			if isInvalidCredentials(err) {
+				s.loginFailed(ctx, cmd.Email, keys...)
+			}
+
+			return err
+		}
+
+		// Only the email key is cleared, the ip one decays by itself so a successful
+		// login on a known account does not reset attempts made on other ones.
+		s.attempts.Succeeded(emailKey)
+
+		sid, err := bus.Send(s.bus, context, create_session.Command{
+			User:      uid,
+			IP:        ctx.ClientIP(),
+			UserAgent: ctx.Request.UserAgent(),
+		})
+
 		if err != nil {
 			return err
 		}
 
-		// Everything went good, let's set the user cookie
-		sess.Set(userSessionKey, uid)
+		// Everything went good, let's set the session cookie
+		sess.Set(userSessionKey, sid)
 
 		if err = sess.Save(); err != nil {
 			return err
@@ -40,8 +79,16 @@ func (s *server) createSessionHandler() gin.HandlerFunc {
 
 func (s *server) deleteSessionHandler() gin.HandlerFunc {
 	return http.Send(s, func(ctx *gin.Context) error {
+		c := ctx.Request.Context()
 		sess := sessions.Default(ctx)
 
+		if _, err := bus.Send(s.bus, c, revoke_session.Command{
+			ID:   string(domain.CurrentSession(c).Get("")),
+			User: string(domain.CurrentUser(c).MustGet()),
+		}); err != nil && !errors.Is(err, apperr.ErrNotFound) {
+			return err
+		}
+
 		sess.Clear()
 
 		if err := sess.Save(); err != nil {
@@ -51,3 +98,82 @@ func (s *server) deleteSessionHandler() gin.HandlerFunc {
 		return http.NoContent(ctx)
 	})
 }
+
+func (s *server) listSessionsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		c := ctx.Request.Context()
+		result, err := bus.Send(s.bus, c, list_sessions.Query{
+			User:    string(domain.CurrentUser(c).MustGet()),
+			Current: string(domain.CurrentSession(c).Get("")),
+		})
+
+		if err != nil {
+			return err
+		}
+
+		return http.Ok(ctx, result)
+	})
+}
+
+func (s *server) deleteSessionByIDHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		c := ctx.Request.Context()
+
+		if _, err := bus.Send(s.bus, c, revoke_session.Command{
+			ID:   ctx.Param("id"),
+			User: string(domain.CurrentUser(c).MustGet()),
+		}); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+func (s *server) deleteOtherSessionsHandler() gin.HandlerFunc {
+	return http.Send(s, func(ctx *gin.Context) error {
+		c := ctx.Request.Context()
+		cmd := revoke_sessions.Command{
+			User: string(domain.CurrentUser(c).MustGet()),
+		}
+
+		if current, isSet := domain.CurrentSession(c).TryGet(); isSet {
+			cmd.Except.Set(string(current))
+		}
+
+		if _, err := bus.Send(s.bus, c, cmd); err != nil {
+			return err
+		}
+
+		return http.NoContent(ctx)
+	})
+}
+
+// Checks if the given error was returned because of wrong credentials.
+func isInvalidCredentials(err error) bool {
+	var fields validate.FieldErrors
+	return errors.As(err, &fields) && errors.Is(fields["email"], domain.ErrInvalidEmailOrPassword)
+}
+
+// Register a failed authentication attempt for the given keys and log it so
+// that tools such as fail2ban could act on it.
+func (s *server) loginFailed(ctx *gin.Context, email string, keys ...string) {
+	failure := s.attempts.Failed(keys...)
+
+	s.logger.Warnw("failed authentication attempt",
+		"ip", ctx.ClientIP(),
+		"email", email,
+		"attempts", failure.Attempts)
+
+	if failure.Locked {
+		s.logger.Warnw("too many failed authentication attempts, temporarily locked",
+			"ip", ctx.ClientIP(),
+			"email", email)
+	}
+}
+
+// Sets the Retry-After header and returns the appropriate error.
+func tooManyRequests(ctx *gin.Context, wait time.Duration) error {
+	ctx.Header("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	return http.ErrTooManyRequests
+}
@@ -1,7 +1,10 @@
 package serve
 
 import (
+	"time"
+
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
 	"github.com/YuukanOO/seelf/internal/deployment/app/reconfigure_target"
@@ -122,7 +125,8 @@ func (s *server) listTargetsHandler() gin.HandlerFunc {
 
 func (s *server) getTargetByIDHandler() gin.HandlerFunc {
 	return http.Send(s, func(c *gin.Context) error {
-		target, err := bus.Send(s.bus, c.Request.Context(), get_target.Query{
+		ctx := c.Request.Context()
+		target, err := bus.Send(s.bus, ctx, get_target.Query{
 			ID: c.Param("id"),
 		})
 
@@ -130,6 +134,21 @@ func (s *server) getTargetByIDHandler() gin.HandlerFunc {
 			return err
 		}
 
+		status, err := bus.Send(s.bus, ctx, get_runtime_status.Query{
+			TargetID: target.ID,
+		})
+
+		// An unreachable target is reported in the status itself instead of failing the whole request.
+		if err != nil {
+			status = get_runtime_status.Status{
+				Services:  []get_runtime_status.Service{},
+				Error:     monad.Value(err.Error()),
+				CheckedAt: time.Now().UTC(),
+			}
+		}
+
+		target.Runtime.Set(status)
+
 		return http.Ok(c, target)
 	})
 }
@@ -0,0 +1,70 @@
+package startup
+
+import (
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	authinfra "github.com/YuukanOO/seelf/internal/auth/infra"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+)
+
+type (
+	// Represents a services root used by administrative commands. Unlike the server one,
+	// it does not start background jobs and does not require an admin account to exist.
+	ConsoleRoot interface {
+		Cleanup() error
+		Bus() bus.Dispatcher
+		Logger() log.Logger
+		UsersReader() domain.UsersReader
+	}
+
+	ConsoleOptions interface {
+		authinfra.Options
+
+		Dialect() storage.Dialect
+		ConnectionString() string
+	}
+
+	consoleRoot struct {
+		bus         bus.Bus
+		logger      log.Logger
+		db          *sqldb.Database
+		usersReader domain.UsersReader
+	}
+)
+
+// Instantiate a new console root, opening the database and registering services
+// needed to manage users.
+func Console(options ConsoleOptions, logger log.Logger) (ConsoleRoot, error) {
+	c := &consoleRoot{
+		logger: logger,
+		bus:    memory.NewBus(),
+	}
+
+	db, err := sqldb.Open(options.Dialect(), options.ConnectionString(), c.logger, c.bus)
+
+	if err != nil {
+		return nil, err
+	}
+
+	c.db = db
+
+	if c.usersReader, err = authinfra.Setup(options, c.logger, c.db, c.bus); err != nil {
+		_ = c.db.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *consoleRoot) Cleanup() error {
+	c.logger.Debug("cleaning console services")
+
+	return c.db.Close()
+}
+
+func (c *consoleRoot) Bus() bus.Dispatcher             { return c.bus }
+func (c *consoleRoot) Logger() log.Logger              { return c.logger }
+func (c *consoleRoot) UsersReader() domain.UsersReader { return c.usersReader }
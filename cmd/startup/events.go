@@ -0,0 +1,25 @@
+package startup
+
+import (
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+)
+
+const jobQueueChanged = "job_queue_changed"
+
+type jobQueueChangedData struct {
+	JobID   string        `json:"job_id,omitempty"`
+	Message string        `json:"message,omitempty"`
+	Change  bus.JobChange `json:"change"`
+}
+
+// Relay changes made to the jobs queue to events subscribers.
+func relayJobs(b bus.Bus, outbox bus.Outbox, r *relay.Relay) {
+	relay.Forward(b, outbox, r, jobQueueChanged, func(evt bus.JobQueueChanged) any {
+		return jobQueueChangedData{
+			JobID:   evt.JobID,
+			Message: evt.Message,
+			Change:  evt.Change,
+		}
+	})
+}
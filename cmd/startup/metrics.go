@@ -0,0 +1,58 @@
+package startup
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+)
+
+// Dispatcher used by the scheduler to measure how long it takes to process jobs.
+type jobsDispatcher struct {
+	bus.Dispatcher
+	duration *prometheus.HistogramVec
+}
+
+// Builds a new registry with default go and process collectors.
+func newMetricsRegistry() *prometheus.Registry {
+	registry := prometheus.NewRegistry()
+
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return registry
+}
+
+// Wraps the given dispatcher to observe the processing duration of requests sent by
+// the scheduler.
+func observeJobs(registerer prometheus.Registerer, dispatcher bus.Dispatcher) (bus.Dispatcher, error) {
+	d := &jobsDispatcher{
+		Dispatcher: dispatcher,
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "seelf_scheduler_job_duration_seconds",
+			Help:    "Time taken to process a scheduled job by message name and result (success or error).",
+			Buckets: []float64{0.1, 0.5, 1, 5, 10, 30, 60, 120, 300, 600, 1800},
+		}, []string{"message", "result"}),
+	}
+
+	return d, registerer.Register(d.duration)
+}
+
+func (d *jobsDispatcher) Send(ctx context.Context, msg bus.Request) (any, error) {
+	start := time.Now()
+	result, err := d.Dispatcher.Send(ctx, msg)
+
+	status := "success"
+
+	if err != nil {
+		status = "error"
+	}
+
+	d.duration.WithLabelValues(msg.Name_(), status).Observe(time.Since(start).Seconds())
+
+	return result, err
+}
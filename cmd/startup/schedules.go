@@ -0,0 +1,71 @@
+package startup
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/trigger_schedules"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/lease"
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+// Interval at which due deployment schedules are evaluated. Cron expressions have a
+// minute precision so there is no need to check more often.
+const schedulesTickInterval = 30 * time.Second
+
+// Periodically evaluates deployment schedules until stopped. Only the leader evaluates
+// them so a schedule does not trigger one deployment per instance.
+type schedulesTicker struct {
+	dispatcher bus.Dispatcher
+	logger     log.Logger
+	elector    *lease.Elector
+	done       chan struct{}
+	stopped    chan struct{}
+}
+
+func newSchedulesTicker(dispatcher bus.Dispatcher, logger log.Logger, elector *lease.Elector) *schedulesTicker {
+	return &schedulesTicker{
+		dispatcher: dispatcher,
+		logger:     logger,
+		elector:    elector,
+	}
+}
+
+func (t *schedulesTicker) Start() {
+	t.done = make(chan struct{})
+	t.stopped = make(chan struct{})
+
+	go func() {
+		defer close(t.stopped)
+
+		ticker := time.NewTicker(schedulesTickInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-t.done:
+				return
+			case now := <-ticker.C:
+				if !t.elector.IsLeader() {
+					continue
+				}
+
+				if _, err := bus.Send(t.dispatcher, context.Background(), trigger_schedules.Command{
+					At: now.UTC(),
+				}); err != nil {
+					t.logger.Errorw("error while triggering deployment schedules", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+func (t *schedulesTicker) Stop() {
+	if t.done == nil {
+		return
+	}
+
+	close(t.done)
+	<-t.stopped
+}
@@ -14,14 +14,24 @@ import (
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
+	"github.com/YuukanOO/seelf/internal/deployment/app/fail_interrupted_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/app/notify"
+	"github.com/YuukanOO/seelf/internal/deployment/app/report_commit_status"
 	deploymentdomain "github.com/YuukanOO/seelf/internal/deployment/domain"
 	deploymentinfra "github.com/YuukanOO/seelf/internal/deployment/infra"
+	deploymentevents "github.com/YuukanOO/seelf/internal/deployment/infra/events"
+	deploymentmetrics "github.com/YuukanOO/seelf/internal/deployment/infra/metrics"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/bus/memory"
-	bussqlite "github.com/YuukanOO/seelf/pkg/bus/sqlite"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+	bussqldb "github.com/YuukanOO/seelf/pkg/bus/sqldb"
+	"github.com/YuukanOO/seelf/pkg/lease"
+	leasesqldb "github.com/YuukanOO/seelf/pkg/lease/sqldb"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 type (
@@ -32,27 +42,39 @@ type (
 		Logger() log.Logger
 		UsersReader() domain.UsersReader
 		ScheduledJobsStore() bus.ScheduledJobsStore
+		Metrics() *prometheus.Registry
+		Events() *relay.Relay
 	}
 
 	ServerOptions interface {
 		deploymentinfra.Options
+		authinfra.Options
 
 		AppExposedUrl() monad.Maybe[deploymentdomain.Url]
 		DefaultEmail() string
 		DefaultPassword() string
 		RunnersPollInterval() time.Duration
+		RunnersJobsRetention() time.Duration
 		RunnersDeploymentCount() int
 		RunnersCleanupCount() int
+		Dialect() storage.Dialect
 		ConnectionString() string
+		InstanceID() string
+		LeaseDuration() time.Duration
 	}
 
 	serverRoot struct {
 		bus            bus.Bus
 		logger         log.Logger
-		db             *sqlite.Database
+		db             *sqldb.Database
 		usersReader    domain.UsersReader
 		schedulerStore bus.ScheduledJobsStore
 		scheduler      bus.RunnableScheduler
+		outbox         bus.RunnableOutbox
+		schedules      *schedulesTicker
+		elector        *lease.Elector
+		metrics        *prometheus.Registry
+		events         *relay.Relay
 	}
 )
 
@@ -60,7 +82,8 @@ type (
 // needed by the server.
 func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 	s := &serverRoot{
-		logger: logger,
+		logger:  logger,
+		metrics: newMetricsRegistry(),
 	}
 
 	// embedded.NewBus()
@@ -68,7 +91,7 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 
 	s.bus = memory.NewBus()
 
-	db, err := sqlite.Open(options.ConnectionString(), s.logger, s.bus)
+	db, err := sqldb.Open(options.Dialect(), options.ConnectionString(), s.logger, s.bus)
 
 	if err != nil {
 		return nil, err
@@ -76,18 +99,52 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 
 	s.db = db
 
-	s.schedulerStore = bussqlite.NewScheduledJobsStore(s.db)
+	s.schedulerStore = bussqldb.NewScheduledJobsStore(s.db, options.InstanceID(), options.LeaseDuration())
 
 	if err = s.schedulerStore.Setup(); err != nil {
 		return nil, err
 	}
 
-	s.scheduler = bus.NewScheduler(s.schedulerStore, s.logger, s.bus, options.RunnersPollInterval(),
+	outboxStore := bussqldb.NewOutboxStore(s.db, options.InstanceID(), options.LeaseDuration())
+
+	if err = outboxStore.Setup(); err != nil {
+		return nil, err
+	}
+
+	s.outbox = bus.NewOutbox(outboxStore, s.logger, options.RunnersPollInterval(), options.RunnersJobsRetention(), bus.DefaultOutboxRetryPolicy)
+
+	relayStore := bussqldb.NewRelayStore(s.db)
+
+	if err = relayStore.Setup(); err != nil {
+		return nil, err
+	}
+
+	s.events = relay.New(relayStore, s.logger, options.RunnersPollInterval())
+
+	jobsDispatcher, err := observeJobs(s.metrics, s.bus)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.metrics.Register(bussqldb.NewMetricsCollector(s.db)); err != nil {
+		return nil, err
+	}
+
+	jobsStore, err := bussqldb.ObserveAttempts(s.metrics, s.schedulerStore)
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.scheduler = bus.NewScheduler(jobsStore, s.logger, jobsDispatcher, options.RunnersPollInterval(), options.RunnersJobsRetention(),
 		bus.WorkerGroup{
+			Name:     "deployment",
 			Size:     options.RunnersDeploymentCount(),
 			Messages: []string{deploy.Command{}.Name_()},
 		},
 		bus.WorkerGroup{
+			Name: "cleanup",
 			Size: options.RunnersCleanupCount(),
 			Messages: []string{
 				cleanup_app.Command{}.Name_(),
@@ -95,12 +152,14 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 				configure_target.Command{}.Name_(),
 				cleanup_target.Command{}.Name_(),
 				delete_target.Command{}.Name_(),
+				notify.Command{}.Name_(),
+				report_commit_status.Command{}.Name_(),
 			},
 		},
 	)
 
 	// Setup auth infrastructure
-	if s.usersReader, err = authinfra.Setup(s.logger, s.db, s.bus); err != nil {
+	if s.usersReader, err = authinfra.Setup(options, s.logger, s.db, s.bus); err != nil {
 		return nil, err
 	}
 
@@ -111,10 +170,25 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 		s.db,
 		s.bus,
 		s.scheduler,
+		s.outbox,
+		s.usersReader,
 	); err != nil {
 		return nil, err
 	}
 
+	if err = deploymentmetrics.Setup(s.metrics, s.db, s.bus, s.outbox); err != nil {
+		return nil, err
+	}
+
+	leasesStore := leasesqldb.NewLeasesStore(s.db)
+
+	if err = leasesStore.Setup(); err != nil {
+		return nil, err
+	}
+
+	relayJobs(s.bus, s.outbox, s.events)
+	deploymentevents.Setup(s.bus, s.outbox, s.events)
+
 	// Create the first account if needed
 	uid, err := bus.Send(s.bus, context.Background(), create_first_account.Command{
 		Email:    options.DefaultEmail(),
@@ -125,22 +199,42 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 		return nil, err
 	}
 
-	// Create the target needed to expose seelf itself and manage certificates if needed
-	if exposedUrl, isSet := options.AppExposedUrl().TryGet(); isSet {
-		container := exposedUrl.User().Get("")
+	// Only one instance, the leader, should perform cluster wide maintenance tasks. Since the
+	// leadership may be taken over at any time, they are run each time this instance is elected.
+	s.elector = lease.NewElector(leasesStore, s.logger, "leader", options.InstanceID(), options.LeaseDuration(),
+		func(ctx context.Context) {
+			if _, err := bus.Send(s.bus, ctx, fail_interrupted_deployments.Command{}); err != nil {
+				s.logger.Errorw("error while failing interrupted deployments", "error", err)
+			}
 
-		s.logger.Infow("exposing seelf container using the local target, creating it if needed, the container may restart once done",
-			"container", container)
+			// Create the target needed to expose seelf itself and manage certificates if needed
+			exposedUrl, isSet := options.AppExposedUrl().TryGet()
 
-		if _, err := bus.Send(s.bus, domain.WithUserID(context.Background(), domain.UserID(uid)), expose_seelf_container.Command{
-			Container: container,
-			Url:       exposedUrl.WithoutUser().String(),
-		}); err != nil {
-			return nil, err
-		}
-	}
+			if !isSet {
+				return
+			}
+
+			container := exposedUrl.User().Get("")
+
+			s.logger.Infow("exposing seelf container using the local target, creating it if needed, the container may restart once done",
+				"container", container)
+
+			if _, err := bus.Send(s.bus, domain.WithUserID(ctx, domain.UserID(uid)), expose_seelf_container.Command{
+				Container: container,
+				Url:       exposedUrl.WithoutUser().String(),
+			}); err != nil {
+				s.logger.Errorw("error while exposing seelf container", "error", err)
+			}
+		})
+
+	s.elector.Start()
 
 	s.scheduler.Start()
+	s.outbox.Start()
+	s.events.Start()
+
+	s.schedules = newSchedulesTicker(s.bus, s.logger, s.elector)
+	s.schedules.Start()
 
 	return s, nil
 }
@@ -148,7 +242,11 @@ func Server(options ServerOptions, logger log.Logger) (ServerRoot, error) {
 func (s *serverRoot) Cleanup() error {
 	s.logger.Debug("cleaning server services")
 
+	s.schedules.Stop()
+	s.events.Stop()
+	s.outbox.Stop()
 	s.scheduler.Stop()
+	s.elector.Stop()
 
 	return s.db.Close()
 }
@@ -157,3 +255,5 @@ func (s *serverRoot) Bus() bus.Dispatcher                        { return s.bus
 func (s *serverRoot) Logger() log.Logger                         { return s.logger }
 func (s *serverRoot) UsersReader() domain.UsersReader            { return s.usersReader }
 func (s *serverRoot) ScheduledJobsStore() bus.ScheduledJobsStore { return s.schedulerStore }
+func (s *serverRoot) Metrics() *prometheus.Registry              { return s.metrics }
+func (s *serverRoot) Events() *relay.Relay                       { return s.events }
@@ -0,0 +1,255 @@
+package users
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/YuukanOO/seelf/cmd/startup"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_user"
+	"github.com/YuukanOO/seelf/internal/auth/app/list_users"
+	"github.com/YuukanOO/seelf/internal/auth/app/refresh_api_key"
+	"github.com/YuukanOO/seelf/internal/auth/app/update_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var errPasswordRequired = errors.New("a password is required")
+
+type Options interface {
+	startup.ConsoleOptions
+}
+
+// Returns the root users command used to manage user accounts, mostly useful to
+// recover access to a seelf instance.
+func Root(opts Options, logger log.Logger) *cobra.Command {
+	usersCmd := &cobra.Command{
+		Use:   "users",
+		Short: "Manage user accounts",
+	}
+
+	usersCmd.AddCommand(
+		listCmd(opts, logger),
+		createCmd(opts, logger),
+		resetPasswordCmd(opts, logger),
+		rotateKeyCmd(opts, logger),
+	)
+
+	return usersCmd
+}
+
+func listCmd(opts Options, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List registered users",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withConsole(opts, logger, func(ctx context.Context, root startup.ConsoleRoot) error {
+				users, err := bus.Send(root.Bus(), ctx, list_users.Query{})
+
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "ID\tEMAIL\tADMIN\tREGISTERED AT")
+
+				for _, user := range users {
+					fmt.Fprintf(w, "%s\t%s\t%t\t%s\n", user.ID, user.Email, user.Admin, user.RegisteredAt.Format(time.RFC3339))
+				}
+
+				return w.Flush()
+			})
+		},
+	}
+}
+
+func createCmd(opts Options, logger log.Logger) *cobra.Command {
+	var (
+		email, password string
+		admin           bool
+	)
+
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a new user account",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := passwordOrPrompt(cmd, password)
+
+			if err != nil {
+				return err
+			}
+
+			return withConsole(opts, logger, func(ctx context.Context, root startup.ConsoleRoot) error {
+				uid, err := bus.Send(root.Bus(), ctx, create_user.Command{
+					Email:    email,
+					Password: password,
+					Admin:    admin,
+				})
+
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "user %s created with id %s\n", email, uid)
+
+				return nil
+			})
+		},
+	}
+
+	createCmd.Flags().StringVarP(&email, "email", "e", "", "email of the user to create")
+	createCmd.Flags().StringVarP(&password, "password", "p", "", "password of the user to create, prompted if not set")
+	createCmd.Flags().BoolVar(&admin, "admin", false, "grant administrative privileges, such as overriding a deployment freeze")
+	_ = createCmd.MarkFlagRequired("email")
+
+	return createCmd
+}
+
+func resetPasswordCmd(opts Options, logger log.Logger) *cobra.Command {
+	var password string
+
+	resetCmd := &cobra.Command{
+		Use:   "reset-password <email>",
+		Short: "Reset the password of a user, revoking all of its sessions",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			password, err := passwordOrPrompt(cmd, password)
+
+			if err != nil {
+				return err
+			}
+
+			return withConsole(opts, logger, func(ctx context.Context, root startup.ConsoleRoot) error {
+				uid, err := userIDFromEmail(ctx, root, args[0])
+
+				if err != nil {
+					return err
+				}
+
+				if _, err = bus.Send(root.Bus(), ctx, update_user.Command{
+					ID:       string(uid),
+					Password: monad.Value(password),
+				}); err != nil {
+					return err
+				}
+
+				fmt.Fprintf(cmd.OutOrStdout(), "password of %s has been reset\n", args[0])
+
+				return nil
+			})
+		},
+	}
+
+	resetCmd.Flags().StringVarP(&password, "password", "p", "", "new password, prompted if not set")
+
+	return resetCmd
+}
+
+func rotateKeyCmd(opts Options, logger log.Logger) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate-key <email>",
+		Short: "Generate a new API key for a user and print it",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return withConsole(opts, logger, func(ctx context.Context, root startup.ConsoleRoot) error {
+				uid, err := userIDFromEmail(ctx, root, args[0])
+
+				if err != nil {
+					return err
+				}
+
+				key, err := bus.Send(root.Bus(), ctx, refresh_api_key.Command{
+					ID: string(uid),
+				})
+
+				if err != nil {
+					return err
+				}
+
+				fmt.Fprintln(cmd.OutOrStdout(), key)
+
+				return nil
+			})
+		},
+	}
+}
+
+// Instantiate the console root and call the given function with it, cleaning everything
+// once done.
+func withConsole(opts Options, logger log.Logger, fn func(context.Context, startup.ConsoleRoot) error) error {
+	root, err := startup.Console(opts, logger)
+
+	if err != nil {
+		return err
+	}
+
+	defer root.Cleanup()
+
+	return fn(context.Background(), root)
+}
+
+func userIDFromEmail(ctx context.Context, root startup.ConsoleRoot, value string) (domain.UserID, error) {
+	email, err := domain.EmailFrom(value)
+
+	if err != nil {
+		return "", err
+	}
+
+	user, err := root.UsersReader().GetByEmail(ctx, email)
+
+	if err != nil {
+		return "", fmt.Errorf("could not find user %s: %w", value, err)
+	}
+
+	return user.ID(), nil
+}
+
+// Returns the given password if not empty or prompt for it. When the standard input
+// is not a terminal, the password is read from the first line so it could be piped.
+func passwordOrPrompt(cmd *cobra.Command, password string) (string, error) {
+	if password != "" {
+		return password, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+
+	if !term.IsTerminal(fd) {
+		line, err := bufio.NewReader(cmd.InOrStdin()).ReadString('\n')
+
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", err
+		}
+
+		return requiredPassword(strings.TrimRight(line, "\r\n"))
+	}
+
+	fmt.Fprint(cmd.OutOrStdout(), "Password: ")
+	value, err := term.ReadPassword(fd)
+	fmt.Fprintln(cmd.OutOrStdout())
+
+	if err != nil {
+		return "", err
+	}
+
+	return requiredPassword(string(value))
+}
+
+func requiredPassword(password string) (string, error) {
+	if password == "" {
+		return "", errPasswordRequired
+	}
+
+	return password, nil
+}
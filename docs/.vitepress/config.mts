@@ -75,6 +75,10 @@ export default defineConfig({
             text: "Jobs",
             link: "/reference/jobs",
           },
+          {
+            text: "Notifications",
+            link: "/reference/notifications",
+          },
           {
             text: "API",
             link: "/reference/api",
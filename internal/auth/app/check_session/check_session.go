@@ -0,0 +1,49 @@
+package check_session
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Check the given session is still valid, marking it as seen and returning
+// the user it belongs to.
+type Command struct {
+	bus.Command[string]
+
+	ID string `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.check_session" }
+
+func Handler(
+	reader domain.SessionsReader,
+	writer domain.SessionsWriter,
+	timeouts domain.SessionTimeouts,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		session, err := reader.GetByID(ctx, domain.SessionID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		// Expired sessions are removed right away
+		expiredErr := session.Seen(timeouts)
+
+		if expiredErr != nil {
+			session.Revoke()
+		}
+
+		if err = writer.Write(ctx, &session); err != nil {
+			return "", err
+		}
+
+		if expiredErr != nil {
+			return "", expiredErr
+		}
+
+		return string(session.UserID()), nil
+	}
+}
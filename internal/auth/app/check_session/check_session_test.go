@@ -0,0 +1,75 @@
+package check_session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/check_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_CheckSession(t *testing.T) {
+	timeouts := domain.SessionTimeouts{Lifetime: time.Hour, Idle: time.Minute}
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, check_session.Command],
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return check_session.Handler(context.SessionsStore, context.SessionsStore, timeouts), context.Dispatcher
+	}
+
+	t.Run("should require an existing session", func(t *testing.T) {
+		handler, _ := arrange(t)
+
+		_, err := handler(context.Background(), check_session.Command{
+			ID: "an_unknown_session",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should return the user id of a valid session", func(t *testing.T) {
+		user := fixture.User()
+		session := domain.NewSession(user.ID(), domain.SessionClient{}, timeouts)
+		handler, _ := arrange(t, fixture.WithUsers(&user), fixture.WithSessions(&session))
+
+		uid, err := handler(context.Background(), check_session.Command{
+			ID: string(session.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, string(user.ID()), uid)
+	})
+
+	t.Run("should revoke an expired session", func(t *testing.T) {
+		user := fixture.User()
+		session := domain.NewSession(user.ID(), domain.SessionClient{}, domain.SessionTimeouts{
+			Lifetime: time.Millisecond,
+			Idle:     time.Millisecond,
+		})
+		handler, dispatcher := arrange(t, fixture.WithUsers(&user), fixture.WithSessions(&session))
+
+		time.Sleep(2 * time.Millisecond)
+
+		_, err := handler(context.Background(), check_session.Command{
+			ID: string(session.ID()),
+		})
+
+		assert.ErrorIs(t, domain.ErrSessionExpired, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.Is[domain.SessionRevoked](t, dispatcher.Signals()[0])
+
+		_, err = handler(context.Background(), check_session.Command{
+			ID: string(session.ID()),
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
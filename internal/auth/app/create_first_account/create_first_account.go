@@ -42,7 +42,7 @@ func Handler(
 			return "", err
 		}
 
-		// Nothing to do if there is already a user.
+		// Nothing to do if there is already an administrator.
 		if err == nil {
 			return string(user.ID()), nil
 		}
@@ -73,13 +73,19 @@ func Handler(
 			return "", err
 		}
 
-		// Here the email uniqueness is guaranteed to be true since we check for the user counts above.
-		user, err = domain.NewUser(domain.NewEmailRequirement(email, true), password, key)
+		// Users may exist without any administrator, for example if it has been created from the CLI.
+		emailRequirement, err := reader.CheckEmailAvailability(ctx, email)
 
 		if err != nil {
 			return "", err
 		}
 
+		user, err = domain.NewAdmin(emailRequirement, password, key)
+
+		if err != nil {
+			return "", validate.Wrap(err, "email")
+		}
+
 		if err = writer.Write(ctx, &user); err != nil {
 			return "", err
 		}
@@ -24,8 +24,8 @@ func Test_CreateFirstAccount(t *testing.T) {
 		return create_first_account.Handler(context.UsersStore, context.UsersStore, crypto.NewBCryptHasher(), crypto.NewKeyGenerator()), context.Dispatcher
 	}
 
-	t.Run("should returns the existing user id if a user already exists", func(t *testing.T) {
-		existingUser := fixture.User()
+	t.Run("should returns the existing user id if an administrator already exists", func(t *testing.T) {
+		existingUser := fixture.User(fixture.AsAdmin())
 		handler, dispatcher := arrange(t, fixture.WithUsers(&existingUser))
 
 		uid, err := handler(context.Background(), create_first_account.Command{})
@@ -83,6 +83,22 @@ func Test_CreateFirstAccount(t *testing.T) {
 			Password:     assert.NotZero(t, registered.Password),
 			RegisteredAt: assert.NotZero(t, registered.RegisteredAt),
 			Key:          assert.NotZero(t, registered.Key),
+			Admin:        true,
 		}, registered)
 	})
+
+	t.Run("should creates the administrator account even if other users exist", func(t *testing.T) {
+		existingUser := fixture.User()
+		handler, dispatcher := arrange(t, fixture.WithUsers(&existingUser))
+
+		uid, err := handler(context.Background(), create_first_account.Command{
+			Email:    "admin@example.com",
+			Password: "admin",
+		})
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, string(existingUser.ID()), uid)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.True(t, assert.Is[domain.UserRegistered](t, dispatcher.Signals()[0]).Admin)
+	})
 }
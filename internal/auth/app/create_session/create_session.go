@@ -0,0 +1,37 @@
+package create_session
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Creates a new server side session for an already authenticated user.
+type Command struct {
+	bus.Command[string]
+
+	User      string `json:"-"`
+	IP        string `json:"-"`
+	UserAgent string `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.create_session" }
+
+func Handler(
+	writer domain.SessionsWriter,
+	timeouts domain.SessionTimeouts,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		session := domain.NewSession(domain.UserID(cmd.User), domain.SessionClient{
+			IP:        cmd.IP,
+			UserAgent: cmd.UserAgent,
+		}, timeouts)
+
+		if err := writer.Write(ctx, &session); err != nil {
+			return "", err
+		}
+
+		return string(session.ID()), nil
+	}
+}
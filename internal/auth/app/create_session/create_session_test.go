@@ -0,0 +1,54 @@
+package create_session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_CreateSession(t *testing.T) {
+	timeouts := domain.SessionTimeouts{Lifetime: time.Hour, Idle: time.Minute}
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, create_session.Command],
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return create_session.Handler(context.SessionsStore, timeouts), context.Dispatcher
+	}
+
+	t.Run("should create a new session for the given user", func(t *testing.T) {
+		user := fixture.User()
+		handler, dispatcher := arrange(t, fixture.WithUsers(&user))
+
+		sid, err := handler(context.Background(), create_session.Command{
+			User:      string(user.ID()),
+			IP:        "127.0.0.1",
+			UserAgent: "Firefox",
+		})
+
+		assert.Nil(t, err)
+		assert.NotZero(t, sid)
+
+		assert.HasLength(t, 1, dispatcher.Signals())
+		created := assert.Is[domain.SessionCreated](t, dispatcher.Signals()[0])
+
+		assert.Equal(t, domain.SessionCreated{
+			ID:   domain.SessionID(sid),
+			User: user.ID(),
+			Client: domain.SessionClient{
+				IP:        "127.0.0.1",
+				UserAgent: "Firefox",
+			},
+			CreatedAt: assert.NotZero(t, created.CreatedAt),
+			ExpiresAt: created.CreatedAt.Add(timeouts.Idle),
+		}, created)
+	})
+}
@@ -0,0 +1,75 @@
+package create_user
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Creates a new user account.
+type Command struct {
+	bus.Command[string]
+
+	Email    string `json:"email"`
+	Password string `json:"password"`
+	Admin    bool   `json:"admin"`
+}
+
+func (Command) Name_() string { return "auth.command.create_user" }
+
+func Handler(
+	reader domain.UsersReader,
+	writer domain.UsersWriter,
+	hasher domain.PasswordHasher,
+	generator domain.KeyGenerator,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var email domain.Email
+
+		if err := validate.Struct(validate.Of{
+			"email":    validate.Value(cmd.Email, &email, domain.EmailFrom),
+			"password": validate.Field(cmd.Password, strings.Required),
+		}); err != nil {
+			return "", err
+		}
+
+		emailRequirement, err := reader.CheckEmailAvailability(ctx, email)
+
+		if err != nil {
+			return "", err
+		}
+
+		password, err := hasher.Hash(cmd.Password)
+
+		if err != nil {
+			return "", err
+		}
+
+		key, err := generator.Generate()
+
+		if err != nil {
+			return "", err
+		}
+
+		newUser := domain.NewUser
+
+		if cmd.Admin {
+			newUser = domain.NewAdmin
+		}
+
+		user, err := newUser(emailRequirement, password, key)
+
+		if err != nil {
+			return "", validate.Wrap(err, "email")
+		}
+
+		if err = writer.Write(ctx, &user); err != nil {
+			return "", err
+		}
+
+		return string(user.ID()), nil
+	}
+}
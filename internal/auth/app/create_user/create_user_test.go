@@ -0,0 +1,89 @@
+package create_user_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/create_user"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreateUser(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, create_user.Command],
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return create_user.Handler(context.UsersStore, context.UsersStore, crypto.NewBCryptHasher(), crypto.NewKeyGenerator()), context.Dispatcher
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		handler, _ := arrange(t)
+
+		_, err := handler(context.Background(), create_user.Command{})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"email":    domain.ErrInvalidEmail,
+			"password": strings.ErrRequired,
+		}, err)
+	})
+
+	t.Run("should fail if the email is already taken", func(t *testing.T) {
+		existingUser := fixture.User(fixture.WithEmail("john@doe.com"))
+		handler, _ := arrange(t, fixture.WithUsers(&existingUser))
+
+		_, err := handler(context.Background(), create_user.Command{
+			Email:    "john@doe.com",
+			Password: "apassword",
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"email": domain.ErrEmailAlreadyTaken,
+		}, err)
+	})
+
+	t.Run("should create the user if everything is good", func(t *testing.T) {
+		existingUser := fixture.User()
+		handler, dispatcher := arrange(t, fixture.WithUsers(&existingUser))
+
+		uid, err := handler(context.Background(), create_user.Command{
+			Email:    "jane@doe.com",
+			Password: "apassword",
+		})
+
+		assert.Nil(t, err)
+		assert.NotZero(t, uid)
+
+		assert.HasLength(t, 1, dispatcher.Signals())
+		registered := assert.Is[domain.UserRegistered](t, dispatcher.Signals()[0])
+
+		assert.Equal(t, domain.UserRegistered{
+			ID:           domain.UserID(uid),
+			Email:        "jane@doe.com",
+			Password:     assert.NotZero(t, registered.Password),
+			Key:          assert.NotZero(t, registered.Key),
+			RegisteredAt: assert.NotZero(t, registered.RegisteredAt),
+		}, registered)
+	})
+	t.Run("should create an administrator if asked to", func(t *testing.T) {
+		handler, dispatcher := arrange(t)
+
+		_, err := handler(context.Background(), create_user.Command{
+			Email:    "jane@doe.com",
+			Password: "apassword",
+			Admin:    true,
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.True(t, assert.Is[domain.UserRegistered](t, dispatcher.Signals()[0]).Admin)
+	})
+}
@@ -0,0 +1,29 @@
+package list_sessions
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve active sessions of a user.
+	Query struct {
+		bus.Query[[]Session]
+
+		User    string `json:"-"`
+		Current string `json:"-"` // Session ID used to flag the current session in the results
+	}
+
+	Session struct {
+		ID         string    `json:"id"`
+		IP         string    `json:"ip"`
+		UserAgent  string    `json:"user_agent"`
+		CreatedAt  time.Time `json:"created_at"`
+		LastSeenAt time.Time `json:"last_seen_at"`
+		ExpiresAt  time.Time `json:"expires_at"`
+		Current    bool      `json:"current"`
+	}
+)
+
+func (Query) Name_() string { return "auth.query.list_sessions" }
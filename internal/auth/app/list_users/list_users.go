@@ -0,0 +1,23 @@
+package list_users
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type (
+	// Retrieve all registered users.
+	Query struct {
+		bus.Query[[]User]
+	}
+
+	User struct {
+		ID           string    `json:"id"`
+		Email        string    `json:"email"`
+		Admin        bool      `json:"admin"`
+		RegisteredAt time.Time `json:"registered_at"`
+	}
+)
+
+func (Query) Name_() string { return "auth.query.list_users" }
@@ -0,0 +1,41 @@
+package revoke_session
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Revoke a session of the given user.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID   string `json:"-"`
+	User string `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.revoke_session" }
+
+func Handler(
+	reader domain.SessionsReader,
+	writer domain.SessionsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		session, err := reader.GetByID(ctx, domain.SessionID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		// Do not leak the existence of sessions owned by someone else
+		if session.UserID() != domain.UserID(cmd.User) {
+			return bus.Unit, apperr.ErrNotFound
+		}
+
+		session.Revoke()
+
+		return bus.Unit, writer.Write(ctx, &session)
+	}
+}
@@ -0,0 +1,69 @@
+package revoke_session_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_RevokeSession(t *testing.T) {
+	timeouts := domain.SessionTimeouts{Lifetime: time.Hour, Idle: time.Hour}
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, revoke_session.Command],
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return revoke_session.Handler(context.SessionsStore, context.SessionsStore), context.Dispatcher
+	}
+
+	t.Run("should require an existing session", func(t *testing.T) {
+		handler, _ := arrange(t)
+
+		_, err := handler(context.Background(), revoke_session.Command{
+			ID: "an_unknown_session",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should not allow to revoke a session of another user", func(t *testing.T) {
+		john := fixture.User()
+		jane := fixture.User()
+		session := domain.NewSession(jane.ID(), domain.SessionClient{}, timeouts)
+		handler, _ := arrange(t, fixture.WithUsers(&john, &jane), fixture.WithSessions(&session))
+
+		_, err := handler(context.Background(), revoke_session.Command{
+			ID:   string(session.ID()),
+			User: string(john.ID()),
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should revoke the session", func(t *testing.T) {
+		user := fixture.User()
+		session := domain.NewSession(user.ID(), domain.SessionClient{}, timeouts)
+		handler, dispatcher := arrange(t, fixture.WithUsers(&user), fixture.WithSessions(&session))
+
+		_, err := handler(context.Background(), revoke_session.Command{
+			ID:   string(session.ID()),
+			User: string(user.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.Equal(t, domain.SessionRevoked{
+			ID:   session.ID(),
+			User: user.ID(),
+		}, assert.Is[domain.SessionRevoked](t, dispatcher.Signals()[0]))
+	})
+}
@@ -0,0 +1,20 @@
+package revoke_sessions
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a user changes its password, revoke every other sessions so a stolen one
+// could not be used anymore.
+func OnUserPasswordChangedHandler(writer domain.SessionsWriter) bus.SignalHandler[domain.UserPasswordChanged] {
+	return func(ctx context.Context, evt domain.UserPasswordChanged) error {
+		return writer.RevokeSessions(ctx, domain.RevokeCriteria{
+			User:   monad.Value(evt.ID),
+			Except: domain.CurrentSession(ctx),
+		})
+	}
+}
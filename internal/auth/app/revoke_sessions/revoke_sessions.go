@@ -0,0 +1,35 @@
+package revoke_sessions
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Revoke all sessions of the given user, except the one given if any.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	User   string              `json:"-"`
+	Except monad.Maybe[string] `json:"-"`
+}
+
+func (Command) Name_() string { return "auth.command.revoke_sessions" }
+
+func Handler(
+	writer domain.SessionsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		var criteria = domain.RevokeCriteria{
+			User: monad.Value(domain.UserID(cmd.User)),
+		}
+
+		if except, isSet := cmd.Except.TryGet(); isSet {
+			criteria.Except.Set(domain.SessionID(except))
+		}
+
+		return bus.Unit, writer.RevokeSessions(ctx, criteria)
+	}
+}
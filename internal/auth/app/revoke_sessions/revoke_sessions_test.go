@@ -0,0 +1,70 @@
+package revoke_sessions_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_sessions"
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+func Test_RevokeSessions(t *testing.T) {
+	timeouts := domain.SessionTimeouts{Lifetime: time.Hour, Idle: time.Hour}
+
+	t.Run("should revoke all sessions of a user except the given one", func(t *testing.T) {
+		john := fixture.User()
+		jane := fixture.User()
+		current := domain.NewSession(john.ID(), domain.SessionClient{}, timeouts)
+		other := domain.NewSession(john.ID(), domain.SessionClient{}, timeouts)
+		janeSession := domain.NewSession(jane.ID(), domain.SessionClient{}, timeouts)
+		ctx := fixture.PrepareDatabase(t,
+			fixture.WithUsers(&john, &jane),
+			fixture.WithSessions(&current, &other, &janeSession),
+		)
+		handler := revoke_sessions.Handler(ctx.SessionsStore)
+
+		_, err := handler(context.Background(), revoke_sessions.Command{
+			User:   string(john.ID()),
+			Except: monad.Value(string(current.ID())),
+		})
+
+		assert.Nil(t, err)
+
+		_, err = ctx.SessionsStore.GetByID(context.Background(), current.ID())
+		assert.Nil(t, err)
+
+		_, err = ctx.SessionsStore.GetByID(context.Background(), other.ID())
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+
+		_, err = ctx.SessionsStore.GetByID(context.Background(), janeSession.ID())
+		assert.Nil(t, err)
+	})
+
+	t.Run("should revoke other sessions when the user password has changed", func(t *testing.T) {
+		user := fixture.User()
+		current := domain.NewSession(user.ID(), domain.SessionClient{}, timeouts)
+		other := domain.NewSession(user.ID(), domain.SessionClient{}, timeouts)
+		ctx := fixture.PrepareDatabase(t,
+			fixture.WithUsers(&user),
+			fixture.WithSessions(&current, &other),
+		)
+		handler := revoke_sessions.OnUserPasswordChangedHandler(ctx.SessionsStore)
+
+		err := handler(domain.WithSessionID(context.Background(), current.ID()), domain.UserPasswordChanged{
+			ID: user.ID(),
+		})
+
+		assert.Nil(t, err)
+
+		_, err = ctx.SessionsStore.GetByID(context.Background(), current.ID())
+		assert.Nil(t, err)
+
+		_, err = ctx.SessionsStore.GetByID(context.Background(), other.ID())
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+}
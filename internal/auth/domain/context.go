@@ -8,7 +8,10 @@ import (
 
 type contextKey string
 
-const currentUserContextKey contextKey = "current-user"
+const (
+	currentUserContextKey    contextKey = "current-user"
+	currentSessionContextKey contextKey = "current-session"
+)
 
 // Attach the given UserID to the given context. Will be used everywhere when trying
 // to determine which user is currently executing an action.
@@ -28,3 +31,22 @@ func CurrentUser(ctx context.Context) (m monad.Maybe[UserID]) {
 
 	return m
 }
+
+// Attach the given SessionID to the given context. Only set when the user has been
+// authenticated using a session and not an API key.
+func WithSessionID(ctx context.Context, sid SessionID) context.Context {
+	return context.WithValue(ctx, currentSessionContextKey, sid)
+}
+
+// Retrieve the current session attached to the given context if any.
+func CurrentSession(ctx context.Context) (m monad.Maybe[SessionID]) {
+	val := ctx.Value(currentSessionContextKey)
+
+	if val == nil {
+		return m
+	}
+
+	m.Set(val.(SessionID))
+
+	return m
+}
@@ -24,4 +24,18 @@ func Test_AuthContext(t *testing.T) {
 
 		assert.Equal(t, monad.None[domain.UserID](), uid)
 	})
+
+	t.Run("should embed a session id into the context", func(t *testing.T) {
+		sid := domain.SessionID("a_session_id")
+
+		newCtx := domain.WithSessionID(context.Background(), sid)
+
+		assert.Equal(t, sid, domain.CurrentSession(newCtx).MustGet())
+	})
+
+	t.Run("should returns an empty monad.Maybe if no session id has been attached to the context", func(t *testing.T) {
+		sid := domain.CurrentSession(context.Background())
+
+		assert.Equal(t, monad.None[domain.SessionID](), sid)
+	})
 }
@@ -0,0 +1,185 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+// Minimum interval between two last seen updates of a session to avoid writing
+// on every single request.
+const sessionSeenResolution = time.Minute
+
+var ErrSessionExpired = apperr.New("session_expired")
+
+type (
+	SessionID string
+
+	// Server side session of a user, referenced by its ID in the session cookie.
+	Session struct {
+		event.Emitter
+
+		id         SessionID
+		user       UserID
+		client     SessionClient
+		createdAt  time.Time
+		lastSeenAt time.Time
+		expiresAt  time.Time
+	}
+
+	// Information about the client which has initiated a session.
+	SessionClient struct {
+		IP        string
+		UserAgent string
+	}
+
+	// Configure how long a session will stay valid.
+	SessionTimeouts struct {
+		Lifetime time.Duration // Maximum lifetime of a session, whatever the user activity
+		Idle     time.Duration // Expires the session if the user has not been seen for this duration
+	}
+
+	// Criteria used to revoke multiple sessions at once.
+	RevokeCriteria struct {
+		User   monad.Maybe[UserID]
+		Except monad.Maybe[SessionID]
+	}
+
+	SessionsReader interface {
+		GetByID(context.Context, SessionID) (Session, error)
+	}
+
+	SessionsWriter interface {
+		RevokeSessions(context.Context, RevokeCriteria) error
+		Write(context.Context, ...*Session) error
+	}
+
+	SessionCreated struct {
+		bus.Notification
+
+		ID        SessionID
+		User      UserID
+		Client    SessionClient
+		CreatedAt time.Time
+		ExpiresAt time.Time
+	}
+
+	SessionSeen struct {
+		bus.Notification
+
+		ID        SessionID
+		SeenAt    time.Time
+		ExpiresAt time.Time
+	}
+
+	SessionRevoked struct {
+		bus.Notification
+
+		ID   SessionID
+		User UserID
+	}
+)
+
+func (SessionCreated) Name_() string { return "auth.event.session_created" }
+func (SessionSeen) Name_() string    { return "auth.event.session_seen" }
+func (SessionRevoked) Name_() string { return "auth.event.session_revoked" }
+
+// Creates a new session for the given user.
+func NewSession(user UserID, client SessionClient, timeouts SessionTimeouts) (s Session) {
+	now := time.Now().UTC()
+
+	s.apply(SessionCreated{
+		ID:        id.New[SessionID](),
+		User:      user,
+		Client:    client,
+		CreatedAt: now,
+		ExpiresAt: timeouts.expiresAt(now, now),
+	})
+
+	return s
+}
+
+// Recreates a session from a storage driver
+func SessionFrom(scanner storage.Scanner) (s Session, err error) {
+	err = scanner.Scan(
+		&s.id,
+		&s.user,
+		&s.client.IP,
+		&s.client.UserAgent,
+		&s.createdAt,
+		&s.lastSeenAt,
+		&s.expiresAt,
+	)
+
+	return s, err
+}
+
+// Mark the session as used right now, extending its expiration date based on the
+// given timeouts. Returns ErrSessionExpired if the session has already expired.
+func (s *Session) Seen(timeouts SessionTimeouts) error {
+	now := time.Now().UTC()
+
+	if !now.Before(s.expiresAt) || !now.Before(s.createdAt.Add(timeouts.Lifetime)) {
+		return ErrSessionExpired
+	}
+
+	if now.Sub(s.lastSeenAt) < sessionSeenResolution {
+		return nil
+	}
+
+	s.apply(SessionSeen{
+		ID:        s.id,
+		SeenAt:    now,
+		ExpiresAt: timeouts.expiresAt(s.createdAt, now),
+	})
+
+	return nil
+}
+
+// Revoke the session, the user will have to log in again.
+func (s *Session) Revoke() {
+	s.apply(SessionRevoked{
+		ID:   s.id,
+		User: s.user,
+	})
+}
+
+func (s *Session) ID() SessionID        { return s.id }
+func (s *Session) UserID() UserID       { return s.user }
+func (s *Session) ExpiresAt() time.Time { return s.expiresAt }
+
+func (s *Session) apply(e event.Event) {
+	switch evt := e.(type) {
+	case SessionCreated:
+		s.id = evt.ID
+		s.user = evt.User
+		s.client = evt.Client
+		s.createdAt = evt.CreatedAt
+		s.lastSeenAt = evt.CreatedAt
+		s.expiresAt = evt.ExpiresAt
+	case SessionSeen:
+		s.lastSeenAt = evt.SeenAt
+		s.expiresAt = evt.ExpiresAt
+	}
+
+	event.Store(s, e)
+}
+
+// Compute the expiration date of a session created at the given date and last seen
+// at the given one.
+func (t SessionTimeouts) expiresAt(createdAt, seenAt time.Time) time.Time {
+	idle := seenAt.Add(t.Idle)
+	absolute := createdAt.Add(t.Lifetime)
+
+	if idle.Before(absolute) {
+		return idle
+	}
+
+	return absolute
+}
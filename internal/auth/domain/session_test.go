@@ -0,0 +1,78 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/assert"
+)
+
+func Test_Session(t *testing.T) {
+	timeouts := domain.SessionTimeouts{
+		Lifetime: time.Hour,
+		Idle:     time.Minute,
+	}
+
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			uid    domain.UserID = "a_user_id"
+			client               = domain.SessionClient{IP: "127.0.0.1", UserAgent: "Firefox"}
+		)
+
+		s := domain.NewSession(uid, client, timeouts)
+
+		assert.NotZero(t, s.ID())
+		assert.Equal(t, uid, s.UserID())
+
+		created := assert.EventIs[domain.SessionCreated](t, &s, 0)
+
+		assert.Equal(t, domain.SessionCreated{
+			ID:        s.ID(),
+			User:      uid,
+			Client:    client,
+			CreatedAt: assert.NotZero(t, created.CreatedAt),
+			ExpiresAt: created.CreatedAt.Add(timeouts.Idle),
+		}, created)
+	})
+
+	t.Run("should never expire after its lifetime", func(t *testing.T) {
+		s := domain.NewSession("uid", domain.SessionClient{}, domain.SessionTimeouts{
+			Lifetime: time.Minute,
+			Idle:     time.Hour,
+		})
+
+		created := assert.EventIs[domain.SessionCreated](t, &s, 0)
+
+		assert.Equal(t, created.CreatedAt.Add(time.Minute), s.ExpiresAt())
+	})
+
+	t.Run("should not raise an event if seen recently", func(t *testing.T) {
+		s := domain.NewSession("uid", domain.SessionClient{}, timeouts)
+
+		assert.Nil(t, s.Seen(timeouts))
+		assert.HasNEvents(t, 1, &s)
+	})
+
+	t.Run("should fail when seen after its expiration", func(t *testing.T) {
+		short := domain.SessionTimeouts{Lifetime: time.Millisecond, Idle: time.Millisecond}
+		s := domain.NewSession("uid", domain.SessionClient{}, short)
+
+		time.Sleep(2 * time.Millisecond)
+
+		assert.ErrorIs(t, domain.ErrSessionExpired, s.Seen(short))
+	})
+
+	t.Run("could be revoked", func(t *testing.T) {
+		s := domain.NewSession("uid", domain.SessionClient{}, timeouts)
+
+		s.Revoke()
+
+		revoked := assert.EventIs[domain.SessionRevoked](t, &s, 1)
+
+		assert.Equal(t, domain.SessionRevoked{
+			ID:   s.ID(),
+			User: "uid",
+		}, revoked)
+	})
+}
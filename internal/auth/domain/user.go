@@ -28,6 +28,7 @@ type (
 		password     PasswordHash
 		email        Email
 		key          APIKey
+		admin        bool
 		registeredAt time.Time
 	}
 
@@ -40,7 +41,13 @@ type (
 		Generate() (APIKey, error)
 	}
 
+	// Exposes the users privileges to other modules.
+	AdminsReader interface {
+		IsAdmin(context.Context, UserID) (bool, error)
+	}
+
 	UsersReader interface {
+		AdminsReader
 		GetAdminUser(context.Context) (User, error)
 		GetIDFromAPIKey(context.Context, APIKey) (UserID, error)
 		CheckEmailAvailability(context.Context, Email, ...UserID) (EmailRequirement, error)
@@ -59,6 +66,7 @@ type (
 		Email        Email
 		Password     PasswordHash
 		Key          APIKey
+		Admin        bool
 		RegisteredAt time.Time
 	}
 
@@ -89,7 +97,18 @@ func (UserEmailChanged) Name_() string    { return "auth.event.user_email_change
 func (UserPasswordChanged) Name_() string { return "auth.event.user_password_changed" }
 func (UserAPIKeyChanged) Name_() string   { return "auth.event.user_api_key_changed" }
 
-func NewUser(emailRequirement EmailRequirement, password PasswordHash, key APIKey) (u User, err error) {
+// Builds a new user without administrative privileges.
+func NewUser(emailRequirement EmailRequirement, password PasswordHash, key APIKey) (User, error) {
+	return newUser(emailRequirement, password, key, false)
+}
+
+// Builds a new administrator, allowed to perform sensitive actions such as overriding
+// a deployment freeze.
+func NewAdmin(emailRequirement EmailRequirement, password PasswordHash, key APIKey) (User, error) {
+	return newUser(emailRequirement, password, key, true)
+}
+
+func newUser(emailRequirement EmailRequirement, password PasswordHash, key APIKey, admin bool) (u User, err error) {
 	email, err := emailRequirement.Met()
 
 	if err != nil {
@@ -102,6 +121,7 @@ func NewUser(emailRequirement EmailRequirement, password PasswordHash, key APIKe
 		Password:     password,
 		RegisteredAt: time.Now().UTC(),
 		Key:          key,
+		Admin:        admin,
 	})
 
 	return u, nil
@@ -114,6 +134,7 @@ func UserFrom(scanner storage.Scanner) (u User, err error) {
 		&u.email,
 		&u.password,
 		&u.key,
+		&u.admin,
 		&u.registeredAt,
 	)
 
@@ -166,6 +187,7 @@ func (u *User) HasAPIKey(key APIKey) {
 
 func (u *User) ID() UserID             { return u.id }
 func (u *User) Password() PasswordHash { return u.password }
+func (u *User) IsAdmin() bool          { return u.admin }
 
 func (u *User) apply(e event.Event) {
 	switch evt := e.(type) {
@@ -175,6 +197,7 @@ func (u *User) apply(e event.Event) {
 		u.password = evt.Password
 		u.registeredAt = evt.RegisteredAt
 		u.key = evt.Key
+		u.admin = evt.Admin
 	case UserEmailChanged:
 		u.email = evt.Email
 	case UserPasswordChanged:
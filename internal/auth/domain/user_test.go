@@ -36,6 +36,15 @@ func Test_User(t *testing.T) {
 			Key:          key,
 			RegisteredAt: assert.NotZero(t, registeredEvent.RegisteredAt),
 		}, registeredEvent)
+		assert.False(t, u.IsAdmin())
+	})
+
+	t.Run("could be created as an administrator", func(t *testing.T) {
+		u, err := domain.NewAdmin(domain.NewEmailRequirement("some@email.com", true), "someHashedPassword", "someapikey")
+
+		assert.Nil(t, err)
+		assert.True(t, u.IsAdmin())
+		assert.True(t, assert.EventIs[domain.UserRegistered](t, &u, 0).Admin)
 	})
 
 	t.Run("should fail if trying to change for a non available email", func(t *testing.T) {
@@ -9,23 +9,26 @@ import (
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
-	auth "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
+	auth "github.com/YuukanOO/seelf/internal/auth/infra/sqldb"
 	"github.com/YuukanOO/seelf/pkg/bus/spy"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/ostools"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
 )
 
 type (
 	seed struct {
-		users []*domain.User
+		users    []*domain.User
+		sessions []*domain.Session
 	}
 
 	Context struct {
-		Context    context.Context // If users has been seeded, will be authenticated as the first one
-		Dispatcher spy.Dispatcher
-		UsersStore auth.UsersStore
+		Context       context.Context // If users has been seeded, will be authenticated as the first one
+		Dispatcher    spy.Dispatcher
+		UsersStore    auth.UsersStore
+		SessionsStore auth.SessionsStore
 	}
 
 	SeedBuilder func(*seed)
@@ -43,7 +46,13 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 		Dispatcher: spy.NewDispatcher(),
 	}
 
-	db, err := sqlite.Open(cfg.ConnectionString(), must.Panic(log.NewLogger()), result.Dispatcher)
+	dsn := cfg.ConnectionString()
+
+	if cfg.Dialect() == postgres.Dialect {
+		dsn = postgres.PrepareTestSchema(t, dsn)
+	}
+
+	db, err := sqldb.Open(cfg.Dialect(), dsn, must.Panic(log.NewLogger()), result.Dispatcher)
 
 	if err != nil {
 		t.Fatal(err)
@@ -59,6 +68,7 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 	}
 
 	result.UsersStore = auth.NewUsersStore(db)
+	result.SessionsStore = auth.NewSessionsStore(db)
 
 	// Seed the database
 	var s seed
@@ -75,6 +85,12 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 		result.Context = domain.WithUserID(result.Context, s.users[0].ID()) // The first created user will be used as the authenticated one
 	}
 
+	if len(s.sessions) > 0 {
+		if err := result.SessionsStore.Write(result.Context, s.sessions...); err != nil {
+			t.Fatal(err)
+		}
+	}
+
 	// Reset the dispatcher after seeding
 	result.Dispatcher.Reset()
 
@@ -86,3 +102,9 @@ func WithUsers(users ...*domain.User) SeedBuilder {
 		s.users = users
 	}
 }
+
+func WithSessions(sessions ...*domain.Session) SeedBuilder {
+	return func(s *seed) {
+		s.sessions = sessions
+	}
+}
@@ -2,6 +2,7 @@ package fixture_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/auth/fixture"
@@ -15,6 +16,7 @@ func Test_PrepareDatabase(t *testing.T) {
 		assert.NotNil(t, ctx)
 		assert.NotNil(t, ctx.Dispatcher)
 		assert.NotNil(t, ctx.UsersStore)
+		assert.NotNil(t, ctx.SessionsStore)
 		assert.HasLength(t, 0, ctx.Dispatcher.Signals())
 		assert.HasLength(t, 0, ctx.Dispatcher.Requests())
 	})
@@ -27,4 +29,16 @@ func Test_PrepareDatabase(t *testing.T) {
 
 		assert.Equal(t, user1.ID(), domain.CurrentUser(ctx.Context).Get(""))
 	})
+
+	t.Run("should seed sessions", func(t *testing.T) {
+		user := fixture.User()
+		session := domain.NewSession(user.ID(), domain.SessionClient{}, domain.SessionTimeouts{Lifetime: time.Hour, Idle: time.Hour})
+
+		ctx := fixture.PrepareDatabase(t, fixture.WithUsers(&user), fixture.WithSessions(&session))
+
+		saved, err := ctx.SessionsStore.GetByID(ctx.Context, session.ID())
+
+		assert.Nil(t, err)
+		assert.Equal(t, user.ID(), saved.UserID())
+	})
 }
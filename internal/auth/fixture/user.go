@@ -13,6 +13,7 @@ type (
 		email        domain.Email
 		passwordHash domain.PasswordHash
 		apiKey       domain.APIKey
+		admin        bool
 	}
 
 	UserOptionBuilder func(*userOption)
@@ -29,7 +30,13 @@ func User(options ...UserOptionBuilder) domain.User {
 		o(&opts)
 	}
 
-	return must.Panic(domain.NewUser(
+	newUser := domain.NewUser
+
+	if opts.admin {
+		newUser = domain.NewAdmin
+	}
+
+	return must.Panic(newUser(
 		domain.NewEmailRequirement(opts.email, true),
 		opts.passwordHash,
 		opts.apiKey,
@@ -54,6 +61,12 @@ func WithPassword(password string, hasher domain.PasswordHasher) UserOptionBuild
 	}
 }
 
+func AsAdmin() UserOptionBuilder {
+	return func(o *userOption) {
+		o.admin = true
+	}
+}
+
 func WithAPIKey(apiKey domain.APIKey) UserOptionBuilder {
 	return func(o *userOption) {
 		o.apiKey = apiKey
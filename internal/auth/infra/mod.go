@@ -3,34 +3,54 @@ package infra
 import (
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/log"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
 
+	"github.com/YuukanOO/seelf/internal/auth/app/check_session"
 	"github.com/YuukanOO/seelf/internal/auth/app/create_first_account"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/create_user"
 	"github.com/YuukanOO/seelf/internal/auth/app/login"
 	"github.com/YuukanOO/seelf/internal/auth/app/refresh_api_key"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_session"
+	"github.com/YuukanOO/seelf/internal/auth/app/revoke_sessions"
 	"github.com/YuukanOO/seelf/internal/auth/app/update_user"
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/auth/infra/crypto"
-	authsqlite "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
+	authsqldb "github.com/YuukanOO/seelf/internal/auth/infra/sqldb"
 )
 
+type Options interface {
+	SessionTimeouts() domain.SessionTimeouts
+}
+
 // Setup the auth module
 func Setup(
+	opts Options,
 	logger log.Logger,
-	db *sqlite.Database,
+	db *sqldb.Database,
 	b bus.Bus,
 ) (domain.UsersReader, error) {
-	usersStore := authsqlite.NewUsersStore(db)
-	authQueryHandler := authsqlite.NewGateway(db)
+	usersStore := authsqldb.NewUsersStore(db)
+	sessionsStore := authsqldb.NewSessionsStore(db)
+	authQueryHandler := authsqldb.NewGateway(db)
 
 	passwordHasher := crypto.NewBCryptHasher()
 	keyGenerator := crypto.NewKeyGenerator()
 
 	bus.Register(b, login.Handler(usersStore, passwordHasher))
 	bus.Register(b, create_first_account.Handler(usersStore, usersStore, passwordHasher, keyGenerator))
+	bus.Register(b, create_user.Handler(usersStore, usersStore, passwordHasher, keyGenerator))
 	bus.Register(b, update_user.Handler(usersStore, usersStore, passwordHasher))
 	bus.Register(b, refresh_api_key.Handler(usersStore, usersStore, keyGenerator))
+	bus.Register(b, create_session.Handler(sessionsStore, opts.SessionTimeouts()))
+	bus.Register(b, check_session.Handler(sessionsStore, sessionsStore, opts.SessionTimeouts()))
+	bus.Register(b, revoke_session.Handler(sessionsStore, sessionsStore))
+	bus.Register(b, revoke_sessions.Handler(sessionsStore))
 	bus.Register(b, authQueryHandler.GetProfile)
+	bus.Register(b, authQueryHandler.ListSessions)
+	bus.Register(b, authQueryHandler.ListUsers)
+
+	bus.On(b, revoke_sessions.OnUserPasswordChangedHandler(sessionsStore))
 
-	return usersStore, db.Migrate(authsqlite.Migrations)
+	return usersStore, db.Migrate(authsqldb.Migrations)
 }
@@ -0,0 +1,100 @@
+package sqldb
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/app/get_profile"
+	"github.com/YuukanOO/seelf/internal/auth/app/list_sessions"
+	"github.com/YuukanOO/seelf/internal/auth/app/list_users"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+type gateway struct {
+	db *sqldb.Database
+}
+
+func NewGateway(db *sqldb.Database) *gateway {
+	return &gateway{db}
+}
+
+func (s *gateway) GetProfile(ctx context.Context, q get_profile.Query) (get_profile.Profile, error) {
+	return builder.
+		Query[get_profile.Profile](`
+			SELECT
+				id
+				,email
+				,registered_at
+				,api_key
+			FROM users
+			WHERE id = ?`, q.ID).
+		One(s.db, ctx, profileMapper)
+}
+
+func (s *gateway) ListUsers(ctx context.Context, q list_users.Query) ([]list_users.User, error) {
+	return builder.
+		Query[list_users.User](`
+			SELECT
+				id
+				,email
+				,admin
+				,registered_at
+			FROM users
+			ORDER BY registered_at ASC`).
+		All(s.db, ctx, userMapper)
+}
+
+func (s *gateway) ListSessions(ctx context.Context, q list_sessions.Query) ([]list_sessions.Session, error) {
+	return builder.
+		Query[list_sessions.Session](`
+			SELECT
+				id
+				,ip
+				,user_agent
+				,created_at
+				,last_seen_at
+				,expires_at
+				,id = ?
+			FROM sessions
+			WHERE user_id = ? AND expires_at > ?
+			ORDER BY last_seen_at DESC`, q.Current, q.User, time.Now().UTC()).
+		All(s.db, ctx, sessionMapper)
+}
+
+func profileMapper(row storage.Scanner) (p get_profile.Profile, err error) {
+	err = row.Scan(
+		&p.ID,
+		&p.Email,
+		&p.RegisteredAt,
+		&p.APIKey,
+	)
+
+	return p, err
+}
+
+func userMapper(row storage.Scanner) (u list_users.User, err error) {
+	err = row.Scan(
+		&u.ID,
+		&u.Email,
+		&u.Admin,
+		&u.RegisteredAt,
+	)
+
+	return u, err
+}
+
+func sessionMapper(row storage.Scanner) (s list_sessions.Session, err error) {
+	err = row.Scan(
+		&s.ID,
+		&s.IP,
+		&s.UserAgent,
+		&s.CreatedAt,
+		&s.LastSeenAt,
+		&s.ExpiresAt,
+		&s.Current,
+	)
+
+	return s, err
+}
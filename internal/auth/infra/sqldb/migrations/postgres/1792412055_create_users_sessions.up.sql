@@ -0,0 +1,24 @@
+CREATE TABLE users (
+    id TEXT NOT NULL
+    ,email TEXT NOT NULL
+    ,password_hash TEXT NOT NULL
+    ,api_key TEXT NOT NULL
+    ,registered_at TIMESTAMP NOT NULL
+    ,CONSTRAINT pk_users PRIMARY KEY(id)
+    ,CONSTRAINT unique_users_email UNIQUE(email)
+    ,CONSTRAINT unique_users_api_key UNIQUE(api_key)
+);
+
+CREATE TABLE sessions (
+    id TEXT NOT NULL
+    ,user_id TEXT NOT NULL
+    ,ip TEXT NOT NULL
+    ,user_agent TEXT NOT NULL
+    ,created_at TIMESTAMP NOT NULL
+    ,last_seen_at TIMESTAMP NOT NULL
+    ,expires_at TIMESTAMP NOT NULL
+    ,CONSTRAINT pk_sessions PRIMARY KEY(id)
+    ,CONSTRAINT fk_sessions_user_id FOREIGN KEY(user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_user_id ON sessions(user_id);
@@ -0,0 +1,4 @@
+ALTER TABLE users ADD admin BOOLEAN NOT NULL DEFAULT false;
+
+-- Until now, the first registered user was considered as the administrator
+UPDATE users SET admin = true WHERE id = (SELECT id FROM users ORDER BY registered_at ASC LIMIT 1);
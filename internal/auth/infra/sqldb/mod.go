@@ -0,0 +1,12 @@
+package sqldb
+
+import (
+	"embed"
+
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+)
+
+//go:embed migrations/*/*.sql
+var migrations embed.FS
+
+var Migrations = sqldb.NewMigrationsModule("auth", "migrations", migrations)
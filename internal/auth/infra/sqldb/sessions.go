@@ -0,0 +1,92 @@
+package sqldb
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+type (
+	SessionsStore interface {
+		domain.SessionsReader
+		domain.SessionsWriter
+	}
+
+	sessionsStore struct {
+		db *sqldb.Database
+	}
+)
+
+func NewSessionsStore(db *sqldb.Database) SessionsStore {
+	return &sessionsStore{db}
+}
+
+func (s *sessionsStore) GetByID(ctx context.Context, id domain.SessionID) (domain.Session, error) {
+	return builder.
+		Query[domain.Session](`
+			SELECT
+				id
+				,user_id
+				,ip
+				,user_agent
+				,created_at
+				,last_seen_at
+				,expires_at
+			FROM sessions
+			WHERE id = ?`, id).
+		One(s.db, ctx, domain.SessionFrom)
+}
+
+func (s *sessionsStore) RevokeSessions(ctx context.Context, criteria domain.RevokeCriteria) error {
+	return builder.
+		Command("DELETE FROM sessions WHERE TRUE").
+		S(
+			builder.MaybeValue(criteria.User, "AND user_id = ?"),
+			builder.MaybeValue(criteria.Except, "AND id != ?"),
+		).
+		Exec(s.db, ctx)
+}
+
+func (s *sessionsStore) Write(c context.Context, sessions ...*domain.Session) error {
+	return sqldb.WriteAndDispatch(s.db, c, sessions, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.SessionCreated:
+			// Take this opportunity to remove expired sessions so the table does not grow indefinitely
+			if err := builder.
+				Command("DELETE FROM sessions WHERE expires_at <= ?", time.Now().UTC()).
+				Exec(s.db, ctx); err != nil {
+				return err
+			}
+
+			return builder.
+				Insert("sessions", builder.Values{
+					"id":           evt.ID,
+					"user_id":      evt.User,
+					"ip":           evt.Client.IP,
+					"user_agent":   evt.Client.UserAgent,
+					"created_at":   evt.CreatedAt,
+					"last_seen_at": evt.CreatedAt,
+					"expires_at":   evt.ExpiresAt,
+				}).
+				Exec(s.db, ctx)
+		case domain.SessionSeen:
+			return builder.
+				Update("sessions", builder.Values{
+					"last_seen_at": evt.SeenAt,
+					"expires_at":   evt.ExpiresAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.SessionRevoked:
+			return builder.
+				Command("DELETE FROM sessions WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -1,12 +1,12 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type (
@@ -16,11 +16,11 @@ type (
 	}
 
 	usersStore struct {
-		db *sqlite.Database
+		db *sqldb.Database
 	}
 )
 
-func NewUsersStore(db *sqlite.Database) UsersStore {
+func NewUsersStore(db *sqldb.Database) UsersStore {
 	return &usersStore{db}
 }
 
@@ -32,8 +32,10 @@ func (s *usersStore) GetAdminUser(ctx context.Context) (domain.User, error) {
 			,email
 			,password_hash
 			,api_key
+			,admin
 			,registered_at
 		FROM users
+		WHERE admin = true
 		ORDER BY registered_at ASC
 		LIMIT 1`).
 		One(s.db, ctx, domain.UserFrom)
@@ -57,6 +59,7 @@ func (s *usersStore) GetByID(ctx context.Context, id domain.UserID) (u domain.Us
 				,email
 				,password_hash
 				,api_key
+				,admin
 				,registered_at
 			FROM users
 			WHERE id = ?`, id).
@@ -71,12 +74,19 @@ func (s *usersStore) GetByEmail(ctx context.Context, email domain.Email) (u doma
 				,email
 				,password_hash
 				,api_key
+				,admin
 				,registered_at
 			FROM users
 			WHERE email = ?`, email).
 		One(s.db, ctx, domain.UserFrom)
 }
 
+func (s *usersStore) IsAdmin(ctx context.Context, id domain.UserID) (bool, error) {
+	return builder.
+		Query[bool]("SELECT EXISTS(SELECT 1 FROM users WHERE id = ? AND admin = true)", id).
+		Extract(s.db, ctx)
+}
+
 func (s *usersStore) GetIDFromAPIKey(ctx context.Context, key domain.APIKey) (domain.UserID, error) {
 	return builder.
 		Query[domain.UserID]("SELECT id FROM users WHERE api_key = ?", key).
@@ -84,7 +94,7 @@ func (s *usersStore) GetIDFromAPIKey(ctx context.Context, key domain.APIKey) (do
 }
 
 func (s *usersStore) Write(c context.Context, users ...*domain.User) error {
-	return sqlite.WriteAndDispatch(s.db, c, users, func(ctx context.Context, e event.Event) error {
+	return sqldb.WriteAndDispatch(s.db, c, users, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.UserRegistered:
 			return builder.
@@ -93,6 +103,7 @@ func (s *usersStore) Write(c context.Context, users ...*domain.User) error {
 					"email":         evt.Email,
 					"password_hash": evt.Password,
 					"api_key":       evt.Key,
+					"admin":         evt.Admin,
 					"registered_at": evt.RegisteredAt,
 				}).
 				Exec(s.db, ctx)
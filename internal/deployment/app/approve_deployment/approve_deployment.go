@@ -0,0 +1,46 @@
+package approve_deployment
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Approve a deployment awaiting approval so it could be run. The current user must not
+// be the one who requested the deployment.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Command) Name_() string { return "deployment.command.approve_deployment" }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = depl.Approve(auth.CurrentUser(ctx).MustGet()); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = writer.Write(ctx, &depl); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, nil
+	}
+}
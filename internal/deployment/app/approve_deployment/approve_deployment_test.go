@@ -0,0 +1,104 @@
+package approve_deployment_test
+
+import (
+	"context"
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/approve_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_ApproveDeployment(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, approve_deployment.Command],
+		context.Context,
+		spy.Dispatcher,
+		*fixture.Context,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return approve_deployment.Handler(context.DeploymentsStore, context.DeploymentsStore), context.Context, context.Dispatcher, context
+	}
+
+	// The first user is the one authenticated when calling the handler.
+	seed := func(deployment *domain.Deployment, requestedBy func(approver, requester auth.User) auth.UserID) []fixture.SeedBuilder {
+		approver := authfixture.User()
+		requester := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(approver.ID()))
+		config := domain.NewEnvironmentConfig(target.ID())
+		config.RequireApproval()
+		app := fixture.App(
+			fixture.WithAppCreatedBy(approver.ID()),
+			fixture.WithEnvironmentConfig(config, domain.NewEnvironmentConfig(target.ID())),
+		)
+		*deployment = fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(requestedBy(approver, requester)),
+			fixture.FromApp(app),
+		)
+
+		return []fixture.SeedBuilder{
+			fixture.WithUsers(&approver, &requester),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(deployment),
+		}
+	}
+
+	t.Run("should fail if the deployment does not exist", func(t *testing.T) {
+		handler, ctx, _, _ := arrange(t)
+
+		_, err := handler(ctx, approve_deployment.Command{
+			AppID:            "some-app-id",
+			DeploymentNumber: 1,
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should fail if the current user has requested the deployment", func(t *testing.T) {
+		var deployment domain.Deployment
+		seeds := seed(&deployment, func(approver, _ auth.User) auth.UserID { return approver.ID() })
+		handler, ctx, dispatcher, _ := arrange(t, seeds...)
+
+		_, err := handler(ctx, approve_deployment.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.ErrorIs(t, domain.ErrSelfApprovalNotAllowed, err)
+		assert.HasLength(t, 0, dispatcher.Signals())
+	})
+
+	t.Run("should approve a deployment requested by another user", func(t *testing.T) {
+		var deployment domain.Deployment
+		seeds := seed(&deployment, func(_, requester auth.User) auth.UserID { return requester.ID() })
+		handler, ctx, dispatcher, context := arrange(t, seeds...)
+
+		r, err := handler(ctx, approve_deployment.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+		assert.HasLength(t, 2, dispatcher.Signals())
+
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.DeploymentStatusPending, changed.State.Status())
+
+		approved := assert.Is[domain.DeploymentApproved](t, dispatcher.Signals()[1])
+		assert.Equal(t, auth.CurrentUser(ctx).MustGet(), approved.Approved.By())
+
+		saved, err := context.DeploymentsStore.GetByID(ctx, deployment.ID())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DeploymentStatusPending, saved.State().Status())
+		assert.Equal(t, approved.Approved.By(), saved.Approved().MustGet().By())
+	})
+}
@@ -0,0 +1,50 @@
+package cancel_deployment
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Cancel a pending or running deployment. If the deployment is running, the deploy
+// job will be cancelled so the build, pull or up process are stopped.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Command) Name_() string { return "deployment.command.cancel_deployment" }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+	scheduler bus.Scheduler,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = depl.Cancel(); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = writer.Write(ctx, &depl); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, scheduler.Cancel(ctx, deploy.Command{
+			AppID:            cmd.AppID,
+			DeploymentNumber: cmd.DeploymentNumber,
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package cancel_deployment_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/cancel_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_CancelDeployment(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, cancel_deployment.Command],
+		context.Context,
+		spy.Dispatcher,
+		*dummyScheduler,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		scheduler := &dummyScheduler{}
+		return cancel_deployment.Handler(context.DeploymentsStore, context.DeploymentsStore, scheduler), context.Context, context.Dispatcher, scheduler
+	}
+
+	seed := func(deployment *domain.Deployment) []fixture.SeedBuilder {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		*deployment = fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.FromApp(app),
+		)
+
+		return []fixture.SeedBuilder{
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(deployment),
+		}
+	}
+
+	t.Run("should fail if the deployment does not exist", func(t *testing.T) {
+		handler, ctx, _, scheduler := arrange(t)
+
+		_, err := handler(ctx, cancel_deployment.Command{
+			AppID:            "some-app-id",
+			DeploymentNumber: 1,
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+		assert.HasLength(t, 0, scheduler.cancelled)
+	})
+
+	t.Run("should fail if the deployment has already ended", func(t *testing.T) {
+		var deployment domain.Deployment
+		seeds := seed(&deployment)
+		assert.Nil(t, deployment.HasStarted())
+		assert.Nil(t, deployment.HasEnded(nil, nil))
+		handler, ctx, _, scheduler := arrange(t, seeds...)
+
+		_, err := handler(ctx, cancel_deployment.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.ErrorIs(t, domain.ErrDeploymentAlreadyEnded, err)
+		assert.HasLength(t, 0, scheduler.cancelled)
+	})
+
+	t.Run("should cancel a running deployment and its deploy job", func(t *testing.T) {
+		var deployment domain.Deployment
+		seeds := seed(&deployment)
+		assert.Nil(t, deployment.HasStarted())
+		handler, ctx, dispatcher, scheduler := arrange(t, seeds...)
+
+		r, err := handler(ctx, cancel_deployment.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+		assert.HasLength(t, 1, dispatcher.Signals())
+
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.DeploymentStatusFailed, changed.State.Status())
+		assert.Equal(t, domain.ErrDeploymentCancelled.Error(), changed.State.ErrCode().MustGet())
+
+		assert.HasLength(t, 1, scheduler.cancelled)
+		assert.Equal[bus.Schedulable](t, deploy.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		}, scheduler.cancelled[0])
+	})
+}
+
+type dummyScheduler struct {
+	bus.Scheduler
+	cancelled []bus.Schedulable
+}
+
+func (s *dummyScheduler) Cancel(_ context.Context, msg bus.Schedulable) error {
+	s.cancelled = append(s.cancelled, msg)
+	return nil
+}
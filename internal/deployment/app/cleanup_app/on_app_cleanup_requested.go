@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
@@ -19,7 +20,7 @@ func OnAppCleanupRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[dom
 			TargetID:    string(evt.ProductionConfig.Target()),
 			From:        evt.ProductionConfig.Version(),
 			To:          now,
-		}, bus.WithPolicy(bus.JobPolicyCancellable)); err != nil {
+		}, bus.WithPolicy(bus.JobPolicyCancellable), bus.WithRetry(app.TargetJobRetryPolicy)); err != nil {
 			return err
 		}
 
@@ -29,6 +30,6 @@ func OnAppCleanupRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[dom
 			TargetID:    string(evt.StagingConfig.Target()),
 			From:        evt.StagingConfig.Version(),
 			To:          now,
-		}, bus.WithPolicy(bus.JobPolicyCancellable))
+		}, bus.WithPolicy(bus.JobPolicyCancellable), bus.WithRetry(app.TargetJobRetryPolicy))
 	}
 }
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
@@ -20,6 +21,6 @@ func OnAppEnvChangedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.Ap
 			Environment: string(evt.Environment),
 			From:        evt.OldConfig.Version(),
 			To:          time.Now().UTC(),
-		}, bus.WithPolicy(bus.JobPolicyCancellable))
+		}, bus.WithPolicy(bus.JobPolicyCancellable), bus.WithRetry(app.TargetJobRetryPolicy))
 	}
 }
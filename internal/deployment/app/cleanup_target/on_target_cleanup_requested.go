@@ -3,6 +3,7 @@ package cleanup_target
 import (
 	"context"
 
+	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
@@ -11,6 +12,6 @@ func OnTargetCleanupRequestedHandler(scheduler bus.Scheduler) bus.SignalHandler[
 	return func(ctx context.Context, evt domain.TargetCleanupRequested) error {
 		return scheduler.Queue(ctx, Command{
 			ID: string(evt.ID),
-		}, bus.WithPolicy(bus.JobPolicyCancellable))
+		}, bus.WithPolicy(bus.JobPolicyCancellable), bus.WithRetry(app.TargetJobRetryPolicy))
 	}
 }
@@ -2,6 +2,7 @@ package create_app
 
 import (
 	"context"
+	"time"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
@@ -20,16 +21,25 @@ type (
 		VersionControl monad.Maybe[VersionControl] `json:"version_control"`
 		Production     EnvironmentConfig           `json:"production"`
 		Staging        EnvironmentConfig           `json:"staging"`
+		LatestWins     bool                        `json:"latest_wins"` // Supersede older pending deployments when a new one is queued
 	}
 
 	EnvironmentConfig struct {
-		Target string                                    `json:"target"`
-		Vars   monad.Maybe[map[string]map[string]string] `json:"vars"`
+		Target           string                                    `json:"target"`
+		Vars             monad.Maybe[map[string]map[string]string] `json:"vars"`
+		Timeout          monad.Maybe[int]                          `json:"timeout"`           // Deployment timeout in seconds
+		RequiresApproval bool                                      `json:"requires_approval"` // Deployments must be approved by another user
 	}
 
 	VersionControl struct {
 		Url   string              `json:"url"`
 		Token monad.Maybe[string] `json:"token"`
+		Forge monad.Maybe[Forge]  `json:"forge"`
+	}
+
+	Forge struct {
+		Kind  string `json:"kind"`
+		Token string `json:"token"`
 	}
 )
 
@@ -41,10 +51,13 @@ func Handler(
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
 		var (
-			appname          domain.AppName
-			url              domain.Url
-			productionTarget = domain.TargetID(cmd.Production.Target)
-			stagingTarget    = domain.TargetID(cmd.Staging.Target)
+			appname           domain.AppName
+			url               domain.Url
+			forge             domain.Forge
+			productionTarget  = domain.TargetID(cmd.Production.Target)
+			stagingTarget     = domain.TargetID(cmd.Staging.Target)
+			productionTimeout monad.Maybe[time.Duration]
+			stagingTimeout    monad.Maybe[time.Duration]
 		)
 
 		if err := validate.Struct(validate.Of{
@@ -53,13 +66,18 @@ func Handler(
 				return validate.Struct(validate.Of{
 					"url":   validate.Value(config.Url, &url, domain.UrlFrom),
 					"token": validate.Maybe(config.Token, strings.Required),
+					"forge": validate.Maybe(config.Forge, func(f Forge) error {
+						return validate.Value(f, &forge, BuildForge)
+					}),
 				})
 			}),
 			"production": validate.Struct(validate.Of{
-				"target": validate.Field(cmd.Production.Target, strings.Required),
+				"target":  validate.Field(cmd.Production.Target, strings.Required),
+				"timeout": ValidateTimeout(cmd.Production.Timeout, &productionTimeout),
 			}),
 			"staging": validate.Struct(validate.Of{
-				"target": validate.Field(cmd.Staging.Target, strings.Required),
+				"target":  validate.Field(cmd.Staging.Target, strings.Required),
+				"timeout": ValidateTimeout(cmd.Staging.Timeout, &stagingTimeout),
 			}),
 		}); err != nil {
 			return "", err
@@ -68,8 +86,8 @@ func Handler(
 		productionRequirement, stagingRequirement, err := reader.CheckAppNamingAvailability(
 			ctx,
 			appname,
-			BuildEnvironmentConfig(productionTarget, cmd.Production.Vars),
-			BuildEnvironmentConfig(stagingTarget, cmd.Staging.Vars),
+			BuildEnvironmentConfig(productionTarget, cmd.Production.Vars, productionTimeout, cmd.Production.RequiresApproval),
+			BuildEnvironmentConfig(stagingTarget, cmd.Staging.Vars, stagingTimeout, cmd.Staging.RequiresApproval),
 		)
 
 		if err != nil {
@@ -102,9 +120,15 @@ func Handler(
 				vcs.Authenticated(token)
 			}
 
+			if cmdVCS.Forge.HasValue() {
+				vcs.ReportStatusesTo(forge)
+			}
+
 			_ = app.UseVersionControl(vcs)
 		}
 
+		_ = app.UseLatestWins(cmd.LatestWins)
+
 		if err := writer.Write(ctx, &app); err != nil {
 			return "", err
 		}
@@ -114,12 +138,53 @@ func Handler(
 }
 
 // Helper method to build a domain.EnvironmentConfig from a raw command value.
-func BuildEnvironmentConfig(target domain.TargetID, env monad.Maybe[map[string]map[string]string]) domain.EnvironmentConfig {
+func BuildEnvironmentConfig(
+	target domain.TargetID,
+	env monad.Maybe[map[string]map[string]string],
+	timeout monad.Maybe[time.Duration],
+	requiresApproval bool,
+) domain.EnvironmentConfig {
 	config := domain.NewEnvironmentConfig(target)
 
 	if vars, hasVars := env.TryGet(); hasVars {
 		config.HasEnvironmentVariables(domain.ServicesEnvFrom(vars))
 	}
 
+	if d, hasTimeout := timeout.TryGet(); hasTimeout {
+		config.HasTimeout(d)
+	}
+
+	if requiresApproval {
+		config.RequireApproval()
+	}
+
 	return config
 }
+
+// Validates an optional raw timeout (in seconds) and populates the target if set.
+func ValidateTimeout(value monad.Maybe[int], target *monad.Maybe[time.Duration]) error {
+	return validate.Maybe(value, func(seconds int) error {
+		d, err := domain.DeploymentTimeoutFrom(seconds)
+
+		if err != nil {
+			return err
+		}
+
+		target.Set(d)
+		return nil
+	})
+}
+
+// Validates and builds a domain.Forge from a raw command value.
+func BuildForge(forge Forge) (domain.Forge, error) {
+	var kind domain.ForgeKind
+
+	if err := validate.Struct(validate.Of{
+		"kind":  validate.Value(forge.Kind, &kind, domain.ForgeKindFrom),
+		"token": validate.Field(forge.Token, strings.Required),
+	}); err != nil {
+		return domain.Forge{}, err
+	}
+
+	return domain.NewForge(kind, forge.Token), nil
+}
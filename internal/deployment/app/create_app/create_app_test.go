@@ -42,6 +42,28 @@ func Test_CreateApp(t *testing.T) {
 		}, err)
 	})
 
+	t.Run("should require valid timeouts if set", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		id, err := handler(ctx, create_app.Command{
+			Name: "my-app",
+			Production: create_app.EnvironmentConfig{
+				Target:  "production-target",
+				Timeout: monad.Value(0),
+			},
+			Staging: create_app.EnvironmentConfig{
+				Target:  "staging-target",
+				Timeout: monad.Value(-60),
+			},
+		})
+
+		assert.Zero(t, id)
+		assert.ValidationError(t, validate.FieldErrors{
+			"production.timeout": domain.ErrInvalidDeploymentTimeout,
+			"staging.timeout":    domain.ErrInvalidDeploymentTimeout,
+		}, err)
+	})
+
 	t.Run("should fail if the name is already taken", func(t *testing.T) {
 		user := authfixture.User()
 		productionTarget := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
@@ -114,6 +136,10 @@ func Test_CreateApp(t *testing.T) {
 			VersionControl: monad.Value(create_app.VersionControl{
 				Url:   "https://somewhere.git",
 				Token: monad.Value("some-token"),
+				Forge: monad.Value(create_app.Forge{
+					Kind:  "gitea",
+					Token: "forge-token",
+				}),
 			}),
 		})
 
@@ -136,5 +162,6 @@ func Test_CreateApp(t *testing.T) {
 		assert.Equal(t, created.ID, versionControlConfigured.ID)
 		assert.Equal(t, "https://somewhere.git", versionControlConfigured.Config.Url().String())
 		assert.Equal(t, "some-token", versionControlConfigured.Config.Token().Get(""))
+		assert.Equal(t, domain.NewForge(domain.ForgeKindGitea, "forge-token"), versionControlConfigured.Config.Forge().Get(domain.Forge{}))
 	})
 }
@@ -0,0 +1,112 @@
+package create_freeze_window
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type (
+	// Create a new freeze window during which deployments are rejected. Without an
+	// application and an environment, it applies everywhere.
+	Command struct {
+		bus.Command[string]
+
+		Name        string                 `json:"name"`
+		AppID       monad.Maybe[string]    `json:"app_id"`
+		Environment monad.Maybe[string]    `json:"environment"`
+		Recurring   monad.Maybe[Recurring] `json:"recurring"`
+		Fixed       monad.Maybe[Fixed]     `json:"fixed"`
+	}
+
+	// Freeze starting at each occurrence of a cron expression (in UTC).
+	Recurring struct {
+		Expression string `json:"expression"`
+		Duration   int    `json:"duration"` // Duration of each freeze in seconds
+	}
+
+	// Freeze covering a date range.
+	Fixed struct {
+		StartsAt time.Time `json:"starts_at"`
+		EndsAt   time.Time `json:"ends_at"`
+	}
+)
+
+func (Command) Name_() string { return "deployment.command.create_freeze_window" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	writer domain.FreezeWindowsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			app    monad.Maybe[domain.AppID]
+			env    monad.Maybe[domain.Environment]
+			period domain.FreezePeriod
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Field(cmd.Name, strings.Required),
+			"environment": validate.Maybe(cmd.Environment, func(e string) error {
+				var value domain.Environment
+
+				if err := validate.Value(e, &value, domain.EnvironmentFrom); err != nil {
+					return err
+				}
+
+				env.Set(value)
+
+				return nil
+			}),
+			"period": validate.If(cmd.Recurring.HasValue() == cmd.Fixed.HasValue(), func() error {
+				return domain.ErrInvalidFreezePeriod
+			}),
+			"recurring": validate.Maybe(cmd.Recurring, func(r Recurring) error {
+				return validate.Value(r, &period, BuildRecurringPeriod)
+			}),
+			"fixed": validate.Maybe(cmd.Fixed, func(f Fixed) error {
+				return validate.Value(f, &period, BuildFixedPeriod)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		if id, isSet := cmd.AppID.TryGet(); isSet {
+			a, err := appsReader.GetByID(ctx, domain.AppID(id))
+
+			if err != nil {
+				return "", err
+			}
+
+			app.Set(a.ID())
+		}
+
+		window := domain.NewFreezeWindow(cmd.Name, app, env, period, auth.CurrentUser(ctx).MustGet())
+
+		if err := writer.Write(ctx, &window); err != nil {
+			return "", err
+		}
+
+		return string(window.ID()), nil
+	}
+}
+
+func BuildRecurringPeriod(r Recurring) (domain.FreezePeriod, error) {
+	expr, err := domain.ScheduleExpressionFrom(r.Expression)
+
+	if err != nil {
+		return domain.FreezePeriod{}, err
+	}
+
+	return domain.RecurringFreezePeriod(expr, time.Duration(r.Duration)*time.Second)
+}
+
+func BuildFixedPeriod(f Fixed) (domain.FreezePeriod, error) {
+	return domain.FixedFreezePeriod(f.StartsAt, f.EndsAt)
+}
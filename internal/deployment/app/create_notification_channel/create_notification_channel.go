@@ -0,0 +1,112 @@
+package create_notification_channel
+
+import (
+	"context"
+	"strconv"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+type (
+	// Create a new notification channel.
+	Command struct {
+		bus.Command[string]
+
+		Name        string              `json:"name"`
+		Kind        string              `json:"kind"`
+		Destination string              `json:"destination"`
+		Template    monad.Maybe[string] `json:"template"`
+		Rules       []Rule              `json:"rules"`
+	}
+
+	Rule struct {
+		AppID       monad.Maybe[string] `json:"app_id"`
+		Environment monad.Maybe[string] `json:"environment"`
+		Events      []string            `json:"events"`
+	}
+)
+
+func (Command) Name_() string { return "deployment.command.create_notification_channel" }
+
+func Handler(
+	writer domain.NotificationChannelsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			destination   string
+			tmpl          string
+			rules         domain.NotificationRules
+			kind, kindErr = domain.NotificationChannelKindFrom(cmd.Kind)
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Field(cmd.Name, strings.Required),
+			"kind": kindErr,
+			"destination": validate.If(kindErr == nil, func() error {
+				return validate.Value(cmd.Destination, &destination, kind.Destination)
+			}),
+			"template": validate.Maybe(cmd.Template, func(t string) error {
+				return validate.Value(t, &tmpl, domain.NotificationTemplateFrom)
+			}),
+			"rules": validate.Value(cmd.Rules, &rules, BuildRules),
+		}); err != nil {
+			return "", err
+		}
+
+		channel := domain.NewNotificationChannel(cmd.Name, kind, destination, auth.CurrentUser(ctx).MustGet())
+
+		if cmd.Template.HasValue() {
+			channel.UseTemplate(monad.Value(tmpl))
+		}
+
+		channel.SubscribeTo(rules)
+
+		if err := writer.Write(ctx, &channel); err != nil {
+			return "", err
+		}
+
+		return string(channel.ID()), nil
+	}
+}
+
+// Validates and converts the given rules to their domain counterpart.
+func BuildRules(rules []Rule) (domain.NotificationRules, error) {
+	var (
+		result = make(domain.NotificationRules, len(rules))
+		errs   = make(validate.Of, len(rules))
+	)
+
+	for i, rule := range rules {
+		var env domain.Environment
+
+		result[i].Events = make([]domain.NotificationEvent, len(rule.Events))
+
+		if appid, isSet := rule.AppID.TryGet(); isSet {
+			result[i].App.Set(domain.AppID(appid))
+		}
+
+		eventsErrs := make(validate.Of, len(rule.Events))
+
+		for j, evt := range rule.Events {
+			eventsErrs[strconv.Itoa(j)] = validate.Value(evt, &result[i].Events[j], domain.NotificationEventFrom)
+		}
+
+		errs[strconv.Itoa(i)] = validate.Struct(validate.Of{
+			"environment": validate.Maybe(rule.Environment, func(e string) error {
+				return validate.Value(e, &env, domain.EnvironmentFrom)
+			}),
+			"events": validate.Struct(eventsErrs),
+		})
+
+		if rule.Environment.HasValue() {
+			result[i].Environment.Set(env)
+		}
+	}
+
+	return result, validate.Struct(errs)
+}
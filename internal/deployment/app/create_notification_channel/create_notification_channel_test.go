@@ -0,0 +1,116 @@
+package create_notification_channel_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_CreateNotificationChannel(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, create_notification_channel.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return create_notification_channel.Handler(context.ChannelsStore), context.Context, context.Dispatcher
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		id, err := handler(ctx, create_notification_channel.Command{
+			Kind:     "pigeon",
+			Template: monad.Value("{{ .AppName "),
+			Rules: []create_notification_channel.Rule{
+				{
+					Environment: monad.Value("dev"),
+					Events:      []string{"deployment_failed", "deployment_started"},
+				},
+			},
+		})
+
+		assert.Zero(t, id)
+		assert.ValidationError(t, validate.FieldErrors{
+			"name":                strings.ErrRequired,
+			"kind":                domain.ErrInvalidNotificationChannelKind,
+			"template":            domain.ErrInvalidNotificationTemplate,
+			"rules.0.environment": domain.ErrInvalidEnvironmentName,
+			"rules.0.events.1":    domain.ErrInvalidNotificationEvent,
+		}, err)
+	})
+
+	t.Run("should require a destination valid for the channel kind", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, create_notification_channel.Command{
+			Name:        "ops",
+			Kind:        "email",
+			Destination: "http://example.com",
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"destination": domain.ErrInvalidNotificationDestination,
+		}, err)
+	})
+
+	t.Run("should create a new notification channel if everything is good", func(t *testing.T) {
+		user := authfixture.User()
+		handler, ctx, dispatcher := arrange(t, fixture.WithUsers(&user))
+
+		id, err := handler(ctx, create_notification_channel.Command{
+			Name:        "ops",
+			Kind:        "slack",
+			Destination: "https://hooks.slack.com/services/xxx",
+			Template:    monad.Value("{{ .AppName }} deployed"),
+			Rules: []create_notification_channel.Rule{
+				{
+					AppID:  monad.Value("my-app"),
+					Events: []string{"deployment_failed"},
+				},
+			},
+		})
+
+		assert.Nil(t, err)
+		assert.NotZero(t, id)
+		assert.HasLength(t, 3, dispatcher.Signals())
+
+		created := assert.Is[domain.NotificationChannelCreated](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.NotificationChannelCreated{
+			ID:          domain.NotificationChannelID(id),
+			Name:        "ops",
+			Kind:        domain.NotificationChannelKindSlack,
+			Destination: "https://hooks.slack.com/services/xxx",
+			Created:     shared.ActionFrom(user.ID(), assert.NotZero(t, created.Created.At())),
+		}, created)
+
+		templateChanged := assert.Is[domain.NotificationChannelTemplateChanged](t, dispatcher.Signals()[1])
+		assert.Equal(t, domain.NotificationChannelTemplateChanged{
+			ID:       domain.NotificationChannelID(id),
+			Template: monad.Value("{{ .AppName }} deployed"),
+		}, templateChanged)
+
+		rulesChanged := assert.Is[domain.NotificationChannelRulesChanged](t, dispatcher.Signals()[2])
+		assert.DeepEqual(t, domain.NotificationChannelRulesChanged{
+			ID: domain.NotificationChannelID(id),
+			Rules: domain.NotificationRules{
+				{
+					App:    monad.Value(domain.AppID("my-app")),
+					Events: []domain.NotificationEvent{domain.NotificationEventDeploymentFailed},
+				},
+			},
+		}, rulesChanged)
+	})
+}
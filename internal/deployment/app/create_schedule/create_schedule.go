@@ -0,0 +1,60 @@
+package create_schedule
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/cron"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Create a new schedule which will redeploy the latest successful deployment of an
+// application environment based on a cron expression.
+type Command struct {
+	bus.Command[string]
+
+	AppID       string `json:"app_id"`
+	Environment string `json:"environment"`
+	Expression  string `json:"expression"`
+}
+
+func (Command) Name_() string { return "deployment.command.create_schedule" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	writer domain.SchedulesWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			env  domain.Environment
+			expr cron.Expression
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"expression":  validate.Value(cmd.Expression, &expr, domain.ScheduleExpressionFrom),
+		}); err != nil {
+			return "", err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
+
+		if err != nil {
+			return "", err
+		}
+
+		schedule, err := app.NewSchedule(env, expr, auth.CurrentUser(ctx).MustGet())
+
+		if err != nil {
+			return "", err
+		}
+
+		if err = writer.Write(ctx, &schedule); err != nil {
+			return "", err
+		}
+
+		return string(schedule.ID()), nil
+	}
+}
@@ -0,0 +1,95 @@
+package create_schedule_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_CreateSchedule(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, create_schedule.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return create_schedule.Handler(context.AppsStore, context.SchedulesStore), context.Context, context.Dispatcher
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		id, err := handler(ctx, create_schedule.Command{
+			Environment: "dev",
+			Expression:  "0 0 30 2 *",
+		})
+
+		assert.Zero(t, id)
+		assert.ValidationError(t, validate.FieldErrors{
+			"environment": domain.ErrInvalidEnvironmentName,
+			"expression":  domain.ErrInvalidScheduleExpression,
+		}, err)
+	})
+
+	t.Run("should fail if the application does not exist", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		id, err := handler(ctx, create_schedule.Command{
+			AppID:       "some-app-id",
+			Environment: "production",
+			Expression:  "@daily",
+		})
+
+		assert.Zero(t, id)
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should create a new schedule if everything is good", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		id, err := handler(ctx, create_schedule.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Expression:  "0 22 * * *",
+		})
+
+		assert.Nil(t, err)
+		assert.NotZero(t, id)
+		assert.HasLength(t, 1, dispatcher.Signals())
+
+		created := assert.Is[domain.ScheduleCreated](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.ScheduleCreated{
+			ID:          domain.ScheduleID(id),
+			App:         app.ID(),
+			Environment: domain.Staging,
+			Expression:  must.Panic(domain.ScheduleExpressionFrom("0 22 * * *")),
+			NextRunAt:   assert.NotZero(t, created.NextRunAt),
+			Created:     shared.ActionFrom(user.ID(), assert.NotZero(t, created.Created.At())),
+		}, created)
+	})
+}
@@ -14,9 +14,10 @@ import (
 type Command struct {
 	bus.Command[string]
 
-	Name     string              `json:"name"`
-	Url      monad.Maybe[string] `json:"url"`
-	Provider any                 `json:"-"`
+	Name                     string              `json:"name"`
+	Url                      monad.Maybe[string] `json:"url"`
+	MaxConcurrentDeployments monad.Maybe[int]    `json:"max_concurrent_deployments"`
+	Provider                 any                 `json:"-"`
 }
 
 func (Command) Name_() string { return "deployment.command.create_target" }
@@ -27,13 +28,19 @@ func Handler(
 	provider domain.Provider,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var targetUrl domain.Url
+		var (
+			targetUrl      domain.Url
+			maxDeployments int
+		)
 
 		if err := validate.Struct(validate.Of{
 			"name": validate.Field(cmd.Name, strings.Required),
 			"url": validate.Maybe(cmd.Url, func(url string) error {
 				return validate.Value(url, &targetUrl, domain.UrlFrom)
 			}),
+			"max_concurrent_deployments": validate.Maybe(cmd.MaxConcurrentDeployments, func(max int) error {
+				return validate.Value(max, &maxDeployments, domain.MaxConcurrentDeploymentsFrom)
+			}),
 		}); err != nil {
 			return "", err
 		}
@@ -84,6 +91,12 @@ func Handler(
 			}
 		}
 
+		if cmd.MaxConcurrentDeployments.HasValue() {
+			if err = target.LimitConcurrentDeployments(maxDeployments); err != nil {
+				return "", err
+			}
+		}
+
 		if err = writer.Write(ctx, &target); err != nil {
 			return "", err
 		}
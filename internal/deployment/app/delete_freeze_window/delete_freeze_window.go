@@ -0,0 +1,33 @@
+package delete_freeze_window
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string { return "deployment.command.delete_freeze_window" }
+
+func Handler(
+	reader domain.FreezeWindowsReader,
+	writer domain.FreezeWindowsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		window, err := reader.GetByID(ctx, domain.FreezeWindowID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		window.Delete()
+
+		return bus.Unit, writer.Write(ctx, &window)
+	}
+}
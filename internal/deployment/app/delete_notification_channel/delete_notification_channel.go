@@ -0,0 +1,33 @@
+package delete_notification_channel
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string { return "deployment.command.delete_notification_channel" }
+
+func Handler(
+	reader domain.NotificationChannelsReader,
+	writer domain.NotificationChannelsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		channel, err := reader.GetByID(ctx, domain.NotificationChannelID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		channel.Delete()
+
+		return bus.Unit, writer.Write(ctx, &channel)
+	}
+}
@@ -0,0 +1,52 @@
+package delete_notification_channel_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_DeleteNotificationChannel(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, delete_notification_channel.Command],
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return delete_notification_channel.Handler(context.ChannelsStore, context.ChannelsStore), context.Dispatcher
+	}
+
+	t.Run("should require an existing channel", func(t *testing.T) {
+		handler, _ := arrange(t)
+
+		_, err := handler(context.Background(), delete_notification_channel.Command{
+			ID: "non-existing-id",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the channel", func(t *testing.T) {
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelCreatedBy(user.ID()))
+		handler, dispatcher := arrange(t, fixture.WithUsers(&user), fixture.WithNotificationChannels(&channel))
+
+		_, err := handler(context.Background(), delete_notification_channel.Command{
+			ID: string(channel.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.Equal(t, domain.NotificationChannelDeleted{
+			ID: channel.ID(),
+		}, assert.Is[domain.NotificationChannelDeleted](t, dispatcher.Signals()[0]))
+	})
+}
@@ -0,0 +1,33 @@
+package delete_schedule
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ID string `json:"id"`
+}
+
+func (Command) Name_() string { return "deployment.command.delete_schedule" }
+
+func Handler(
+	reader domain.SchedulesReader,
+	writer domain.SchedulesWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		schedule, err := reader.GetByID(ctx, domain.ScheduleID(cmd.ID))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		schedule.Delete()
+
+		return bus.Unit, writer.Write(ctx, &schedule)
+	}
+}
@@ -0,0 +1,69 @@
+package delete_schedule_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+)
+
+func Test_DeleteSchedule(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, delete_schedule.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return delete_schedule.Handler(context.SchedulesStore, context.SchedulesStore), context.Context, context.Dispatcher
+	}
+
+	t.Run("should fail if the schedule does not exist", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, delete_schedule.Command{
+			ID: "some-id",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should delete the schedule", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		schedule := fixture.Schedule(
+			fixture.WithScheduleApp(app),
+			fixture.WithScheduleCreatedBy(user.ID()),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithSchedules(&schedule),
+		)
+
+		_, err := handler(ctx, delete_schedule.Command{
+			ID: string(schedule.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.Equal(t, domain.ScheduleDeleted{
+			ID: schedule.ID(),
+		}, assert.Is[domain.ScheduleDeleted](t, dispatcher.Signals()[0]))
+	})
+}
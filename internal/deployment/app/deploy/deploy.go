@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"strconv"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/apperr"
@@ -24,6 +25,9 @@ func (c Command) ResourceID() string { return c.AppID + "-" + strconv.Itoa(c.Dep
 // Handle the deployment process.
 // If an unexpected error occurs during this process, it uses the bus.PreserveOrder function
 // to make sure all deployments are processed linearly.
+//
+// The default timeout is used when the deployment environment does not define its own,
+// a zero value means deployments can run forever.
 func Handler(
 	reader domain.DeploymentsReader,
 	writer domain.DeploymentsWriter,
@@ -32,6 +36,7 @@ func Handler(
 	provider domain.Provider,
 	targetsReader domain.TargetsReader,
 	registriesReader domain.RegistriesReader,
+	defaultTimeout time.Duration,
 ) bus.RequestHandler[bus.UnitType, Command] {
 	return func(ctx context.Context, cmd Command) (result bus.UnitType, finalErr error) {
 		result = bus.Unit
@@ -59,6 +64,16 @@ func Handler(
 		}
 
 		if err = depl.HasStarted(); err != nil {
+			// Already running means a previous attempt has been interrupted, the instance
+			// processing it has stopped and the job has been released, so fail it.
+			if depl.State().Status() == domain.DeploymentStatusRunning {
+				if err = depl.HasEnded(nil, domain.ErrServerReset); err != nil {
+					return result, err
+				}
+
+				return result, writer.Write(ctx, &depl)
+			}
+
 			// If the deployment could not be started, it probably means the
 			// application has been requested for cleanup and the deployment has been
 			// cancelled, so the deploy job will never succeed.
@@ -85,16 +100,35 @@ func Handler(
 			deploymentCtx domain.DeploymentContext
 			services      domain.Services
 			registries    []domain.Registry
+			timeout       = depl.Config().Timeout().Get(defaultTimeout)
 		)
 
+		if timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+
 		// This one is a special case to avoid to avoid many branches
 		// checking for errors when writing the domain.
 		// Based on wether or not there was an error, it will update the deployment
 		// accordingly.
 		defer func() {
+			// The deployment may have been cancelled, in which case the context is done
+			// but the deployment must still be written.
+			writeCtx := context.WithoutCancel(ctx)
+
+			if finalErr != nil && ctx.Err() != nil {
+				if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+					finalErr = domain.ErrDeploymentTimeout
+				} else {
+					finalErr = domain.ErrDeploymentCancelled
+				}
+			}
+
 			// Since the deployment process could take some time, retrieve a fresh version of the
 			// deployment right now
-			if depl, err = reader.GetByID(ctx, depl.ID()); err != nil {
+			if depl, err = reader.GetByID(writeCtx, depl.ID()); err != nil {
 				if errors.Is(err, apperr.ErrNotFound) {
 					finalErr = nil
 				} else {
@@ -109,7 +143,7 @@ func Handler(
 				return
 			}
 
-			if err = writer.Write(ctx, &depl); err != nil {
+			if err = writer.Write(writeCtx, &depl); err != nil {
 				finalErr = err
 				return
 			}
@@ -124,6 +158,21 @@ func Handler(
 
 		defer deploymentCtx.Logger().Close()
 
+		// Document where the deployment was when it timed out before the logger is closed.
+		defer func() {
+			if finalErr == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return
+			}
+
+			phase := deploymentCtx.Logger().CurrentPhase()
+
+			if phase == "" {
+				deploymentCtx.Logger().Warnf("deployment has timed out after %s before any phase has started", timeout)
+			} else {
+				deploymentCtx.Logger().Warnf("deployment has timed out after %s during the %s phase", timeout, phase)
+			}
+		}()
+
 		// If the target does not exist, let's fail the deployment correctly
 		if targetErr != nil {
 			finalErr = targetErr
@@ -137,6 +186,8 @@ func Handler(
 		}
 
 		// Fetch deployment files
+		deploymentCtx.Logger().Phase(domain.DeploymentPhaseFetch)
+
 		if finalErr = source.Fetch(ctx, deploymentCtx, depl); finalErr != nil {
 			return
 		}
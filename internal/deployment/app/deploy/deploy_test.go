@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
 	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
@@ -32,7 +33,7 @@ func Test_Deploy(t *testing.T) {
 		context := fixture.PrepareDatabase(tb, seed...)
 		logger, _ := log.NewLogger()
 		artifactManager := artifact.NewLocal(context.Config, logger)
-		return deploy.Handler(context.DeploymentsStore, context.DeploymentsStore, artifactManager, source, provider, context.TargetsStore, context.RegistriesStore), context.Context, context.Dispatcher
+		return deploy.Handler(context.DeploymentsStore, context.DeploymentsStore, artifactManager, source, provider, context.TargetsStore, context.RegistriesStore, 0), context.Context, context.Dispatcher
 	}
 
 	t.Run("should fail silently if the deployment does not exists", func(t *testing.T) {
@@ -190,6 +191,114 @@ func Test_Deploy(t *testing.T) {
 		assert.Equal(t, providerErr.Error(), changed.State.ErrCode().MustGet())
 	})
 
+	t.Run("should mark the deployment has cancelled if the context is cancelled while deploying", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		deployment := fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.FromApp(app),
+		)
+		cancellable := &cancellingProvider{}
+		handler, ctx, dispatcher := arrange(t, source(nil), cancellable,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployment),
+		)
+		ctx, cancellable.cancel = context.WithCancel(ctx)
+
+		r, err := handler(ctx, deploy.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[1])
+		assert.Equal(t, domain.DeploymentStatusFailed, changed.State.Status())
+		assert.Equal(t, domain.ErrDeploymentCancelled.Error(), changed.State.ErrCode().MustGet())
+	})
+
+	t.Run("should fail the deployment if it has timed out", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		config := domain.NewEnvironmentConfig(target.ID())
+		config.HasTimeout(time.Second)
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(config, config),
+		)
+		deployment := fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.FromApp(app),
+		)
+		handler, ctx, dispatcher := arrange(t, source(nil), &blockingProvider{},
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployment),
+		)
+
+		r, err := handler(ctx, deploy.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[1])
+		assert.Equal(t, domain.DeploymentStatusFailed, changed.State.Status())
+		assert.Equal(t, domain.ErrDeploymentTimeout.Error(), changed.State.ErrCode().MustGet())
+	})
+
+	t.Run("should fail the deployment if a previous attempt has been interrupted", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		target.Configured(target.CurrentVersion(), nil, nil)
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		deployment := fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.FromApp(app),
+		)
+		assert.Nil(t, deployment.HasStarted())
+		handler, ctx, dispatcher := arrange(t, source(nil), provider(nil),
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployment),
+		)
+
+		r, err := handler(ctx, deploy.Command{
+			AppID:            string(deployment.ID().AppID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+		assert.HasLength(t, 1, dispatcher.Signals())
+
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.DeploymentStatusFailed, changed.State.Status())
+		assert.Equal(t, domain.ErrServerReset.Error(), changed.State.ErrCode().MustGet())
+	})
+
 	t.Run("should mark the deployment has succeeded if all is good", func(t *testing.T) {
 		user := authfixture.User()
 		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
@@ -262,3 +371,23 @@ func (b *dummyProvider) Prepare(context.Context, any, ...domain.ProviderConfig)
 func (b *dummyProvider) Deploy(context.Context, domain.DeploymentContext, domain.Deployment, domain.Target, []domain.Registry) (domain.Services, error) {
 	return domain.Services{}, b.err
 }
+
+type cancellingProvider struct {
+	dummyProvider
+	cancel context.CancelFunc
+}
+
+func (b *cancellingProvider) Deploy(ctx context.Context, _ domain.DeploymentContext, _ domain.Deployment, _ domain.Target, _ []domain.Registry) (domain.Services, error) {
+	b.cancel()
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+type blockingProvider struct {
+	dummyProvider
+}
+
+func (*blockingProvider) Deploy(ctx context.Context, _ domain.DeploymentContext, _ domain.Deployment, _ domain.Target, _ []domain.Registry) (domain.Services, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
@@ -2,18 +2,73 @@ package deploy
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
 // Upon receiving a deployment created event, queue a job to deploy the application.
-func OnDeploymentCreatedHandler(scheduler bus.Scheduler) bus.SignalHandler[domain.DeploymentCreated] {
+// Deployments awaiting approval are queued once approved.
+func OnDeploymentCreatedHandler(
+	scheduler bus.Scheduler,
+	targetsReader domain.TargetsReader,
+) bus.SignalHandler[domain.DeploymentCreated] {
 	return func(ctx context.Context, evt domain.DeploymentCreated) error {
-		return scheduler.Queue(ctx, Command{
-			AppID:            string(evt.ID.AppID()),
-			DeploymentNumber: int(evt.ID.DeploymentNumber()),
-		}, bus.WithGroup(app.DeploymentGroup(evt.Config)), bus.WithPolicy(bus.JobPolicyRetryPreserveOrder))
+		if evt.IsAwaitingApproval() {
+			return nil
+		}
+
+		return queue(ctx, scheduler, targetsReader, evt.ID, evt.Config, evt.NotBefore)
+	}
+}
+
+// Upon receiving a deployment approved event, queue a job to deploy the application.
+func OnDeploymentApprovedHandler(
+	scheduler bus.Scheduler,
+	targetsReader domain.TargetsReader,
+) bus.SignalHandler[domain.DeploymentApproved] {
+	return func(ctx context.Context, evt domain.DeploymentApproved) error {
+		return queue(ctx, scheduler, targetsReader, evt.ID, evt.Config, evt.NotBefore)
 	}
 }
+
+func queue(
+	ctx context.Context,
+	scheduler bus.Scheduler,
+	targetsReader domain.TargetsReader,
+	id domain.DeploymentID,
+	config domain.ConfigSnapshot,
+	notBefore monad.Maybe[time.Time],
+) error {
+	options := []bus.JobOptions{
+		bus.WithGroup(app.DeploymentGroup(config)),
+		bus.WithPolicy(bus.JobPolicyRetryPreserveOrder),
+	}
+
+	// Limit deployments running at the same time on the target if configured. If the target
+	// does not exist anymore, the deployment will fail anyway.
+	target, err := targetsReader.GetByID(ctx, config.Target())
+
+	if err != nil && !errors.Is(err, apperr.ErrNotFound) {
+		return err
+	}
+
+	if max, isSet := target.MaxConcurrentDeployments().TryGet(); isSet {
+		options = append(options, bus.WithConcurrencyLimit(app.TargetDeploymentsConcurrencyKey(target.ID()), max))
+	}
+
+	// Scheduled deployments stay pending until the requested time
+	if at, isSet := notBefore.TryGet(); isSet {
+		options = append(options, bus.WithNotBefore(at))
+	}
+
+	return scheduler.Queue(ctx, Command{
+		AppID:            string(id.AppID()),
+		DeploymentNumber: int(id.DeploymentNumber()),
+	}, options...)
+}
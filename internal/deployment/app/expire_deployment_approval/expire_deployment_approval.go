@@ -0,0 +1,54 @@
+package expire_deployment_approval
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Fail a deployment which is still awaiting approval once the expiration delay is reached.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"app_id"`
+	DeploymentNumber int    `json:"deployment_number"`
+}
+
+func (Command) Name_() string        { return "deployment.command.expire_deployment_approval" }
+func (c Command) ResourceID() string { return c.AppID + "-" + strconv.Itoa(c.DeploymentNumber) }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			// Deployment does not exist anymore, the app should have been deleted
+			if errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, nil
+			}
+
+			return bus.Unit, err
+		}
+
+		// Already approved, rejected or cancelled, nothing to do
+		if err = depl.ApprovalExpired(); err != nil {
+			return bus.Unit, nil
+		}
+
+		if err = writer.Write(ctx, &depl); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, nil
+	}
+}
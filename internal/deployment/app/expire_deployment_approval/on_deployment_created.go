@@ -0,0 +1,24 @@
+package expire_deployment_approval
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When a deployment must be approved, queue a job to expire it if it has not been
+// approved in time. A zero expiration means deployments could wait forever.
+func OnDeploymentCreatedHandler(scheduler bus.Scheduler, expiration time.Duration) bus.SignalHandler[domain.DeploymentCreated] {
+	return func(ctx context.Context, evt domain.DeploymentCreated) error {
+		if expiration <= 0 || !evt.IsAwaitingApproval() {
+			return nil
+		}
+
+		return scheduler.Queue(ctx, Command{
+			AppID:            string(evt.ID.AppID()),
+			DeploymentNumber: int(evt.ID.DeploymentNumber()),
+		}, bus.WithNotBefore(evt.Requested.At().Add(expiration)))
+	}
+}
@@ -0,0 +1,46 @@
+package fail_interrupted_deployments
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Fail running deployments which are not processed by any worker anymore, because
+// the instance running them has been stopped abruptly. Sent by the leader when elected.
+type Command struct {
+	bus.Command[bus.UnitType]
+}
+
+func (Command) Name_() string { return "deployment.command.fail_interrupted_deployments" }
+
+func Handler(
+	writer domain.DeploymentsWriter,
+	scheduler bus.Scheduler,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		claimed, err := scheduler.Claimed(ctx, deploy.Command{}.Name_())
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		processed := make([]domain.DeploymentID, 0, len(claimed))
+
+		for _, msg := range claimed {
+			if job, isDeploy := msg.(deploy.Command); isDeploy {
+				processed = append(processed, domain.DeploymentIDFrom(
+					domain.AppID(job.AppID),
+					domain.DeploymentNumber(job.DeploymentNumber),
+				))
+			}
+		}
+
+		return bus.Unit, writer.FailDeployments(ctx, domain.ErrServerReset, domain.FailCriteria{
+			Status: []domain.DeploymentStatus{domain.DeploymentStatusRunning},
+			Except: processed,
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package fail_interrupted_deployments_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/fail_interrupted_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func Test_FailInterruptedDeployments(t *testing.T) {
+
+	arrange := func(tb testing.TB, claimed []bus.Request, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, fail_interrupted_deployments.Command],
+		context.Context,
+		domain.DeploymentsReader,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		scheduler := &dummyScheduler{claimed: claimed}
+		return fail_interrupted_deployments.Handler(context.DeploymentsStore, scheduler), context.Context, context.DeploymentsStore
+	}
+
+	t.Run("should fail running deployments not processed by a worker anymore", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		interruptedApp := fixture.App(fixture.WithAppCreatedBy(user.ID()), fixture.WithEnvironmentConfig(
+			domain.NewEnvironmentConfig(target.ID()),
+			domain.NewEnvironmentConfig(target.ID()),
+		))
+		processedApp := fixture.App(fixture.WithAppCreatedBy(user.ID()), fixture.WithEnvironmentConfig(
+			domain.NewEnvironmentConfig(target.ID()),
+			domain.NewEnvironmentConfig(target.ID()),
+		))
+		interrupted := fixture.Deployment(fixture.FromApp(interruptedApp), fixture.WithDeploymentRequestedBy(user.ID()))
+		assert.Nil(t, interrupted.HasStarted())
+		processed := fixture.Deployment(fixture.FromApp(processedApp), fixture.WithDeploymentRequestedBy(user.ID()))
+		assert.Nil(t, processed.HasStarted())
+
+		handler, ctx, reader := arrange(t,
+			[]bus.Request{deploy.Command{
+				AppID:            string(processed.ID().AppID()),
+				DeploymentNumber: int(processed.ID().DeploymentNumber()),
+			}},
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&interruptedApp, &processedApp),
+			fixture.WithDeployments(&interrupted, &processed),
+		)
+
+		r, err := handler(ctx, fail_interrupted_deployments.Command{})
+
+		assert.Nil(t, err)
+		assert.Equal(t, bus.Unit, r)
+
+		interrupted, err = reader.GetByID(ctx, interrupted.ID())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DeploymentStatusFailed, interrupted.State().Status())
+		assert.Equal(t, domain.ErrServerReset.Error(), interrupted.State().ErrCode().MustGet())
+
+		processed, err = reader.GetByID(ctx, processed.ID())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.DeploymentStatusRunning, processed.State().Status())
+	})
+}
+
+type dummyScheduler struct {
+	bus.Scheduler
+	claimed []bus.Request
+}
+
+func (s *dummyScheduler) Claimed(context.Context, string) ([]bus.Request, error) {
+	return s.claimed, nil
+}
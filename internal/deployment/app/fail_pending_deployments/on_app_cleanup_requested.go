@@ -12,7 +12,7 @@ import (
 func OnAppCleanupRequestedHandler(writer domain.DeploymentsWriter) bus.SignalHandler[domain.AppCleanupRequested] {
 	return func(ctx context.Context, evt domain.AppCleanupRequested) error {
 		return writer.FailDeployments(ctx, domain.ErrAppCleanupRequested, domain.FailCriteria{
-			Status: monad.Value(domain.DeploymentStatusPending),
+			Status: []domain.DeploymentStatus{domain.DeploymentStatusPending, domain.DeploymentStatusAwaitingApproval},
 			App:    monad.Value(evt.ID),
 		})
 	}
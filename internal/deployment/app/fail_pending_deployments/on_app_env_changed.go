@@ -15,7 +15,7 @@ func OnAppEnvChangedHandler(writer domain.DeploymentsWriter) bus.SignalHandler[d
 		}
 
 		return writer.FailDeployments(ctx, domain.ErrAppTargetChanged, domain.FailCriteria{
-			Status:      monad.Value(domain.DeploymentStatusPending),
+			Status:      []domain.DeploymentStatus{domain.DeploymentStatusPending, domain.DeploymentStatusAwaitingApproval},
 			App:         monad.Value(evt.ID),
 			Environment: monad.Value(evt.Environment),
 			Target:      monad.Value(evt.OldConfig.Target()),
@@ -0,0 +1,67 @@
+package fail_pending_deployments
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When the "latest wins" policy is enabled on the app, older pending deployments of the
+// same environment are superseded by the new one and their deploy jobs removed from the queue.
+// Scheduled deployments neither supersede nor are superseded since they are expected to run
+// at the time they have been planned for.
+func OnDeploymentCreatedHandler(
+	appsReader domain.AppsReader,
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[domain.DeploymentCreated] {
+	return func(ctx context.Context, evt domain.DeploymentCreated) error {
+		if evt.NotBefore.HasValue() {
+			return nil
+		}
+
+		app, err := appsReader.GetByID(ctx, evt.ID.AppID())
+
+		if err != nil {
+			return err
+		}
+
+		if !app.LatestWins() {
+			return nil
+		}
+
+		deployments, err := reader.GetSupersedable(ctx, evt.ID.AppID(), evt.Config.Environment(), evt.ID.DeploymentNumber())
+
+		if err != nil {
+			return err
+		}
+
+		superseded := make([]*domain.Deployment, len(deployments))
+
+		for i := range deployments {
+			if err = deployments[i].Supersede(); err != nil {
+				return err
+			}
+
+			superseded[i] = &deployments[i]
+		}
+
+		if err = writer.Write(ctx, superseded...); err != nil {
+			return err
+		}
+
+		for _, depl := range deployments {
+			if err = scheduler.Cancel(ctx, deploy.Command{
+				AppID:            string(depl.ID().AppID()),
+				DeploymentNumber: int(depl.ID().DeploymentNumber()),
+			}); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,149 @@
+package fail_pending_deployments_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/fail_pending_deployments"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+func Test_OnDeploymentCreated(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.SignalHandler[domain.DeploymentCreated],
+		context.Context,
+		domain.DeploymentsReader,
+		spy.Dispatcher,
+		*dummyScheduler,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		scheduler := &dummyScheduler{}
+		return fail_pending_deployments.OnDeploymentCreatedHandler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore, scheduler),
+			context.Context, context.DeploymentsStore, context.Dispatcher, scheduler
+	}
+
+	// Builds an app with the given policy and the following deployments:
+	//  #1 pending on production
+	//  #2 scheduled on production
+	//  #3 pending on staging
+	//  #4 pending on production, the one being created
+	//  #5 pending on production, queued after the created one
+	seed := func(t testing.TB, latestWins bool) ([]domain.Deployment, []fixture.SeedBuilder) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			))
+		assert.Nil(t, app.UseLatestWins(latestWins))
+
+		source := fixture.SourceData()
+		deployments := []domain.Deployment{
+			must.Panic(app.NewDeployment(1, source, domain.Production, user.ID())),
+			must.Panic(app.ScheduleDeployment(2, source, domain.Production, time.Now().Add(time.Hour), user.ID())),
+			must.Panic(app.NewDeployment(3, source, domain.Staging, user.ID())),
+			must.Panic(app.NewDeployment(4, source, domain.Production, user.ID())),
+			must.Panic(app.NewDeployment(5, source, domain.Production, user.ID())),
+		}
+
+		return deployments, []fixture.SeedBuilder{
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployments[0], &deployments[1], &deployments[2], &deployments[3], &deployments[4]),
+		}
+	}
+
+	statuses := func(t testing.TB, ctx context.Context, reader domain.DeploymentsReader, deployments []domain.Deployment) []domain.DeploymentStatus {
+		result := make([]domain.DeploymentStatus, len(deployments))
+
+		for i, d := range deployments {
+			deployment, err := reader.GetByID(ctx, d.ID())
+			assert.Nil(t, err)
+			result[i] = deployment.State().Status()
+		}
+
+		return result
+	}
+
+	t.Run("should do nothing if the latest wins policy is disabled", func(t *testing.T) {
+		deployments, builders := seed(t, false)
+		handler, ctx, reader, dispatcher, scheduler := arrange(t, builders...)
+
+		assert.Nil(t, handler(ctx, assert.EventIs[domain.DeploymentCreated](t, &deployments[3], 0)))
+
+		assert.HasLength(t, 0, dispatcher.Signals())
+		assert.HasLength(t, 0, scheduler.cancelled)
+		assert.DeepEqual(t, []domain.DeploymentStatus{
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+		}, statuses(t, ctx, reader, deployments))
+	})
+
+	t.Run("should do nothing if the created deployment is scheduled", func(t *testing.T) {
+		deployments, builders := seed(t, true)
+		handler, ctx, reader, dispatcher, scheduler := arrange(t, builders...)
+
+		assert.Nil(t, handler(ctx, assert.EventIs[domain.DeploymentCreated](t, &deployments[1], 0)))
+
+		assert.HasLength(t, 0, dispatcher.Signals())
+		assert.HasLength(t, 0, scheduler.cancelled)
+		assert.DeepEqual(t, []domain.DeploymentStatus{
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+		}, statuses(t, ctx, reader, deployments))
+	})
+
+	t.Run("should supersede older pending deployments of the same environment which are not scheduled", func(t *testing.T) {
+		deployments, builders := seed(t, true)
+		handler, ctx, reader, dispatcher, scheduler := arrange(t, builders...)
+
+		assert.Nil(t, handler(ctx, assert.EventIs[domain.DeploymentCreated](t, &deployments[3], 0)))
+
+		assert.HasLength(t, 1, dispatcher.Signals())
+		changed := assert.Is[domain.DeploymentStateChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, deployments[0].ID(), changed.ID)
+		assert.Equal(t, domain.DeploymentStatusFailed, changed.State.Status())
+		assert.DeepEqual(t, []bus.Schedulable{deploy.Command{
+			AppID:            string(deployments[0].ID().AppID()),
+			DeploymentNumber: int(deployments[0].ID().DeploymentNumber()),
+		}}, scheduler.cancelled)
+		assert.DeepEqual(t, []domain.DeploymentStatus{
+			domain.DeploymentStatusFailed,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+			domain.DeploymentStatusPending,
+		}, statuses(t, ctx, reader, deployments))
+
+		superseded, err := reader.GetByID(ctx, deployments[0].ID())
+		assert.Nil(t, err)
+		assert.Equal(t, domain.ErrDeploymentSuperseded.Error(), superseded.State().ErrCode().MustGet())
+	})
+}
+
+type dummyScheduler struct {
+	bus.Scheduler
+	cancelled []bus.Schedulable
+}
+
+func (s *dummyScheduler) Cancel(_ context.Context, msg bus.Schedulable) error {
+	s.cancelled = append(s.cancelled, msg)
+	return nil
+}
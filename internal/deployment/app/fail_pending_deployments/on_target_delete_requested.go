@@ -11,7 +11,7 @@ import (
 func OnTargetDeleteRequestedHandler(writer domain.DeploymentsWriter) bus.SignalHandler[domain.TargetCleanupRequested] {
 	return func(ctx context.Context, evt domain.TargetCleanupRequested) error {
 		return writer.FailDeployments(ctx, domain.ErrTargetCleanupRequested, domain.FailCriteria{
-			Status: monad.Value(domain.DeploymentStatusPending),
+			Status: []domain.DeploymentStatus{domain.DeploymentStatusPending, domain.DeploymentStatusAwaitingApproval},
 			Target: monad.Value(evt.ID),
 		})
 	}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+// Check freeze windows applying to a newly created deployment. The override flag is
+// only honored for users allowed to deploy during a freeze.
+func CheckFreezeWindows(
+	ctx context.Context,
+	reader domain.FreezeWindowsReader,
+	deployment *domain.Deployment,
+	override bool,
+) error {
+	config := deployment.Config()
+
+	windows, err := reader.GetForAppEnv(ctx, config.AppID(), config.Environment())
+
+	if err != nil {
+		return err
+	}
+
+	var allowed domain.FreezeOverrideAllowed
+
+	if override {
+		if allowed, err = reader.IsFreezeOverrideAllowed(ctx, deployment.Requested().By()); err != nil {
+			return err
+		}
+	}
+
+	return deployment.CheckFreezeWindows(windows, override, allowed)
+}
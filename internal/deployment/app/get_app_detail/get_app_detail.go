@@ -5,6 +5,7 @@ import (
 
 	"github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
@@ -29,16 +30,26 @@ type (
 		Production         EnvironmentConfig                                `json:"production"`
 		Staging            EnvironmentConfig                                `json:"staging"`
 		VersionControl     monad.Maybe[VersionControl]                      `json:"version_control"`
+		LatestWins         bool                                             `json:"latest_wins"`
 	}
 
 	VersionControl struct {
 		Url   string                            `json:"url"`
 		Token monad.Maybe[storage.SecretString] `json:"token"`
+		Forge monad.Maybe[Forge]                `json:"forge"`
+	}
+
+	Forge struct {
+		Kind  string               `json:"kind"`
+		Token storage.SecretString `json:"token"`
 	}
 
 	EnvironmentConfig struct {
-		Target app.TargetSummary        `json:"target"`
-		Vars   monad.Maybe[ServicesEnv] `json:"vars"`
+		Target           app.TargetSummary                      `json:"target"`
+		Vars             monad.Maybe[ServicesEnv]               `json:"vars"`
+		Timeout          monad.Maybe[int64]                     `json:"timeout"` // Deployment timeout in seconds
+		RequiresApproval bool                                   `json:"requires_approval"`
+		Runtime          monad.Maybe[get_runtime_status.Status] `json:"runtime"` // Only set when retrieving a single app
 	}
 
 	ServicesEnv map[string]map[string]string
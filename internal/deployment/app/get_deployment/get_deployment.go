@@ -24,14 +24,42 @@ type (
 	}
 
 	Deployment struct {
-		AppID            string          `json:"app_id"`
-		DeploymentNumber int             `json:"deployment_number"`
-		Environment      string          `json:"environment"`
-		Target           TargetSummary   `json:"target"`
-		Source           Source          `json:"source"`
-		State            State           `json:"state"`
-		RequestedAt      time.Time       `json:"requested_at"`
-		RequestedBy      app.UserSummary `json:"requested_by"`
+		AppID            string                       `json:"app_id"`
+		DeploymentNumber int                          `json:"deployment_number"`
+		Environment      string                       `json:"environment"`
+		Target           TargetSummary                `json:"target"`
+		Source           Source                       `json:"source"`
+		State            State                        `json:"state"`
+		RequestedAt      time.Time                    `json:"requested_at"`
+		RequestedBy      app.UserSummary              `json:"requested_by"`
+		ApprovedAt       monad.Maybe[time.Time]       `json:"approved_at"`
+		ApprovedBy       monad.Maybe[app.UserSummary] `json:"approved_by"`
+		FreezeOverridden bool                         `json:"freeze_overridden"` // Requested during a freeze window by the administrator
+		Queue            monad.Maybe[Queue]           `json:"queue"`             // Only available for pending deployments waiting for their job
+	}
+
+	// Queue information of a pending deployment, derived from its deploy job.
+	Queue struct {
+		Position  int                    `json:"position"` // Position among pending deploy jobs, starting at 1
+		Reason    QueueReason            `json:"reason"`
+		NotBefore time.Time              `json:"not_before"`
+		Attempts  int                    `json:"attempts"`
+		ErrCode   monad.Maybe[string]    `json:"error_code"` // Last error of the deploy job if it has been retried
+		Eta       monad.Maybe[time.Time] `json:"eta"`        // Estimated time at which the deployment should be done
+	}
+
+	// Why a pending deployment is not running yet.
+	QueueReason string
+
+	// Raw deploy job data used to compute the queue information of a deployment.
+	PendingJob struct {
+		Position         int
+		NotBefore        time.Time
+		Attempts         int
+		ErrCode          monad.Maybe[string]
+		Ahead            int                    // Number of pending jobs before this one in the same group
+		RunningSince     monad.Maybe[time.Time] // Start date of the job currently running in the same group if any
+		ConcurrencyLimit bool                   // Is the target concurrency limit reached?
 	}
 
 	// This summary is specific in the sense that it represents a target which may
@@ -84,6 +112,15 @@ type (
 	}
 )
 
+const (
+	QueueReasonReady             QueueReason = "ready"
+	QueueReasonTargetConfiguring QueueReason = "target_configuring"
+	QueueReasonWaitingForGroup   QueueReason = "waiting_for_group"
+	QueueReasonRetryScheduled    QueueReason = "retry_scheduled"
+	QueueReasonScheduled         QueueReason = "scheduled"
+	QueueReasonTargetBusy        QueueReason = "target_busy"
+)
+
 func (Query) Name_() string { return "deployment.query.get_deployment" }
 
 func (s *Services) Scan(value any) error {
@@ -156,3 +193,58 @@ func (d *Deployment) ResolveServicesUrls() {
 		}
 	}
 }
+
+// Populate the queue information of a pending deployment from its deploy job. The ETA is
+// estimated from the given durations of recent deployments of the app and is left empty
+// if there is none.
+//
+// This method should be called after the deployment has been loaded.
+func (d *Deployment) ResolveQueue(job PendingJob, durations []time.Duration, now time.Time) {
+	queue := Queue{
+		Position:  job.Position,
+		Reason:    QueueReasonReady,
+		NotBefore: job.NotBefore,
+		Attempts:  job.Attempts,
+		ErrCode:   job.ErrCode,
+	}
+
+	status, hasTarget := d.Target.Status.TryGet()
+	delayed := job.NotBefore.After(now)
+
+	switch {
+	case hasTarget && domain.TargetStatus(status) == domain.TargetStatusConfiguring:
+		queue.Reason = QueueReasonTargetConfiguring
+	case job.Ahead > 0 || job.RunningSince.HasValue():
+		queue.Reason = QueueReasonWaitingForGroup
+	case delayed && job.Attempts > 0:
+		queue.Reason = QueueReasonRetryScheduled
+	case delayed:
+		queue.Reason = QueueReasonScheduled
+	case job.ConcurrencyLimit:
+		queue.Reason = QueueReasonTargetBusy
+	}
+
+	if len(durations) > 0 {
+		var total time.Duration
+
+		for _, duration := range durations {
+			total += duration
+		}
+
+		average := total / time.Duration(len(durations))
+		start := now.Add(time.Duration(job.Ahead) * average)
+
+		// The running deployment is expected to last as long as the others
+		if since, isRunning := job.RunningSince.TryGet(); isRunning {
+			start = start.Add(max(average-now.Sub(since), 0))
+		}
+
+		if job.NotBefore.After(start) {
+			start = job.NotBefore
+		}
+
+		queue.Eta.Set(start.Add(average))
+	}
+
+	d.Queue.Set(queue)
+}
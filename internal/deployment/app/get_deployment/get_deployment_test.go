@@ -2,8 +2,10 @@ package get_deployment_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/assert"
 	"github.com/YuukanOO/seelf/pkg/monad"
 )
@@ -279,4 +281,95 @@ func Test_Deployment(t *testing.T) {
 			},
 		}, d.State.Services.Get(get_deployment.Services{}))
 	})
+	t.Run("should resolve the queue information of a pending deployment", func(t *testing.T) {
+		now := time.Date(2024, time.March, 15, 18, 0, 0, 0, time.UTC)
+		durations := []time.Duration{time.Minute, 3 * time.Minute}
+
+		tests := []struct {
+			name   string
+			status monad.Maybe[uint8]
+			job    get_deployment.PendingJob
+			reason get_deployment.QueueReason
+			eta    time.Time
+		}{
+			{
+				name:   "ready",
+				status: monad.Value(uint8(domain.TargetStatusReady)),
+				job:    get_deployment.PendingJob{Position: 1, NotBefore: now},
+				reason: get_deployment.QueueReasonReady,
+				eta:    now.Add(2 * time.Minute),
+			},
+			{
+				name:   "target configuring",
+				status: monad.Value(uint8(domain.TargetStatusConfiguring)),
+				job:    get_deployment.PendingJob{Position: 1, NotBefore: now},
+				reason: get_deployment.QueueReasonTargetConfiguring,
+				eta:    now.Add(2 * time.Minute),
+			},
+			{
+				name: "waiting for an earlier deployment",
+				job: get_deployment.PendingJob{
+					Position:     2,
+					NotBefore:    now,
+					Ahead:        1,
+					RunningSince: monad.Value(now.Add(-time.Minute)),
+				},
+				reason: get_deployment.QueueReasonWaitingForGroup,
+				eta:    now.Add(5 * time.Minute),
+			},
+			{
+				name: "retry scheduled",
+				job: get_deployment.PendingJob{
+					Position:  1,
+					NotBefore: now.Add(10 * time.Minute),
+					Attempts:  1,
+					ErrCode:   monad.Value("some error"),
+				},
+				reason: get_deployment.QueueReasonRetryScheduled,
+				eta:    now.Add(12 * time.Minute),
+			},
+			{
+				name:   "scheduled",
+				job:    get_deployment.PendingJob{Position: 1, NotBefore: now.Add(time.Hour)},
+				reason: get_deployment.QueueReasonScheduled,
+				eta:    now.Add(time.Hour + 2*time.Minute),
+			},
+			{
+				name:   "target busy",
+				job:    get_deployment.PendingJob{Position: 1, NotBefore: now, ConcurrencyLimit: true},
+				reason: get_deployment.QueueReasonTargetBusy,
+				eta:    now.Add(2 * time.Minute),
+			},
+		}
+
+		for _, test := range tests {
+			t.Run(test.name, func(t *testing.T) {
+				d := get_deployment.Deployment{
+					Target: get_deployment.TargetSummary{
+						ID:     "target-id",
+						Status: test.status,
+					},
+				}
+
+				d.ResolveQueue(test.job, durations, now)
+
+				assert.DeepEqual(t, get_deployment.Queue{
+					Position:  test.job.Position,
+					Reason:    test.reason,
+					NotBefore: test.job.NotBefore,
+					Attempts:  test.job.Attempts,
+					ErrCode:   test.job.ErrCode,
+					Eta:       monad.Value(test.eta),
+				}, d.Queue.MustGet())
+			})
+		}
+	})
+
+	t.Run("should not estimate the queue eta without previous deployments", func(t *testing.T) {
+		var d get_deployment.Deployment
+
+		d.ResolveQueue(get_deployment.PendingJob{Position: 1, NotBefore: time.Now()}, nil, time.Now())
+
+		assert.False(t, d.Queue.MustGet().Eta.HasValue())
+	})
 }
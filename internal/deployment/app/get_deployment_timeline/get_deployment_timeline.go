@@ -0,0 +1,64 @@
+package get_deployment_timeline
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve phases of a deployment with their respective duration.
+	Query struct {
+		bus.Query[[]Phase]
+
+		AppID            string `json:"-"`
+		DeploymentNumber int    `json:"-"`
+	}
+
+	Phase struct {
+		Name       string                 `json:"name"`
+		StartedAt  time.Time              `json:"started_at"`
+		FinishedAt monad.Maybe[time.Time] `json:"finished_at"`
+		Duration   float64                `json:"duration"` // Duration in seconds, up to now if the phase is still running
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_deployment_timeline" }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	artifactManager domain.ArtifactManager,
+) bus.RequestHandler[[]Phase, Query] {
+	return func(ctx context.Context, query Query) ([]Phase, error) {
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(query.AppID),
+			domain.DeploymentNumber(query.DeploymentNumber),
+		))
+
+		if err != nil {
+			return nil, err
+		}
+
+		timings, err := artifactManager.Timeline(ctx, depl)
+
+		if err != nil {
+			return nil, err
+		}
+
+		phases := make([]Phase, len(timings))
+
+		for i, timing := range timings {
+			phases[i] = Phase{
+				Name:       string(timing.Phase),
+				StartedAt:  timing.StartedAt,
+				FinishedAt: timing.FinishedAt,
+				Duration:   timing.FinishedAt.Get(time.Now().UTC()).Sub(timing.StartedAt).Seconds(),
+			}
+		}
+
+		return phases, nil
+	}
+}
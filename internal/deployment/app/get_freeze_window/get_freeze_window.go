@@ -0,0 +1,39 @@
+package get_freeze_window
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+type (
+	// Retrieve one freeze window.
+	Query struct {
+		bus.Query[FreezeWindow]
+
+		ID string `json:"id"`
+	}
+
+	FreezeWindow struct {
+		ID          string                  `json:"id"`
+		Name        string                  `json:"name"`
+		App         monad.Maybe[AppSummary] `json:"app"`         // Empty when applying to every applications
+		Environment monad.Maybe[string]     `json:"environment"` // Empty when applying to every environments
+		Expression  monad.Maybe[string]     `json:"expression"`
+		Duration    monad.Maybe[int]        `json:"duration"` // Duration of each recurring freeze in seconds
+		StartsAt    monad.Maybe[time.Time]  `json:"starts_at"`
+		EndsAt      monad.Maybe[time.Time]  `json:"ends_at"`
+		Active      bool                    `json:"active"` // Is the freeze window currently blocking deployments
+		CreatedAt   time.Time               `json:"created_at"`
+		CreatedBy   app.UserSummary         `json:"created_by"`
+	}
+
+	AppSummary struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_freeze_window" }
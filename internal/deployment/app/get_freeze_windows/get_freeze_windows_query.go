@@ -0,0 +1,17 @@
+package get_freeze_windows
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_freeze_window"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Retrieve all freeze windows, optionally only the ones which may apply to a specific
+// application, global ones included.
+type Query struct {
+	bus.Query[[]get_freeze_window.FreezeWindow]
+
+	AppID monad.Maybe[string] `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_freeze_windows" }
@@ -0,0 +1,44 @@
+package get_notification_channel
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+type (
+	// Retrieve one notification channel.
+	Query struct {
+		bus.Query[NotificationChannel]
+
+		ID string `json:"id"`
+	}
+
+	NotificationChannel struct {
+		ID          string              `json:"id"`
+		Name        string              `json:"name"`
+		Kind        string              `json:"kind"`
+		Destination string              `json:"destination"` // Webhook urls are masked since they usually contain a secret token
+		Template    monad.Maybe[string] `json:"template"`
+		Rules       Rules               `json:"rules"`
+		CreatedAt   time.Time           `json:"created_at"`
+		CreatedBy   app.UserSummary     `json:"created_by"`
+	}
+
+	Rules []Rule
+
+	Rule struct {
+		AppID       monad.Maybe[string] `json:"app_id"`
+		Environment monad.Maybe[string] `json:"environment"`
+		Events      []string            `json:"events"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_notification_channel" }
+
+func (r *Rules) Scan(value any) error {
+	return storage.ScanJSON(value, r)
+}
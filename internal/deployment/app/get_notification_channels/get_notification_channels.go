@@ -0,0 +1,12 @@
+package get_notification_channels
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_notification_channel"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+type Query struct {
+	bus.Query[[]get_notification_channel.NotificationChannel]
+}
+
+func (Query) Name_() string { return "deployment.query.get_notification_channels" }
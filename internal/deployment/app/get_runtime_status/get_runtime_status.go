@@ -0,0 +1,188 @@
+package get_runtime_status
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Maximum time given to a provider to report the status of services.
+const providerTimeout = 10 * time.Second
+
+type (
+	// Retrieve the runtime status of services deployed on a target, optionally
+	// filtered by application and environment.
+	Query struct {
+		bus.Query[Status]
+
+		TargetID    string              `json:"-"`
+		AppID       monad.Maybe[string] `json:"-"`
+		Environment monad.Maybe[string] `json:"-"`
+	}
+
+	Status struct {
+		Services  []Service           `json:"services"`
+		Error     monad.Maybe[string] `json:"error"` // Set when the provider could not be reached
+		CheckedAt time.Time           `json:"checked_at"`
+	}
+
+	Service struct {
+		App          string                 `json:"app_id"`
+		Environment  string                 `json:"environment"`
+		Name         string                 `json:"name"`
+		State        string                 `json:"state"`
+		Health       monad.Maybe[string]    `json:"health"`
+		Healthy      bool                   `json:"healthy"`
+		RestartCount int                    `json:"restart_count"`
+		StartedAt    monad.Maybe[time.Time] `json:"started_at"`
+		Uptime       monad.Maybe[int64]     `json:"uptime"` // In seconds, only set for running services
+		Image        string                 `json:"image"`
+		ImageDigest  monad.Maybe[string]    `json:"image_digest"`
+	}
+
+	cacheKey struct {
+		target domain.TargetID
+		app    domain.AppID
+		env    domain.Environment
+	}
+
+	cacheEntry struct {
+		services  []domain.ServiceStatus
+		err       monad.Maybe[string]
+		checkedAt time.Time
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_runtime_status" }
+
+// Builds the handler. Results (including provider errors) are kept for the given
+// duration to avoid inspecting a remote target on every single request.
+func Handler(
+	reader domain.TargetsReader,
+	provider domain.Provider,
+	cacheDuration time.Duration,
+) bus.RequestHandler[Status, Query] {
+	var (
+		mu    sync.Mutex
+		cache = make(map[cacheKey]cacheEntry)
+	)
+
+	return func(ctx context.Context, q Query) (Status, error) {
+		var (
+			criteria domain.RuntimeStatusCriteria
+			env      domain.Environment
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Maybe(q.Environment, func(s string) error {
+				return validate.Value(s, &env, domain.EnvironmentFrom)
+			}),
+		}); err != nil {
+			return Status{}, err
+		}
+
+		if app, isSet := q.AppID.TryGet(); isSet {
+			criteria.App.Set(domain.AppID(app))
+		}
+
+		if q.Environment.HasValue() {
+			criteria.Environment.Set(env)
+		}
+
+		key := cacheKey{
+			target: domain.TargetID(q.TargetID),
+			app:    criteria.App.Get(""),
+			env:    criteria.Environment.Get(""),
+		}
+		now := time.Now().UTC()
+
+		mu.Lock()
+		entry, found := cache[key]
+		mu.Unlock()
+
+		if !found || now.Sub(entry.checkedAt) >= cacheDuration {
+			target, err := reader.GetByID(ctx, key.target)
+
+			if err != nil {
+				return Status{}, err
+			}
+
+			entry = cacheEntry{checkedAt: now}
+			entry.services, err = runtimeStatus(ctx, provider, target, criteria)
+
+			if err != nil {
+				// Request cancelled by the caller, nothing to do with the target itself
+				if ctx.Err() != nil {
+					return Status{}, ctx.Err()
+				}
+
+				entry.err.Set(err.Error())
+			}
+
+			mu.Lock()
+			pruneExpired(cache, now, cacheDuration)
+			cache[key] = entry
+			mu.Unlock()
+		}
+
+		return entry.status(now), nil
+	}
+}
+
+func runtimeStatus(
+	ctx context.Context,
+	provider domain.Provider,
+	target domain.Target,
+	criteria domain.RuntimeStatusCriteria,
+) ([]domain.ServiceStatus, error) {
+	// Unreachable targets should not block the caller for too long
+	ctx, cancel := context.WithTimeout(ctx, providerTimeout)
+	defer cancel()
+
+	return provider.RuntimeStatus(ctx, target, criteria)
+}
+
+// Remove expired entries so the cache does not grow indefinitely.
+func pruneExpired(cache map[cacheKey]cacheEntry, now time.Time, cacheDuration time.Duration) {
+	for key, entry := range cache {
+		if now.Sub(entry.checkedAt) >= cacheDuration {
+			delete(cache, key)
+		}
+	}
+}
+
+func (e cacheEntry) status(now time.Time) Status {
+	status := Status{
+		Services:  make([]Service, len(e.services)),
+		Error:     e.err,
+		CheckedAt: e.checkedAt,
+	}
+
+	for i, s := range e.services {
+		service := Service{
+			App:          string(s.App),
+			Environment:  string(s.Environment),
+			Name:         s.Name,
+			State:        string(s.State),
+			Health:       s.Health,
+			Healthy:      s.IsHealthy(),
+			RestartCount: s.RestartCount,
+			StartedAt:    s.StartedAt,
+			Image:        s.Image,
+			ImageDigest:  s.ImageDigest,
+		}
+
+		if startedAt, isSet := s.StartedAt.TryGet(); isSet && s.State == domain.ServiceStateRunning {
+			service.Uptime.Set(int64(now.Sub(startedAt).Seconds()))
+		}
+
+		status.Services[i] = service
+	}
+
+	return status
+}
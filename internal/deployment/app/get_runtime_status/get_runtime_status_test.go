@@ -0,0 +1,156 @@
+package get_runtime_status_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_GetRuntimeStatus(t *testing.T) {
+
+	arrange := func(tb testing.TB, provider domain.Provider, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[get_runtime_status.Status, get_runtime_status.Query],
+		context.Context,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return get_runtime_status.Handler(context.TargetsStore, provider, time.Minute), context.Context
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		var provider mockProvider
+		handler, ctx := arrange(t, &provider)
+
+		_, err := handler(ctx, get_runtime_status.Query{
+			Environment: monad.Value("not an environment"),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"environment": domain.ErrInvalidEnvironmentName,
+		}, err)
+	})
+
+	t.Run("should require an existing target", func(t *testing.T) {
+		var provider mockProvider
+		handler, ctx := arrange(t, &provider)
+
+		_, err := handler(ctx, get_runtime_status.Query{
+			TargetID: "an_unknown_target",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should returns services status reported by the provider", func(t *testing.T) {
+		startedAt := time.Now().UTC().Add(-time.Hour)
+		provider := mockProvider{
+			services: []domain.ServiceStatus{
+				{
+					App:          "my-app",
+					Environment:  domain.Production,
+					Name:         "app",
+					State:        domain.ServiceStateRunning,
+					Health:       monad.Value("unhealthy"),
+					RestartCount: 2,
+					StartedAt:    monad.Value(startedAt),
+					Image:        "my-app-production/app:latest",
+					ImageDigest:  monad.Value("sha256:digest"),
+				},
+				{
+					App:         "my-app",
+					Environment: domain.Production,
+					Name:        "db",
+					State:       domain.ServiceStateExited,
+					StartedAt:   monad.Value(startedAt),
+					Image:       "postgres:14-alpine",
+				},
+			},
+		}
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		handler, ctx := arrange(t, &provider,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+		)
+
+		status, err := handler(ctx, get_runtime_status.Query{
+			TargetID:    string(target.ID()),
+			AppID:       monad.Value("my-app"),
+			Environment: monad.Value("production"),
+		})
+
+		assert.Nil(t, err)
+		assert.False(t, status.Error.HasValue())
+		assert.HasLength(t, 2, status.Services)
+		assert.Equal(t, target.ID(), provider.target)
+		assert.Equal(t, monad.Value[domain.AppID]("my-app"), provider.criteria.App)
+		assert.Equal(t, monad.Value(domain.Production), provider.criteria.Environment)
+
+		app := status.Services[0]
+		assert.Equal(t, "running", app.State)
+		assert.False(t, app.Healthy)
+		assert.Equal(t, 2, app.RestartCount)
+		assert.True(t, app.Uptime.MustGet() >= int64(time.Hour.Seconds()))
+		assert.Equal(t, monad.Value("sha256:digest"), app.ImageDigest)
+
+		db := status.Services[1]
+		assert.Equal(t, "exited", db.State)
+		assert.False(t, db.Healthy)
+		assert.False(t, db.Uptime.HasValue())
+	})
+
+	t.Run("should cache the provider result, errors included", func(t *testing.T) {
+		provider := mockProvider{err: errors.New("target_unreachable")}
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		handler, ctx := arrange(t, &provider,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+		)
+
+		first, err := handler(ctx, get_runtime_status.Query{TargetID: string(target.ID())})
+
+		assert.Nil(t, err)
+		assert.Equal(t, monad.Value("target_unreachable"), first.Error)
+
+		second, err := handler(ctx, get_runtime_status.Query{TargetID: string(target.ID())})
+
+		assert.Nil(t, err)
+		assert.Equal(t, first.CheckedAt, second.CheckedAt)
+		assert.Equal(t, 1, provider.calls)
+
+		_, err = handler(ctx, get_runtime_status.Query{
+			TargetID:    string(target.ID()),
+			Environment: monad.Value("staging"),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 2, provider.calls)
+	})
+}
+
+type mockProvider struct {
+	domain.Provider
+	services []domain.ServiceStatus
+	err      error
+	calls    int
+	target   domain.TargetID
+	criteria domain.RuntimeStatusCriteria
+}
+
+func (d *mockProvider) RuntimeStatus(_ context.Context, target domain.Target, criteria domain.RuntimeStatusCriteria) ([]domain.ServiceStatus, error) {
+	d.calls++
+	d.target = target.ID()
+	d.criteria = criteria
+	return d.services, d.err
+}
@@ -0,0 +1,40 @@
+package get_schedule
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Number of upcoming run times returned with a schedule.
+const UpcomingRunsCount = 5
+
+type (
+	// Retrieve one schedule.
+	Query struct {
+		bus.Query[Schedule]
+
+		ID string `json:"id"`
+	}
+
+	Schedule struct {
+		ID           string                 `json:"id"`
+		App          AppSummary             `json:"app"`
+		Environment  string                 `json:"environment"`
+		Expression   string                 `json:"expression"`
+		NextRunAt    time.Time              `json:"next_run_at"`
+		UpcomingRuns []time.Time            `json:"upcoming_runs"` // Next run times, computed in UTC
+		LastRunAt    monad.Maybe[time.Time] `json:"last_run_at"`
+		CreatedAt    time.Time              `json:"created_at"`
+		CreatedBy    app.UserSummary        `json:"created_by"`
+	}
+
+	AppSummary struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+)
+
+func (Query) Name_() string { return "deployment.query.get_schedule" }
@@ -0,0 +1,16 @@
+package get_schedules
+
+import (
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_schedule"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Retrieve all schedules, optionally only the ones of a specific application.
+type Query struct {
+	bus.Query[[]get_schedule.Schedule]
+
+	AppID monad.Maybe[string] `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_schedules" }
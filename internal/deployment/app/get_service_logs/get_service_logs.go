@@ -0,0 +1,94 @@
+package get_service_logs
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+var ErrInvalidSince = apperr.New("invalid_since")
+
+// Retrieve the logs of a running service of an application environment.
+// The caller MUST close the returned reader.
+type Query struct {
+	bus.Query[io.ReadCloser]
+
+	AppID       string              `json:"-"`
+	Environment string              `json:"-"`
+	Service     string              `json:"-"`
+	Since       monad.Maybe[string] `json:"-"` // RFC3339 date or duration relative to now (10m, 1h, ...)
+	Tail        monad.Maybe[uint]   `json:"-"`
+	Follow      bool                `json:"-"`
+}
+
+func (Query) Name_() string { return "deployment.query.get_service_logs" }
+
+func Handler(
+	appsReader domain.AppsReader,
+	targetsReader domain.TargetsReader,
+	provider domain.Provider,
+) bus.RequestHandler[io.ReadCloser, Query] {
+	return func(ctx context.Context, q Query) (io.ReadCloser, error) {
+		var (
+			env     domain.Environment
+			options = domain.ServiceLogsOptions{
+				Tail:   q.Tail,
+				Follow: q.Follow,
+			}
+		)
+
+		if err := validate.Struct(validate.Of{
+			"environment": validate.Value(q.Environment, &env, domain.EnvironmentFrom),
+			"service":     validate.Field(q.Service, strings.Required),
+			"since": validate.Maybe(q.Since, func(s string) error {
+				return validate.Value(s, &options.Since, parseSince)
+			}),
+		}); err != nil {
+			return nil, err
+		}
+
+		app, err := appsReader.GetByID(ctx, domain.AppID(q.AppID))
+
+		if err != nil {
+			return nil, err
+		}
+
+		config, err := app.ConfigFor(env)
+
+		if err != nil {
+			return nil, err
+		}
+
+		target, err := targetsReader.GetByID(ctx, config.Target())
+
+		if err != nil {
+			return nil, err
+		}
+
+		return provider.ServiceLogs(ctx, app.ID(), target, env, q.Service, options)
+	}
+}
+
+func parseSince(value string) (m monad.Maybe[time.Time], err error) {
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		m.Set(date)
+		return m, nil
+	}
+
+	duration, err := time.ParseDuration(value)
+
+	if err != nil || duration < 0 {
+		return m, ErrInvalidSince
+	}
+
+	m.Set(time.Now().UTC().Add(-duration))
+
+	return m, nil
+}
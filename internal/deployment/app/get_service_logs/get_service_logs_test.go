@@ -0,0 +1,118 @@
+package get_service_logs_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_service_logs"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	vstrings "github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+func Test_GetServiceLogs(t *testing.T) {
+
+	arrange := func(tb testing.TB, provider domain.Provider, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[io.ReadCloser, get_service_logs.Query],
+		context.Context,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return get_service_logs.Handler(context.AppsStore, context.TargetsStore, provider), context.Context
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		var provider mockProvider
+		handler, ctx := arrange(t, &provider)
+
+		_, err := handler(ctx, get_service_logs.Query{
+			Since: monad.Value("not a date"),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"environment": domain.ErrInvalidEnvironmentName,
+			"service":     vstrings.ErrRequired,
+			"since":       get_service_logs.ErrInvalidSince,
+		}, err)
+	})
+
+	t.Run("should require an existing app", func(t *testing.T) {
+		var provider mockProvider
+		handler, ctx := arrange(t, &provider)
+
+		_, err := handler(ctx, get_service_logs.Query{
+			AppID:       "an_unknown_app",
+			Environment: "production",
+			Service:     "app",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should retrieve logs from the provider with the environment target", func(t *testing.T) {
+		var provider mockProvider
+		user := authfixture.User()
+		production := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		staging := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(production.ID()),
+				domain.NewEnvironmentConfig(staging.ID()),
+			))
+		handler, ctx := arrange(t, &provider,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&production, &staging),
+			fixture.WithApps(&app),
+		)
+
+		logs, err := handler(ctx, get_service_logs.Query{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Service:     "app",
+			Since:       monad.Value("10m"),
+			Tail:        monad.Value[uint](50),
+			Follow:      true,
+		})
+
+		assert.Nil(t, err)
+		defer logs.Close()
+
+		content, err := io.ReadAll(logs)
+		assert.Nil(t, err)
+		assert.Equal(t, "some logs", string(content))
+
+		assert.Equal(t, app.ID(), provider.app)
+		assert.Equal(t, staging.ID(), provider.target)
+		assert.Equal(t, domain.Staging, provider.env)
+		assert.Equal(t, "app", provider.service)
+		assert.Equal(t, monad.Value[uint](50), provider.options.Tail)
+		assert.True(t, provider.options.Follow)
+		assert.True(t, time.Since(provider.options.Since.MustGet()) >= 10*time.Minute)
+	})
+}
+
+type mockProvider struct {
+	domain.Provider
+	app     domain.AppID
+	target  domain.TargetID
+	env     domain.Environment
+	service string
+	options domain.ServiceLogsOptions
+}
+
+func (d *mockProvider) ServiceLogs(_ context.Context, app domain.AppID, target domain.Target, env domain.Environment, service string, options domain.ServiceLogsOptions) (io.ReadCloser, error) {
+	d.app = app
+	d.target = target.ID()
+	d.env = env
+	d.service = service
+	d.options = options
+	return io.NopCloser(strings.NewReader("some logs")), nil
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
@@ -20,15 +21,17 @@ type (
 	}
 
 	Target struct {
-		ID                 string                       `json:"id"`
-		Name               string                       `json:"name"`
-		Url                monad.Maybe[string]          `json:"url"`
-		Provider           Provider                     `json:"provider"`
-		State              State                        `json:"state"`
-		CleanupRequestedAt monad.Maybe[time.Time]       `json:"cleanup_requested_at"`
-		CleanupRequestedBy monad.Maybe[app.UserSummary] `json:"cleanup_requested_by"`
-		CreatedAt          time.Time                    `json:"created_at"`
-		CreatedBy          app.UserSummary              `json:"created_by"`
+		ID                       string                                 `json:"id"`
+		Name                     string                                 `json:"name"`
+		Url                      monad.Maybe[string]                    `json:"url"`
+		Provider                 Provider                               `json:"provider"`
+		State                    State                                  `json:"state"`
+		MaxConcurrentDeployments monad.Maybe[int]                       `json:"max_concurrent_deployments"` // Unlimited if not set
+		CleanupRequestedAt       monad.Maybe[time.Time]                 `json:"cleanup_requested_at"`
+		CleanupRequestedBy       monad.Maybe[app.UserSummary]           `json:"cleanup_requested_by"`
+		CreatedAt                time.Time                              `json:"created_at"`
+		CreatedBy                app.UserSummary                        `json:"created_by"`
+		Runtime                  monad.Maybe[get_runtime_status.Status] `json:"runtime"` // Only set when retrieving a single target
 	}
 
 	State struct {
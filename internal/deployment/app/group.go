@@ -1,6 +1,8 @@
 package app
 
 import (
+	"strconv"
+
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 )
 
@@ -10,7 +12,18 @@ func DeploymentGroup(config domain.ConfigSnapshot) string {
 	return "deployment.deployment.deploy." + config.ProjectName()
 }
 
+// Concurrency key shared by deployments on a target to enforce its maximum number of
+// concurrent deployments.
+func TargetDeploymentsConcurrencyKey(id domain.TargetID) string {
+	return "deployment.target.deployments." + string(id)
+}
+
 // Group for target operation to prevent multiple target configuration at the same time.
 func TargetConfigurationGroup(id domain.TargetID) string {
 	return "deployment.target.configure." + string(id)
 }
+
+// Group for commit status reports to make sure they are sent in order for a deployment.
+func CommitStatusGroup(id domain.DeploymentID) string {
+	return "deployment.deployment.report_commit_status." + string(id.AppID()) + "-" + strconv.Itoa(int(id.DeploymentNumber()))
+}
@@ -0,0 +1,118 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Send a notification to a channel. Data are resolved when the job is processed so
+// deleted resources (channel, deployment or target) will just skip the notification.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	ChannelID        string    `json:"channel_id"`
+	Event            string    `json:"event"`
+	OccurredAt       time.Time `json:"occurred_at"`
+	AppID            string    `json:"app_id"`
+	DeploymentNumber int       `json:"deployment_number"`
+	TargetID         string    `json:"target_id"`
+}
+
+func (Command) Name_() string        { return "deployment.command.notify" }
+func (c Command) ResourceID() string { return c.ChannelID }
+
+func Handler(
+	channelsReader domain.NotificationChannelsReader,
+	deploymentsReader domain.DeploymentsReader,
+	targetsReader domain.TargetsReader,
+	notifier domain.Notifier,
+	dashboardUrl monad.Maybe[domain.Url],
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		channel, err := channelsReader.GetByID(ctx, domain.NotificationChannelID(cmd.ChannelID))
+
+		if err != nil {
+			return bus.Unit, skipNotFound(err)
+		}
+
+		msg := domain.NotificationMessage{
+			Event:            domain.NotificationEvent(cmd.Event),
+			OccurredAt:       cmd.OccurredAt,
+			AppID:            cmd.AppID,
+			DeploymentNumber: cmd.DeploymentNumber,
+			TargetID:         cmd.TargetID,
+		}
+
+		var target monad.Maybe[domain.Target]
+
+		if msg.DeploymentNumber > 0 {
+			depl, err := deploymentsReader.GetByID(ctx, domain.DeploymentIDFrom(
+				domain.AppID(cmd.AppID),
+				domain.DeploymentNumber(cmd.DeploymentNumber),
+			))
+
+			if err != nil {
+				return bus.Unit, skipNotFound(err)
+			}
+
+			msg.AppName = string(depl.Config().AppName())
+			msg.Environment = string(depl.Config().Environment())
+			msg.TargetID = string(depl.Config().Target())
+			msg.ErrCode = depl.State().ErrCode().Get("")
+
+			if url, isSet := dashboardUrl.TryGet(); isSet {
+				msg.LogsUrl = url.String() + "/apps/" + msg.AppID + "/deployments/" + strconv.Itoa(msg.DeploymentNumber)
+			}
+
+			// The target may have been deleted since, that's not a reason to skip the notification
+			if t, err := targetsReader.GetByID(ctx, depl.Config().Target()); err == nil {
+				target.Set(t)
+			} else if !errors.Is(err, apperr.ErrNotFound) {
+				return bus.Unit, err
+			}
+
+			if t, isSet := target.TryGet(); isSet {
+				if targetUrl, isExposedAutomatically := t.Url().TryGet(); isExposedAutomatically {
+					for _, url := range depl.State().Services().Get(nil).HttpUrls(targetUrl) {
+						msg.Urls = append(msg.Urls, url.String())
+					}
+				}
+			}
+		} else {
+			t, err := targetsReader.GetByID(ctx, domain.TargetID(cmd.TargetID))
+
+			if err != nil {
+				return bus.Unit, skipNotFound(err)
+			}
+
+			target.Set(t)
+
+			msg.ErrCode = t.State().ErrCode().Get("")
+
+			if url, isSet := dashboardUrl.TryGet(); isSet {
+				msg.LogsUrl = url.String() + "/targets/" + msg.TargetID
+			}
+		}
+
+		if t, isSet := target.TryGet(); isSet {
+			msg.TargetName = t.Name()
+		}
+
+		return bus.Unit, notifier.Notify(ctx, channel, msg)
+	}
+}
+
+func skipNotFound(err error) error {
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
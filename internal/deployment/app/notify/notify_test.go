@@ -0,0 +1,156 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/notify"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+func Test_Notify(t *testing.T) {
+
+	dashboardUrl := must.Panic(domain.UrlFrom("https://seelf.example.com"))
+
+	arrange := func(tb testing.TB, notifier domain.Notifier, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, notify.Command],
+		context.Context,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return notify.Handler(
+			context.ChannelsStore,
+			context.DeploymentsStore,
+			context.TargetsStore,
+			notifier,
+			monad.Value(dashboardUrl),
+		), context.Context
+	}
+
+	t.Run("should skip the notification if the channel does not exist anymore", func(t *testing.T) {
+		var notifier mockNotifier
+		handler, ctx := arrange(t, &notifier)
+
+		_, err := handler(ctx, notify.Command{
+			ChannelID: "not-found",
+			Event:     string(domain.NotificationEventTargetFailed),
+			TargetID:  "a-target",
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, notifier.messages)
+	})
+
+	t.Run("should skip the notification if the deployment does not exist anymore", func(t *testing.T) {
+		var notifier mockNotifier
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelCreatedBy(user.ID()))
+		handler, ctx := arrange(t, &notifier, fixture.WithUsers(&user), fixture.WithNotificationChannels(&channel))
+
+		_, err := handler(ctx, notify.Command{
+			ChannelID:        string(channel.ID()),
+			Event:            string(domain.NotificationEventDeploymentFailed),
+			AppID:            "an-app",
+			DeploymentNumber: 1,
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, notifier.messages)
+	})
+
+	t.Run("should send a deployment notification to the channel", func(t *testing.T) {
+		var notifier mockNotifier
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelCreatedBy(user.ID()))
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			))
+		deployment := fixture.Deployment(fixture.FromApp(app), fixture.WithDeploymentRequestedBy(user.ID()))
+		assert.Nil(t, deployment.HasStarted())
+		assert.Nil(t, deployment.HasEnded(domain.Services{}, errors.New("some_error")))
+		handler, ctx := arrange(t, &notifier,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployment),
+			fixture.WithNotificationChannels(&channel),
+		)
+		occurredAt := time.Now().UTC()
+
+		_, err := handler(ctx, notify.Command{
+			ChannelID:        string(channel.ID()),
+			Event:            string(domain.NotificationEventDeploymentFailed),
+			OccurredAt:       occurredAt,
+			AppID:            string(app.ID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+			TargetID:         string(target.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, notifier.messages)
+		assert.Equal(t, channel.ID(), notifier.channels[0].ID())
+		assert.DeepEqual(t, domain.NotificationMessage{
+			Event:            domain.NotificationEventDeploymentFailed,
+			OccurredAt:       occurredAt,
+			AppID:            string(app.ID()),
+			AppName:          string(deployment.Config().AppName()),
+			Environment:      string(domain.Production),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+			TargetID:         string(target.ID()),
+			TargetName:       target.Name(),
+			ErrCode:          "some_error",
+			LogsUrl:          dashboardUrl.String() + "/apps/" + string(app.ID()) + "/deployments/" + strconv.Itoa(int(deployment.ID().DeploymentNumber())),
+		}, notifier.messages[0])
+	})
+
+	t.Run("should send a target notification to the channel", func(t *testing.T) {
+		var notifier mockNotifier
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelCreatedBy(user.ID()))
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		target.Configured(target.CurrentVersion(), nil, errors.New("configuration_failed"))
+		handler, ctx := arrange(t, &notifier,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithNotificationChannels(&channel),
+		)
+
+		_, err := handler(ctx, notify.Command{
+			ChannelID: string(channel.ID()),
+			Event:     string(domain.NotificationEventTargetFailed),
+			TargetID:  string(target.ID()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, notifier.messages)
+		assert.DeepEqual(t, domain.NotificationMessage{
+			Event:      domain.NotificationEventTargetFailed,
+			TargetID:   string(target.ID()),
+			TargetName: target.Name(),
+			ErrCode:    "configuration_failed",
+			LogsUrl:    dashboardUrl.String() + "/targets/" + string(target.ID()),
+		}, notifier.messages[0])
+	})
+}
+
+type mockNotifier struct {
+	channels []domain.NotificationChannel
+	messages []domain.NotificationMessage
+}
+
+func (m *mockNotifier) Notify(_ context.Context, channel domain.NotificationChannel, msg domain.NotificationMessage) error {
+	m.channels = append(m.channels, channel)
+	m.messages = append(m.messages, msg)
+	return nil
+}
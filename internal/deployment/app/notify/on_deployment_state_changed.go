@@ -0,0 +1,72 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a deployment has ended, queue a notification for every subscribed channels.
+func OnDeploymentStateChangedHandler(
+	reader domain.NotificationChannelsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		var notification domain.NotificationEvent
+
+		switch evt.State.Status() {
+		case domain.DeploymentStatusSucceeded:
+			notification = domain.NotificationEventDeploymentSucceeded
+		case domain.DeploymentStatusFailed:
+			notification = domain.NotificationEventDeploymentFailed
+		default:
+			return nil
+		}
+
+		return queue(ctx, reader, scheduler, notification,
+			monad.Value(evt.Config.AppID()),
+			monad.Value(evt.Config.Environment()),
+			Command{
+				Event:            string(notification),
+				OccurredAt:       evt.State.FinishedAt().Get(time.Now().UTC()),
+				AppID:            string(evt.ID.AppID()),
+				DeploymentNumber: int(evt.ID.DeploymentNumber()),
+				TargetID:         string(evt.Config.Target()),
+			})
+	}
+}
+
+// Queue the given command for every channels subscribed to the given event.
+func queue(
+	ctx context.Context,
+	reader domain.NotificationChannelsReader,
+	scheduler bus.Scheduler,
+	notification domain.NotificationEvent,
+	appid monad.Maybe[domain.AppID],
+	env monad.Maybe[domain.Environment],
+	cmd Command,
+) error {
+	channels, err := reader.GetAll(ctx)
+
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		if !channel.IsSubscribedTo(notification, appid, env) {
+			continue
+		}
+
+		cmd.ChannelID = string(channel.ID())
+
+		if err = scheduler.Queue(ctx, cmd, bus.WithRetry(app.ExternalCallRetryPolicy)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// When a target configuration has failed, queue a notification for every subscribed channels.
+func OnTargetStateChangedHandler(
+	reader domain.NotificationChannelsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[domain.TargetStateChanged] {
+	return func(ctx context.Context, evt domain.TargetStateChanged) error {
+		if evt.State.Status() != domain.TargetStatusFailed {
+			return nil
+		}
+
+		return queue(ctx, reader, scheduler, domain.NotificationEventTargetFailed,
+			monad.None[domain.AppID](),
+			monad.None[domain.Environment](),
+			Command{
+				Event:      string(domain.NotificationEventTargetFailed),
+				OccurredAt: time.Now().UTC(),
+				TargetID:   string(evt.ID),
+			})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 )
@@ -14,6 +15,7 @@ type Command struct {
 
 	AppID            string `json:"-"`
 	DeploymentNumber int    `json:"-"`
+	OverrideFreeze   bool   `json:"-"` // Deploy even if a freeze window is active, administrator only
 }
 
 func (Command) Name_() string { return "deployment.command.promote" }
@@ -22,6 +24,7 @@ func Handler(
 	appsReader domain.AppsReader,
 	reader domain.DeploymentsReader,
 	writer domain.DeploymentsWriter,
+	freezeWindowsReader domain.FreezeWindowsReader,
 ) bus.RequestHandler[int, Command] {
 	return func(ctx context.Context, cmd Command) (int, error) {
 		app, err := appsReader.GetByID(ctx, domain.AppID(cmd.AppID))
@@ -48,6 +51,10 @@ func Handler(
 			return 0, err
 		}
 
+		if err := deployment.CheckFreezeWindows(ctx, freezeWindowsReader, &newDeployment, cmd.OverrideFreeze); err != nil {
+			return 0, err
+		}
+
 		if err := writer.Write(ctx, &newDeployment); err != nil {
 			return 0, err
 		}
@@ -23,7 +23,7 @@ func Test_Promote(t *testing.T) {
 		spy.Dispatcher,
 	) {
 		context := fixture.PrepareDatabase(tb, seed...)
-		return promote.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore), context.Context, context.Dispatcher
+		return promote.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore, context.FreezesStore), context.Context, context.Dispatcher
 	}
 
 	t.Run("should fail if application does not exist", func(t *testing.T) {
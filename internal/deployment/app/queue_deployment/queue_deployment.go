@@ -2,8 +2,10 @@ package queue_deployment
 
 import (
 	"context"
+	"time"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/validate"
@@ -14,9 +16,11 @@ import (
 type Command struct {
 	bus.Command[int]
 
-	AppID       string `json:"-"`
-	Environment string `json:"environment" form:"environment"`
-	Source      any    `json:"-"`
+	AppID          string `json:"-"`
+	Environment    string `json:"environment" form:"environment"`
+	NotBefore      string `json:"not_before" form:"not_before"`           // Optional RFC3339 date before which the deployment will not start
+	OverrideFreeze bool   `json:"override_freeze" form:"override_freeze"` // Deploy even if a freeze window is active, administrator only
+	Source         any    `json:"-"`
 }
 
 func (Command) Name_() string { return "deployment.command.queue_deployment" }
@@ -25,13 +29,20 @@ func Handler(
 	appsReader domain.AppsReader,
 	reader domain.DeploymentsReader,
 	writer domain.DeploymentsWriter,
+	freezeWindowsReader domain.FreezeWindowsReader,
 	source domain.Source,
 ) bus.RequestHandler[int, Command] {
 	return func(ctx context.Context, cmd Command) (int, error) {
-		var env domain.Environment
+		var (
+			env       domain.Environment
+			notBefore time.Time
+		)
 
 		if err := validate.Struct(validate.Of{
 			"environment": validate.Value(cmd.Environment, &env, domain.EnvironmentFrom),
+			"not_before": validate.If(cmd.NotBefore != "", func() error {
+				return validate.Value(cmd.NotBefore, &notBefore, domain.NotBeforeFrom)
+			}),
 		}); err != nil {
 			return 0, err
 		}
@@ -54,12 +65,22 @@ func Handler(
 			return 0, err
 		}
 
-		dpl, err := app.NewDeployment(number, meta, env, auth.CurrentUser(ctx).MustGet())
+		var dpl domain.Deployment
+
+		if notBefore.IsZero() {
+			dpl, err = app.NewDeployment(number, meta, env, auth.CurrentUser(ctx).MustGet())
+		} else {
+			dpl, err = app.ScheduleDeployment(number, meta, env, notBefore, auth.CurrentUser(ctx).MustGet())
+		}
 
 		if err != nil {
 			return 0, err
 		}
 
+		if err := deployment.CheckFreezeWindows(ctx, freezeWindowsReader, &dpl, cmd.OverrideFreeze); err != nil {
+			return 0, err
+		}
+
 		if err := writer.Write(ctx, &dpl); err != nil {
 			return 0, err
 		}
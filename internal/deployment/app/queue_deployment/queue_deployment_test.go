@@ -3,7 +3,9 @@ package queue_deployment_test
 import (
 	"context"
 	"testing"
+	"time"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
 	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
 	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
@@ -14,6 +16,8 @@ import (
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/bus/spy"
 	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/validate"
 )
 
@@ -25,7 +29,7 @@ func Test_QueueDeployment(t *testing.T) {
 		spy.Dispatcher,
 	) {
 		context := fixture.PrepareDatabase(tb, seed...)
-		return queue_deployment.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore, raw.New()), context.Context, context.Dispatcher
+		return queue_deployment.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore, context.FreezesStore, raw.New()), context.Context, context.Dispatcher
 	}
 
 	t.Run("should fail if the app does not exist", func(t *testing.T) {
@@ -82,12 +86,14 @@ func Test_QueueDeployment(t *testing.T) {
 		)
 
 		num, err := handler(ctx, queue_deployment.Command{
-			AppID: string(app.ID()),
+			AppID:     string(app.ID()),
+			NotBefore: "tonight",
 		})
 
 		assert.Zero(t, num)
 		assert.ValidationError(t, validate.FieldErrors{
 			"environment": domain.ErrInvalidEnvironmentName,
+			"not_before":  domain.ErrInvalidNotBefore,
 		}, err)
 	})
 
@@ -125,4 +131,126 @@ func Test_QueueDeployment(t *testing.T) {
 			Requested: shared.ActionFrom(user.ID(), assert.NotZero(t, created.Requested.At())),
 		}, created)
 	})
+
+	t.Run("should delay the deployment if a not before date has been given", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		num, err := handler(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			NotBefore:   "2024-03-15T22:00:00+01:00",
+			Source:      "some-payload",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, num)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		created := assert.Is[domain.DeploymentCreated](t, dispatcher.Signals()[0])
+		assert.Equal(t, time.Date(2024, time.March, 15, 21, 0, 0, 0, time.UTC), created.NotBefore.MustGet())
+	})
+
+	t.Run("should fail if the environment is frozen", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		window := frozenNow(app.ID(), user.ID())
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithFreezeWindows(&window),
+		)
+
+		num, err := handler(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "production",
+			Source:      "some-payload",
+		})
+
+		assert.ErrorIs(t, domain.ErrEnvironmentFrozen, err)
+		assert.Zero(t, num)
+		assert.HasLength(t, 0, dispatcher.Signals())
+
+		num, err = handler(ctx, queue_deployment.Command{
+			AppID:       string(app.ID()),
+			Environment: "staging",
+			Source:      "some-payload",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, num)
+	})
+
+	t.Run("should only allow the administrator to override a freeze", func(t *testing.T) {
+		admin := authfixture.User(authfixture.AsAdmin())
+		user := authfixture.User(authfixture.WithEmail("another@example.com"))
+		target := fixture.Target(fixture.WithTargetCreatedBy(admin.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(admin.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		window := frozenNow(app.ID(), admin.ID())
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&admin, &user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithFreezeWindows(&window),
+		)
+
+		num, err := handler(auth.WithUserID(ctx, user.ID()), queue_deployment.Command{
+			AppID:          string(app.ID()),
+			Environment:    "production",
+			OverrideFreeze: true,
+			Source:         "some-payload",
+		})
+
+		assert.ErrorIs(t, domain.ErrFreezeOverrideNotAllowed, err)
+		assert.Zero(t, num)
+
+		num, err = handler(ctx, queue_deployment.Command{
+			AppID:          string(app.ID()),
+			Environment:    "production",
+			OverrideFreeze: true,
+			Source:         "some-payload",
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, 1, num)
+		assert.HasLength(t, 2, dispatcher.Signals())
+		assert.Is[domain.DeploymentCreated](t, dispatcher.Signals()[0])
+		overridden := assert.Is[domain.DeploymentFreezeOverridden](t, dispatcher.Signals()[1])
+		assert.Equal(t, domain.DeploymentIDFrom(app.ID(), 1), overridden.ID)
+	})
+}
+
+func frozenNow(app domain.AppID, by auth.UserID) domain.FreezeWindow {
+	return domain.NewFreezeWindow(
+		"now",
+		monad.Value(app),
+		monad.Value(domain.Production),
+		must.Panic(domain.FixedFreezePeriod(time.Now().Add(-time.Hour), time.Now().Add(time.Hour))),
+		by,
+	)
 }
@@ -23,7 +23,7 @@ func Test_Redeploy(t *testing.T) {
 		spy.Dispatcher,
 	) {
 		context := fixture.PrepareDatabase(tb, seed...)
-		return redeploy.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore), context.Context, context.Dispatcher
+		return redeploy.Handler(context.AppsStore, context.DeploymentsStore, context.DeploymentsStore, context.FreezesStore), context.Context, context.Dispatcher
 	}
 
 	t.Run("should fail if the application does not exist", func(t *testing.T) {
@@ -0,0 +1,44 @@
+package reject_deployment
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Reject a deployment awaiting approval, it will never be run.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"-"`
+	DeploymentNumber int    `json:"-"`
+}
+
+func (Command) Name_() string { return "deployment.command.reject_deployment" }
+
+func Handler(
+	reader domain.DeploymentsReader,
+	writer domain.DeploymentsWriter,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		depl, err := reader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		if err = depl.Reject(); err != nil {
+			return bus.Unit, err
+		}
+
+		if err = writer.Write(ctx, &depl); err != nil {
+			return bus.Unit, err
+		}
+
+		return bus.Unit, nil
+	}
+}
@@ -0,0 +1,22 @@
+package report_commit_status
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When a deployment of a specific commit is created, queue a job to report it as pending.
+func OnDeploymentCreatedHandler(
+	reader domain.AppsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[domain.DeploymentCreated] {
+	return func(ctx context.Context, evt domain.DeploymentCreated) error {
+		if _, isCommit := evt.Source.(domain.CommitSourceData); !isCommit {
+			return nil
+		}
+
+		return queue(ctx, reader, scheduler, evt.ID)
+	}
+}
@@ -0,0 +1,46 @@
+package report_commit_status
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// When a deployment has ended, queue a job to report its final state.
+func OnDeploymentStateChangedHandler(
+	reader domain.AppsReader,
+	scheduler bus.Scheduler,
+) bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(ctx context.Context, evt domain.DeploymentStateChanged) error {
+		if status := evt.State.Status(); status != domain.DeploymentStatusSucceeded && status != domain.DeploymentStatusFailed {
+			return nil
+		}
+
+		return queue(ctx, reader, scheduler, evt.ID)
+	}
+}
+
+// Queue a report job only if the app has a forge configured to avoid useless jobs.
+func queue(
+	ctx context.Context,
+	reader domain.AppsReader,
+	scheduler bus.Scheduler,
+	id domain.DeploymentID,
+) error {
+	a, err := reader.GetByID(ctx, id.AppID())
+
+	if err != nil {
+		return skipNotFound(err)
+	}
+
+	if vcs, hasVCS := a.VersionControl().TryGet(); !hasVCS || !vcs.Forge().HasValue() {
+		return nil
+	}
+
+	return scheduler.Queue(ctx, Command{
+		AppID:            string(id.AppID()),
+		DeploymentNumber: int(id.DeploymentNumber()),
+	}, bus.WithGroup(app.CommitStatusGroup(id)), bus.WithPolicy(bus.JobPolicyRetryPreserveOrder), bus.WithRetry(app.ExternalCallRetryPolicy))
+}
@@ -0,0 +1,124 @@
+package report_commit_status
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+// Report the current state of a deployment as a commit status on the forge configured
+// for the app. The state is resolved when the job is processed so a late job will
+// always report the latest state.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	AppID            string `json:"app_id"`
+	DeploymentNumber int    `json:"deployment_number"`
+}
+
+func (Command) Name_() string        { return "deployment.command.report_commit_status" }
+func (c Command) ResourceID() string { return c.AppID + "-" + strconv.Itoa(c.DeploymentNumber) }
+
+func Handler(
+	appsReader domain.AppsReader,
+	deploymentsReader domain.DeploymentsReader,
+	targetsReader domain.TargetsReader,
+	reporter domain.CommitStatusReporter,
+	dashboardUrl monad.Maybe[domain.Url],
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		depl, err := deploymentsReader.GetByID(ctx, domain.DeploymentIDFrom(
+			domain.AppID(cmd.AppID),
+			domain.DeploymentNumber(cmd.DeploymentNumber),
+		))
+
+		if err != nil {
+			return bus.Unit, skipNotFound(err)
+		}
+
+		source, isCommit := depl.Source().(domain.CommitSourceData)
+
+		if !isCommit || source.Commit() == "" {
+			return bus.Unit, nil
+		}
+
+		app, err := appsReader.GetByID(ctx, depl.ID().AppID())
+
+		if err != nil {
+			return bus.Unit, skipNotFound(err)
+		}
+
+		vcs, hasVCS := app.VersionControl().TryGet()
+
+		// Status reporting may have been disabled since the job has been queued
+		if !hasVCS || !vcs.Forge().HasValue() {
+			return bus.Unit, nil
+		}
+
+		status := domain.CommitStatus{
+			Hash:    source.Commit(),
+			Context: "seelf/" + string(depl.Config().Environment()),
+		}
+
+		if url, isSet := dashboardUrl.TryGet(); isSet {
+			status.TargetUrl.Set(url.String() + "/apps/" + cmd.AppID + "/deployments/" + strconv.Itoa(cmd.DeploymentNumber))
+		}
+
+		switch depl.State().Status() {
+		case domain.DeploymentStatusSucceeded:
+			status.State = domain.CommitStateSuccess
+			status.Description = "Deployment #" + strconv.Itoa(cmd.DeploymentNumber) + " succeeded"
+
+			if url, isSet := deployedUrl(ctx, targetsReader, depl); isSet {
+				status.TargetUrl.Set(url.String())
+			}
+		case domain.DeploymentStatusFailed:
+			status.State = domain.CommitStateFailure
+			status.Description = "Deployment #" + strconv.Itoa(cmd.DeploymentNumber) + " failed: " + depl.State().ErrCode().Get("")
+		case domain.DeploymentStatusAwaitingApproval:
+			status.State = domain.CommitStatePending
+			status.Description = "Deployment #" + strconv.Itoa(cmd.DeploymentNumber) + " awaiting approval"
+		default:
+			status.State = domain.CommitStatePending
+			status.Description = "Deployment #" + strconv.Itoa(cmd.DeploymentNumber) + " in progress"
+		}
+
+		return bus.Unit, reporter.Report(ctx, vcs, status)
+	}
+}
+
+// Retrieve the first url at which the deployed app is reachable, if any.
+func deployedUrl(ctx context.Context, reader domain.TargetsReader, depl domain.Deployment) (domain.Url, bool) {
+	target, err := reader.GetByID(ctx, depl.Config().Target())
+
+	if err != nil {
+		return domain.Url{}, false
+	}
+
+	targetUrl, isExposedAutomatically := target.Url().TryGet()
+
+	if !isExposedAutomatically {
+		return domain.Url{}, false
+	}
+
+	urls := depl.State().Services().Get(nil).HttpUrls(targetUrl)
+
+	if len(urls) == 0 {
+		return domain.Url{}, false
+	}
+
+	return urls[0], true
+}
+
+func skipNotFound(err error) error {
+	if errors.Is(err, apperr.ErrNotFound) {
+		return nil
+	}
+
+	return err
+}
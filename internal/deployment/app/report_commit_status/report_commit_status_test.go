@@ -0,0 +1,162 @@
+package report_commit_status_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/report_commit_status"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+func Test_ReportCommitStatus(t *testing.T) {
+
+	dashboardUrl := must.Panic(domain.UrlFrom("https://seelf.example.com"))
+
+	arrange := func(tb testing.TB, reporter domain.CommitStatusReporter, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, report_commit_status.Command],
+		context.Context,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return report_commit_status.Handler(
+			context.AppsStore,
+			context.DeploymentsStore,
+			context.TargetsStore,
+			reporter,
+			monad.Value(dashboardUrl),
+		), context.Context
+	}
+
+	// Builds an app reporting statuses to a forge with a deployment of the given commit.
+	seed := func(t testing.TB, hash string, withForge bool) (domain.App, *domain.Deployment, []fixture.SeedBuilder) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			))
+		vcs := domain.NewVersionControl(must.Panic(domain.UrlFrom("https://github.com/owner/repo.git")))
+
+		if withForge {
+			vcs.ReportStatusesTo(domain.NewForge(domain.ForgeKindGithub, "token"))
+		}
+
+		assert.Nil(t, app.UseVersionControl(vcs))
+		deployment := fixture.Deployment(
+			fixture.FromApp(app),
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.WithSourceData(fixture.SourceData(fixture.WithVersionControlNeeded(), fixture.WithCommit(hash))),
+		)
+
+		return app, &deployment, []fixture.SeedBuilder{
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&deployment),
+		}
+	}
+
+	t.Run("should skip the report if the deployment does not exist anymore", func(t *testing.T) {
+		var reporter mockReporter
+		handler, ctx := arrange(t, &reporter)
+
+		_, err := handler(ctx, report_commit_status.Command{
+			AppID:            "an-app",
+			DeploymentNumber: 1,
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, reporter.statuses)
+	})
+
+	t.Run("should skip the report if the deployment has no commit", func(t *testing.T) {
+		var reporter mockReporter
+		app, deployment, builders := seed(t, "", true)
+		handler, ctx := arrange(t, &reporter, builders...)
+
+		_, err := handler(ctx, report_commit_status.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, reporter.statuses)
+	})
+
+	t.Run("should skip the report if the app has no forge configured", func(t *testing.T) {
+		var reporter mockReporter
+		app, deployment, builders := seed(t, "abcdef", false)
+		handler, ctx := arrange(t, &reporter, builders...)
+
+		_, err := handler(ctx, report_commit_status.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, reporter.statuses)
+	})
+
+	t.Run("should report a pending deployment", func(t *testing.T) {
+		var reporter mockReporter
+		app, deployment, builders := seed(t, "abcdef", true)
+		handler, ctx := arrange(t, &reporter, builders...)
+
+		_, err := handler(ctx, report_commit_status.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, reporter.statuses)
+		assert.Equal(t, domain.ForgeKindGithub, reporter.vcs[0].Forge().MustGet().Kind())
+		assert.Equal(t, domain.CommitStatus{
+			Hash:        "abcdef",
+			State:       domain.CommitStatePending,
+			Context:     "seelf/production",
+			Description: "Deployment #1 in progress",
+			TargetUrl:   monad.Value(dashboardUrl.String() + "/apps/" + string(app.ID()) + "/deployments/1"),
+		}, reporter.statuses[0])
+	})
+
+	t.Run("should report a failed deployment", func(t *testing.T) {
+		var reporter mockReporter
+		app, deployment, builders := seed(t, "abcdef", true)
+		assert.Nil(t, deployment.HasStarted())
+		assert.Nil(t, deployment.HasEnded(domain.Services{}, errors.New("some_error")))
+		handler, ctx := arrange(t, &reporter, builders...)
+
+		_, err := handler(ctx, report_commit_status.Command{
+			AppID:            string(app.ID()),
+			DeploymentNumber: int(deployment.ID().DeploymentNumber()),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, reporter.statuses)
+		assert.Equal(t, domain.CommitStatus{
+			Hash:        "abcdef",
+			State:       domain.CommitStateFailure,
+			Context:     "seelf/production",
+			Description: "Deployment #1 failed: some_error",
+			TargetUrl:   monad.Value(dashboardUrl.String() + "/apps/" + string(app.ID()) + "/deployments/1"),
+		}, reporter.statuses[0])
+	})
+}
+
+type mockReporter struct {
+	vcs      []domain.VersionControl
+	statuses []domain.CommitStatus
+}
+
+func (m *mockReporter) Report(_ context.Context, vcs domain.VersionControl, status domain.CommitStatus) error {
+	m.vcs = append(m.vcs, vcs)
+	m.statuses = append(m.statuses, status)
+	return nil
+}
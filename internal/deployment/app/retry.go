@@ -0,0 +1,22 @@
+package app
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Retry policy for jobs running on targets (configuration and cleanup). They may fail
+// for a while if the target is not reachable but should not be retried forever.
+var TargetJobRetryPolicy = bus.RetryPolicy{
+	MaxAttempts: 10,
+	Delay:       15 * time.Second,
+	MaxDelay:    30 * time.Minute,
+}
+
+// Retry policy for jobs calling external services such as notifications and commit statuses.
+var ExternalCallRetryPolicy = bus.RetryPolicy{
+	MaxAttempts: 5,
+	Delay:       30 * time.Second,
+	MaxDelay:    15 * time.Minute,
+}
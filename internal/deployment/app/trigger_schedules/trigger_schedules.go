@@ -0,0 +1,109 @@
+package trigger_schedules
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	deployment "github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+// Trigger every schedule which should have run at the given time by redeploying the
+// latest successful deployment of their application environment. Sent periodically
+// by a ticker.
+type Command struct {
+	bus.Command[bus.UnitType]
+
+	At time.Time `json:"at"`
+}
+
+func (Command) Name_() string { return "deployment.command.trigger_schedules" }
+
+func Handler(
+	reader domain.SchedulesReader,
+	writer domain.SchedulesWriter,
+	appsReader domain.AppsReader,
+	deploymentsReader domain.DeploymentsReader,
+	deploymentsWriter domain.DeploymentsWriter,
+	freezeWindowsReader domain.FreezeWindowsReader,
+) bus.RequestHandler[bus.UnitType, Command] {
+	return func(ctx context.Context, cmd Command) (bus.UnitType, error) {
+		schedules, err := reader.GetDue(ctx, cmd.At)
+
+		if err != nil {
+			return bus.Unit, err
+		}
+
+		var errs []error
+
+		for _, schedule := range schedules {
+			// Plan the next run first so a failing schedule will not be retried on every tick
+			schedule.Triggered(cmd.At)
+
+			if err = writer.Write(ctx, &schedule); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+
+			if err = redeploy(ctx, schedule, appsReader, deploymentsReader, deploymentsWriter, freezeWindowsReader); err != nil {
+				errs = append(errs, err)
+			}
+		}
+
+		return bus.Unit, errors.Join(errs...)
+	}
+}
+
+func redeploy(
+	ctx context.Context,
+	schedule domain.Schedule,
+	appsReader domain.AppsReader,
+	deploymentsReader domain.DeploymentsReader,
+	deploymentsWriter domain.DeploymentsWriter,
+	freezeWindowsReader domain.FreezeWindowsReader,
+) error {
+	source, err := deploymentsReader.GetLastSuccessfulDeployment(ctx, schedule.App(), schedule.Environment())
+
+	if err != nil {
+		// Nothing has been successfully deployed yet, nothing to do
+		if errors.Is(err, apperr.ErrNotFound) {
+			return nil
+		}
+
+		return err
+	}
+
+	app, err := appsReader.GetByID(ctx, schedule.App())
+
+	if err != nil {
+		return err
+	}
+
+	number, err := deploymentsReader.GetNextDeploymentNumber(ctx, app.ID())
+
+	if err != nil {
+		return err
+	}
+
+	dpl, err := app.Redeploy(source, number, schedule.Created().By())
+
+	// Could not redeploy, probably because the application is being deleted or the
+	// version control configuration has been removed, just skip it.
+	if err != nil {
+		return nil
+	}
+
+	// Scheduled deployments never override a freeze, the run is skipped instead
+	if err = deployment.CheckFreezeWindows(ctx, freezeWindowsReader, &dpl, false); err != nil {
+		if errors.Is(err, domain.ErrEnvironmentFrozen) {
+			return nil
+		}
+
+		return err
+	}
+
+	return deploymentsWriter.Write(ctx, &dpl)
+}
@@ -0,0 +1,147 @@
+package trigger_schedules_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/trigger_schedules"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+)
+
+func Test_TriggerSchedules(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[bus.UnitType, trigger_schedules.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return trigger_schedules.Handler(
+			context.SchedulesStore,
+			context.SchedulesStore,
+			context.AppsStore,
+			context.DeploymentsStore,
+			context.DeploymentsStore,
+			context.FreezesStore,
+		), context.Context, context.Dispatcher
+	}
+
+	t.Run("should do nothing if no schedule is due", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		schedule := fixture.Schedule(
+			fixture.WithScheduleApp(app),
+			fixture.WithScheduleCreatedBy(user.ID()),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithSchedules(&schedule),
+		)
+
+		_, err := handler(ctx, trigger_schedules.Command{
+			At: time.Now().UTC(),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, dispatcher.Signals())
+	})
+
+	t.Run("should plan the next run even if nothing has been deployed yet", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		schedule := fixture.Schedule(
+			fixture.WithScheduleApp(app),
+			fixture.WithScheduleCreatedBy(user.ID()),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithSchedules(&schedule),
+		)
+		at := time.Now().UTC().Add(48 * time.Hour)
+
+		_, err := handler(ctx, trigger_schedules.Command{
+			At: at,
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		triggered := assert.Is[domain.ScheduleTriggered](t, dispatcher.Signals()[0])
+		assert.Equal(t, schedule.ID(), triggered.ID)
+		assert.Equal(t, at, triggered.RanAt)
+		assert.True(t, triggered.NextRunAt.After(at))
+	})
+
+	t.Run("should redeploy the latest successful deployment of due schedules", func(t *testing.T) {
+		user := authfixture.User()
+		scheduler := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		succeeded := fixture.Deployment(
+			fixture.WithDeploymentRequestedBy(user.ID()),
+			fixture.FromApp(app),
+			fixture.ForEnvironment(domain.Staging),
+		)
+		assert.Nil(t, succeeded.HasStarted())
+		assert.Nil(t, succeeded.HasEnded(domain.Services{}, nil))
+		schedule := fixture.Schedule(
+			fixture.WithScheduleApp(app),
+			fixture.WithScheduleEnvironment(domain.Staging),
+			fixture.WithScheduleCreatedBy(scheduler.ID()),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user, &scheduler),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithDeployments(&succeeded),
+			fixture.WithSchedules(&schedule),
+		)
+
+		_, err := handler(ctx, trigger_schedules.Command{
+			At: time.Now().UTC().Add(48 * time.Hour),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 2, dispatcher.Signals())
+		assert.Is[domain.ScheduleTriggered](t, dispatcher.Signals()[0])
+
+		created := assert.Is[domain.DeploymentCreated](t, dispatcher.Signals()[1])
+		assert.DeepEqual(t, domain.DeploymentCreated{
+			ID:        domain.DeploymentIDFrom(app.ID(), 2),
+			Config:    created.Config,
+			State:     created.State,
+			Source:    succeeded.Source(),
+			Requested: shared.ActionFrom(scheduler.ID(), assert.NotZero(t, created.Requested.At())),
+		}, created)
+	})
+}
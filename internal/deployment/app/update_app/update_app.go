@@ -2,6 +2,7 @@ package update_app
 
 import (
 	"context"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
@@ -20,13 +21,15 @@ type (
 		VersionControl monad.Patch[VersionControl]    `json:"version_control"`
 		Production     monad.Maybe[EnvironmentConfig] `json:"production"`
 		Staging        monad.Maybe[EnvironmentConfig] `json:"staging"`
+		LatestWins     monad.Maybe[bool]              `json:"latest_wins"`
 	}
 
 	EnvironmentConfig create_app.EnvironmentConfig
 
 	VersionControl struct {
-		Url   string              `json:"url"`
-		Token monad.Patch[string] `json:"token"`
+		Url   string                        `json:"url"`
+		Token monad.Patch[string]           `json:"token"`
+		Forge monad.Patch[create_app.Forge] `json:"forge"`
 	}
 )
 
@@ -37,23 +40,33 @@ func Handler(
 	writer domain.AppsWriter,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var url domain.Url
+		var (
+			url               domain.Url
+			forge             domain.Forge
+			productionTimeout monad.Maybe[time.Duration]
+			stagingTimeout    monad.Maybe[time.Duration]
+		)
 
 		if err := validate.Struct(validate.Of{
 			"version_control": validate.Patch(cmd.VersionControl, func(config VersionControl) error {
 				return validate.Struct(validate.Of{
 					"url":   validate.Value(config.Url, &url, domain.UrlFrom),
 					"token": validate.Patch(config.Token, strings.Required),
+					"forge": validate.Patch(config.Forge, func(f create_app.Forge) error {
+						return validate.Value(f, &forge, create_app.BuildForge)
+					}),
 				})
 			}),
 			"production": validate.Maybe(cmd.Production, func(conf EnvironmentConfig) error {
 				return validate.Struct(validate.Of{
-					"target": validate.Field(conf.Target, strings.Required),
+					"target":  validate.Field(conf.Target, strings.Required),
+					"timeout": create_app.ValidateTimeout(conf.Timeout, &productionTimeout),
 				})
 			}),
 			"staging": validate.Maybe(cmd.Staging, func(conf EnvironmentConfig) error {
 				return validate.Struct(validate.Of{
-					"target": validate.Field(conf.Target, strings.Required),
+					"target":  validate.Field(conf.Target, strings.Required),
+					"timeout": create_app.ValidateTimeout(conf.Timeout, &stagingTimeout),
 				})
 			}),
 		}); err != nil {
@@ -70,11 +83,11 @@ func Handler(
 		var productionConfig, stagingConfig monad.Maybe[domain.EnvironmentConfig]
 
 		if conf, isUpdated := cmd.Production.TryGet(); isUpdated {
-			productionConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars))
+			productionConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars, productionTimeout, conf.RequiresApproval))
 		}
 
 		if conf, isUpdated := cmd.Staging.TryGet(); isUpdated {
-			stagingConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars))
+			stagingConfig.Set(create_app.BuildEnvironmentConfig(domain.TargetID(conf.Target), conf.Vars, stagingTimeout, conf.RequiresApproval))
 		}
 
 		productionRequirement, stagingRequirement, err := reader.CheckAppNamingAvailabilityByID(ctx, app.ID(), productionConfig, stagingConfig)
@@ -104,6 +117,14 @@ func Handler(
 					}
 				}
 
+				if forgePatch, isSet := vcsUpdate.Forge.TryGet(); isSet {
+					if forgePatch.HasValue() {
+						vcs.ReportStatusesTo(forge)
+					} else {
+						vcs.NoStatusReporting()
+					}
+				}
+
 				err = app.UseVersionControl(vcs)
 			} else {
 				err = app.RemoveVersionControl()
@@ -114,6 +135,12 @@ func Handler(
 			}
 		}
 
+		if latestWins, isSet := cmd.LatestWins.TryGet(); isSet {
+			if err = app.UseLatestWins(latestWins); err != nil {
+				return "", err
+			}
+		}
+
 		if productionConfig.HasValue() {
 			if err = app.HasProductionConfig(productionRequirement); err != nil {
 				return "", err
@@ -3,8 +3,10 @@ package update_app_test
 import (
 	"context"
 	"testing"
+	"time"
 
 	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/fixture"
@@ -15,6 +17,7 @@ import (
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
 )
 
 func Test_UpdateApp(t *testing.T) {
@@ -205,6 +208,85 @@ func Test_UpdateApp(t *testing.T) {
 		}, changed.Config.Vars().MustGet())
 	})
 
+	t.Run("should update an application deployment timeouts", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		id, err := handler(ctx, update_app.Command{
+			ID: string(app.ID()),
+			Production: monad.Value(update_app.EnvironmentConfig{
+				Target:  string(target.ID()),
+				Timeout: monad.Value(600),
+			}),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, string(app.ID()), id)
+		assert.HasLength(t, 1, dispatcher.Signals())
+
+		changed := assert.Is[domain.AppEnvChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.Production, changed.Environment)
+		assert.Equal(t, 10*time.Minute, changed.Config.Timeout().MustGet())
+	})
+
+	t.Run("should enable the latest wins policy", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		id, err := handler(ctx, update_app.Command{
+			ID:         string(app.ID()),
+			LatestWins: monad.Value(true),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, string(app.ID()), id)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		assert.Equal(t, domain.AppLatestWinsChanged{
+			ID:      app.ID(),
+			Enabled: true,
+		}, assert.Is[domain.AppLatestWinsChanged](t, dispatcher.Signals()[0]))
+	})
+
+	t.Run("should require valid timeouts", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, update_app.Command{
+			ID: "some-app",
+			Staging: monad.Value(update_app.EnvironmentConfig{
+				Target:  "some-target",
+				Timeout: monad.Value(0),
+			}),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"staging.timeout": domain.ErrInvalidDeploymentTimeout,
+		}, err)
+	})
+
 	t.Run("should require valid vcs inputs", func(t *testing.T) {
 		user := authfixture.User()
 		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
@@ -425,4 +507,77 @@ func Test_UpdateApp(t *testing.T) {
 		assert.Equal(t, url, configured.Config.Url())
 		assert.Equal(t, "new token", configured.Config.Token().Get(""))
 	})
+	t.Run("should require valid forge inputs", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		handler, ctx, _ := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		_, err := handler(ctx, update_app.Command{
+			ID: string(app.ID()),
+			VersionControl: monad.PatchValue(update_app.VersionControl{
+				Url:   "https://some.url",
+				Forge: monad.PatchValue(create_app.Forge{Kind: "bitbucket"}),
+			}),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"version_control.forge.kind":  domain.ErrInvalidForgeKind,
+			"version_control.forge.token": strings.ErrRequired,
+		}, err)
+	})
+
+	t.Run("should configure and remove the forge", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		assert.Nil(t, app.UseVersionControl(domain.NewVersionControl(must.Panic(domain.UrlFrom("https://some.url")))))
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+		)
+
+		_, err := handler(ctx, update_app.Command{
+			ID: string(app.ID()),
+			VersionControl: monad.PatchValue(update_app.VersionControl{
+				Url:   "https://some.url",
+				Forge: monad.PatchValue(create_app.Forge{Kind: "gitlab", Token: "forge token"}),
+			}),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, dispatcher.Signals())
+		configured := assert.Is[domain.AppVersionControlConfigured](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.NewForge(domain.ForgeKindGitlab, "forge token"), configured.Config.Forge().Get(domain.Forge{}))
+
+		_, err = handler(ctx, update_app.Command{
+			ID: string(app.ID()),
+			VersionControl: monad.PatchValue(update_app.VersionControl{
+				Url:   "https://some.url",
+				Forge: monad.Nil[create_app.Forge](),
+			}),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 2, dispatcher.Signals())
+		configured = assert.Is[domain.AppVersionControlConfigured](t, dispatcher.Signals()[1])
+		assert.False(t, configured.Config.Forge().HasValue())
+	})
 }
@@ -0,0 +1,85 @@
+package update_notification_channel
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+	"github.com/YuukanOO/seelf/pkg/validate/strings"
+)
+
+// Update an existing notification channel. Its kind could not be changed.
+type Command struct {
+	bus.Command[string]
+
+	ID          string                                          `json:"-"`
+	Name        monad.Maybe[string]                             `json:"name"`
+	Destination monad.Maybe[string]                             `json:"destination"`
+	Template    monad.Patch[string]                             `json:"template"`
+	Rules       monad.Maybe[[]create_notification_channel.Rule] `json:"rules"`
+}
+
+func (Command) Name_() string { return "deployment.command.update_notification_channel" }
+
+func Handler(
+	reader domain.NotificationChannelsReader,
+	writer domain.NotificationChannelsWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var (
+			tmpl  string
+			rules domain.NotificationRules
+		)
+
+		if err := validate.Struct(validate.Of{
+			"name": validate.Maybe(cmd.Name, strings.Required),
+			"template": validate.Patch(cmd.Template, func(t string) error {
+				return validate.Value(t, &tmpl, domain.NotificationTemplateFrom)
+			}),
+			"rules": validate.Maybe(cmd.Rules, func(r []create_notification_channel.Rule) error {
+				return validate.Value(r, &rules, create_notification_channel.BuildRules)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		channel, err := reader.GetByID(ctx, domain.NotificationChannelID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if destination, isSet := cmd.Destination.TryGet(); isSet {
+			if destination, err = channel.Kind().Destination(destination); err != nil {
+				return "", validate.Wrap(err, "destination")
+			}
+
+			channel.HasDestination(destination)
+		}
+
+		if name, isSet := cmd.Name.TryGet(); isSet {
+			channel.Rename(name)
+		}
+
+		if templatePatch, isSet := cmd.Template.TryGet(); isSet {
+			if templatePatch.HasValue() {
+				channel.UseTemplate(monad.Value(tmpl))
+			} else {
+				channel.UseTemplate(monad.None[string]())
+			}
+		}
+
+		if cmd.Rules.HasValue() {
+			channel.SubscribeTo(rules)
+		}
+
+		if err = writer.Write(ctx, &channel); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
@@ -0,0 +1,100 @@
+package update_notification_channel_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_UpdateNotificationChannel(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, update_notification_channel.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return update_notification_channel.Handler(context.ChannelsStore, context.ChannelsStore), context.Context, context.Dispatcher
+	}
+
+	t.Run("should require an existing channel", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, update_notification_channel.Command{
+			ID: "not-found",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should require a destination valid for the channel kind", func(t *testing.T) {
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(
+			fixture.WithNotificationChannelCreatedBy(user.ID()),
+			fixture.WithNotificationChannelDestination(domain.NotificationChannelKindEmail, "john@doe.com"),
+		)
+		handler, ctx, _ := arrange(t, fixture.WithUsers(&user), fixture.WithNotificationChannels(&channel))
+
+		_, err := handler(ctx, update_notification_channel.Command{
+			ID:          string(channel.ID()),
+			Destination: monad.Value("http://example.com"),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"destination": domain.ErrInvalidNotificationDestination,
+		}, err)
+	})
+
+	t.Run("should update the channel", func(t *testing.T) {
+		user := authfixture.User()
+		channel := fixture.NotificationChannel(
+			fixture.WithNotificationChannelCreatedBy(user.ID()),
+			fixture.WithNotificationChannelName("ops"),
+		)
+		handler, ctx, dispatcher := arrange(t, fixture.WithUsers(&user), fixture.WithNotificationChannels(&channel))
+
+		id, err := handler(ctx, update_notification_channel.Command{
+			ID:          string(channel.ID()),
+			Name:        monad.Value("alerts"),
+			Destination: monad.Value("http://example.com/alerts"),
+			Template:    monad.PatchValue("{{ .AppName }}"),
+			Rules: monad.Value([]create_notification_channel.Rule{
+				{Events: []string{"target_failed"}},
+			}),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, string(channel.ID()), id)
+		assert.HasLength(t, 4, dispatcher.Signals())
+
+		assert.Equal(t, domain.NotificationChannelDestinationChanged{
+			ID:          channel.ID(),
+			Destination: "http://example.com/alerts",
+		}, assert.Is[domain.NotificationChannelDestinationChanged](t, dispatcher.Signals()[0]))
+		assert.Equal(t, domain.NotificationChannelRenamed{
+			ID:   channel.ID(),
+			Name: "alerts",
+		}, assert.Is[domain.NotificationChannelRenamed](t, dispatcher.Signals()[1]))
+		assert.Equal(t, domain.NotificationChannelTemplateChanged{
+			ID:       channel.ID(),
+			Template: monad.Value("{{ .AppName }}"),
+		}, assert.Is[domain.NotificationChannelTemplateChanged](t, dispatcher.Signals()[2]))
+		assert.DeepEqual(t, domain.NotificationChannelRulesChanged{
+			ID: channel.ID(),
+			Rules: domain.NotificationRules{
+				{Events: []domain.NotificationEvent{domain.NotificationEventTargetFailed}},
+			},
+		}, assert.Is[domain.NotificationChannelRulesChanged](t, dispatcher.Signals()[3]))
+	})
+}
@@ -0,0 +1,54 @@
+package update_schedule
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/cron"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+// Update an existing schedule. Its application and environment could not be changed.
+type Command struct {
+	bus.Command[string]
+
+	ID         string              `json:"-"`
+	Expression monad.Maybe[string] `json:"expression"`
+}
+
+func (Command) Name_() string { return "deployment.command.update_schedule" }
+
+func Handler(
+	reader domain.SchedulesReader,
+	writer domain.SchedulesWriter,
+) bus.RequestHandler[string, Command] {
+	return func(ctx context.Context, cmd Command) (string, error) {
+		var expr cron.Expression
+
+		if err := validate.Struct(validate.Of{
+			"expression": validate.Maybe(cmd.Expression, func(e string) error {
+				return validate.Value(e, &expr, domain.ScheduleExpressionFrom)
+			}),
+		}); err != nil {
+			return "", err
+		}
+
+		schedule, err := reader.GetByID(ctx, domain.ScheduleID(cmd.ID))
+
+		if err != nil {
+			return "", err
+		}
+
+		if cmd.Expression.HasValue() {
+			schedule.HasExpression(expr)
+		}
+
+		if err = writer.Write(ctx, &schedule); err != nil {
+			return "", err
+		}
+
+		return cmd.ID, nil
+	}
+}
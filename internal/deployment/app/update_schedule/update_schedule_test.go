@@ -0,0 +1,90 @@
+package update_schedule_test
+
+import (
+	"context"
+	"testing"
+
+	authfixture "github.com/YuukanOO/seelf/internal/auth/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/spy"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/validate"
+)
+
+func Test_UpdateSchedule(t *testing.T) {
+
+	arrange := func(tb testing.TB, seed ...fixture.SeedBuilder) (
+		bus.RequestHandler[string, update_schedule.Command],
+		context.Context,
+		spy.Dispatcher,
+	) {
+		context := fixture.PrepareDatabase(tb, seed...)
+		return update_schedule.Handler(context.SchedulesStore, context.SchedulesStore), context.Context, context.Dispatcher
+	}
+
+	t.Run("should require valid inputs", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, update_schedule.Command{
+			Expression: monad.Value("every night"),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"expression": domain.ErrInvalidScheduleExpression,
+		}, err)
+	})
+
+	t.Run("should fail if the schedule does not exist", func(t *testing.T) {
+		handler, ctx, _ := arrange(t)
+
+		_, err := handler(ctx, update_schedule.Command{
+			ID: "some-id",
+		})
+
+		assert.ErrorIs(t, apperr.ErrNotFound, err)
+	})
+
+	t.Run("should update the schedule expression", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(fixture.WithTargetCreatedBy(user.ID()))
+		app := fixture.App(
+			fixture.WithAppCreatedBy(user.ID()),
+			fixture.WithEnvironmentConfig(
+				domain.NewEnvironmentConfig(target.ID()),
+				domain.NewEnvironmentConfig(target.ID()),
+			),
+		)
+		schedule := fixture.Schedule(
+			fixture.WithScheduleApp(app),
+			fixture.WithScheduleCreatedBy(user.ID()),
+		)
+		handler, ctx, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+			fixture.WithApps(&app),
+			fixture.WithSchedules(&schedule),
+		)
+
+		id, err := handler(ctx, update_schedule.Command{
+			ID:         string(schedule.ID()),
+			Expression: monad.Value("@hourly"),
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, string(schedule.ID()), id)
+		assert.HasLength(t, 1, dispatcher.Signals())
+
+		changed := assert.Is[domain.ScheduleExpressionChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.ScheduleExpressionChanged{
+			ID:         schedule.ID(),
+			Expression: must.Panic(domain.ScheduleExpressionFrom("@hourly")),
+			NextRunAt:  assert.NotZero(t, changed.NextRunAt),
+		}, changed)
+	})
+}
@@ -13,10 +13,11 @@ import (
 type Command struct {
 	bus.Command[string]
 
-	ID       string              `json:"-"`
-	Name     monad.Maybe[string] `json:"name"`
-	Url      monad.Patch[string] `json:"url"`
-	Provider any                 `json:"-"`
+	ID                       string              `json:"-"`
+	Name                     monad.Maybe[string] `json:"name"`
+	Url                      monad.Patch[string] `json:"url"`
+	MaxConcurrentDeployments monad.Patch[int]    `json:"max_concurrent_deployments"`
+	Provider                 any                 `json:"-"`
 }
 
 func (Command) Name_() string { return "deployment.command.update_target" }
@@ -27,13 +28,19 @@ func Handler(
 	provider domain.Provider,
 ) bus.RequestHandler[string, Command] {
 	return func(ctx context.Context, cmd Command) (string, error) {
-		var targetUrl domain.Url
+		var (
+			targetUrl      domain.Url
+			maxDeployments int
+		)
 
 		if err := validate.Struct(validate.Of{
 			"name": validate.Maybe(cmd.Name, strings.Required),
 			"url": validate.Patch(cmd.Url, func(s string) error {
 				return validate.Value(s, &targetUrl, domain.UrlFrom)
 			}),
+			"max_concurrent_deployments": validate.Patch(cmd.MaxConcurrentDeployments, func(max int) error {
+				return validate.Value(max, &maxDeployments, domain.MaxConcurrentDeploymentsFrom)
+			}),
 		}); err != nil {
 			return "", err
 		}
@@ -99,6 +106,18 @@ func Handler(
 			}
 		}
 
+		if cmd.MaxConcurrentDeployments.IsSet() {
+			if cmd.MaxConcurrentDeployments.HasValue() {
+				err = target.LimitConcurrentDeployments(maxDeployments)
+			} else {
+				err = target.UnlimitConcurrentDeployments()
+			}
+
+			if err != nil {
+				return "", err
+			}
+		}
+
 		if cmd.Provider != nil {
 			if err = target.HasProvider(configRequirement); err != nil {
 				return "", err
@@ -89,6 +89,51 @@ func Test_UpdateTarget(t *testing.T) {
 		}, urlRemoved)
 	})
 
+	t.Run("should be able to limit and unlimit concurrent deployments", func(t *testing.T) {
+		user := authfixture.User()
+		target := fixture.Target(
+			fixture.WithTargetCreatedBy(user.ID()),
+			fixture.WithProviderConfig(fixture.ProviderConfig(fixture.WithFingerprint("test"))),
+		)
+		handler, dispatcher := arrange(t,
+			fixture.WithUsers(&user),
+			fixture.WithTargets(&target),
+		)
+
+		_, err := handler(context.Background(), update_target.Command{
+			ID:                       string(target.ID()),
+			MaxConcurrentDeployments: monad.PatchValue(0),
+		})
+
+		assert.ValidationError(t, validate.FieldErrors{
+			"max_concurrent_deployments": domain.ErrInvalidMaxConcurrentDeployments,
+		}, err)
+
+		_, err = handler(context.Background(), update_target.Command{
+			ID:                       string(target.ID()),
+			MaxConcurrentDeployments: monad.PatchValue(2),
+		})
+
+		assert.Nil(t, err)
+
+		_, err = handler(context.Background(), update_target.Command{
+			ID:                       string(target.ID()),
+			MaxConcurrentDeployments: monad.Nil[int](),
+		})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 2, dispatcher.Signals())
+		changed := assert.Is[domain.TargetMaxDeploymentsChanged](t, dispatcher.Signals()[0])
+		assert.Equal(t, domain.TargetMaxDeploymentsChanged{
+			ID:  target.ID(),
+			Max: 2,
+		}, changed)
+		removed := assert.Is[domain.TargetMaxDeploymentsRemoved](t, dispatcher.Signals()[1])
+		assert.Equal(t, domain.TargetMaxDeploymentsRemoved{
+			ID: target.ID(),
+		}, removed)
+	})
+
 	t.Run("should update the target if everything is good", func(t *testing.T) {
 		user := authfixture.User()
 		target := fixture.Target(
@@ -34,6 +34,7 @@ type (
 		versionControl   monad.Maybe[VersionControl]
 		production       EnvironmentConfig
 		staging          EnvironmentConfig
+		latestWins       bool // Supersede older pending deployments when a new one is queued
 		cleanupRequested monad.Maybe[shared.Action[domain.UserID]]
 		created          shared.Action[domain.UserID]
 	}
@@ -94,6 +95,13 @@ type (
 		ID AppID
 	}
 
+	AppLatestWinsChanged struct {
+		bus.Notification
+
+		ID      AppID
+		Enabled bool
+	}
+
 	AppCleanupRequested struct {
 		bus.Notification
 
@@ -116,6 +124,7 @@ func (AppVersionControlConfigured) Name_() string {
 	return "deployment.event.app_version_control_configured"
 }
 func (AppVersionControlRemoved) Name_() string { return "deployment.event.app_version_control_removed" }
+func (AppLatestWinsChanged) Name_() string     { return "deployment.event.app_latest_wins_changed" }
 func (AppCleanupRequested) Name_() string      { return "deployment.event.app_cleanup_requested" }
 func (AppDeleted) Name_() string               { return "deployment.event.app_deleted" }
 
@@ -156,6 +165,10 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 	var (
 		url                monad.Maybe[Url]
 		token              monad.Maybe[string]
+		forgeKind          monad.Maybe[string]
+		forgeToken         monad.Maybe[string]
+		productionTimeout  monad.Maybe[int64]
+		stagingTimeout     monad.Maybe[int64]
 		createdAt          time.Time
 		createdBy          domain.UserID
 		cleanupRequestedAt monad.Maybe[time.Time]
@@ -167,12 +180,19 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 		&a.name,
 		&url,
 		&token,
+		&forgeKind,
+		&forgeToken,
 		&a.production.target,
 		&a.production.version,
 		&a.production.vars,
+		&productionTimeout,
+		&a.production.approval,
 		&a.staging.target,
 		&a.staging.version,
 		&a.staging.vars,
+		&stagingTimeout,
+		&a.staging.approval,
+		&a.latestWins,
 		&cleanupRequestedAt,
 		&cleanupRequestedBy,
 		&createdAt,
@@ -181,6 +201,14 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 
 	a.created = shared.ActionFrom(createdBy, createdAt)
 
+	if seconds, isSet := productionTimeout.TryGet(); isSet {
+		a.production.timeout.Set(time.Duration(seconds) * time.Second)
+	}
+
+	if seconds, isSet := stagingTimeout.TryGet(); isSet {
+		a.staging.timeout.Set(time.Duration(seconds) * time.Second)
+	}
+
 	if requestedAt, isSet := cleanupRequestedAt.TryGet(); isSet {
 		a.cleanupRequested.Set(
 			shared.ActionFrom(domain.UserID(cleanupRequestedBy.MustGet()), requestedAt),
@@ -195,6 +223,10 @@ func AppFrom(scanner storage.Scanner) (a App, err error) {
 			vcs.Authenticated(tok)
 		}
 
+		if kind, isSet := forgeKind.TryGet(); isSet {
+			vcs.ReportStatusesTo(NewForge(ForgeKind(kind), forgeToken.Get("")))
+		}
+
 		a.versionControl.Set(vcs)
 	}
 
@@ -236,6 +268,25 @@ func (a *App) RemoveVersionControl() error {
 	return nil
 }
 
+// Enables or disables the "latest wins" policy. When enabled, queuing a new deployment
+// fails older pending deployments of the same environment.
+func (a *App) UseLatestWins(enabled bool) error {
+	if a.cleanupRequested.HasValue() {
+		return ErrAppCleanupRequested
+	}
+
+	if a.latestWins == enabled {
+		return nil
+	}
+
+	a.apply(AppLatestWinsChanged{
+		ID:      a.id,
+		Enabled: enabled,
+	})
+
+	return nil
+}
+
 // Updates the production configuration for this application.
 func (a *App) HasProductionConfig(configRequirement EnvironmentConfigRequirement) error {
 	return a.tryUpdateEnvironmentConfig(Production, a.production, configRequirement)
@@ -273,8 +324,21 @@ func (a *App) Delete(cleanedUp bool) error {
 	return nil
 }
 
+// Retrieve the configuration of the given environment.
+func (a *App) ConfigFor(env Environment) (EnvironmentConfig, error) {
+	switch env {
+	case Production:
+		return a.production, nil
+	case Staging:
+		return a.staging, nil
+	default:
+		return EnvironmentConfig{}, ErrInvalidEnvironmentName
+	}
+}
+
 func (a *App) ID() AppID                                   { return a.id }
 func (a *App) VersionControl() monad.Maybe[VersionControl] { return a.versionControl }
+func (a *App) LatestWins() bool                            { return a.latestWins }
 
 func (a *App) tryUpdateEnvironmentConfig(
 	env Environment,
@@ -327,6 +391,8 @@ func (a *App) apply(e event.Event) {
 		a.versionControl.Set(evt.Config)
 	case AppVersionControlRemoved:
 		a.versionControl.Unset()
+	case AppLatestWinsChanged:
+		a.latestWins = evt.Enabled
 	case AppCleanupRequested:
 		a.cleanupRequested.Set(evt.Requested)
 	}
@@ -146,6 +146,31 @@ func Test_App(t *testing.T) {
 		assert.ErrorIs(t, domain.ErrAppCleanupRequested, app.RemoveVersionControl())
 	})
 
+	t.Run("could enable the latest wins policy and raise the event only if different", func(t *testing.T) {
+		app := fixture.App()
+
+		assert.False(t, app.LatestWins())
+		assert.Nil(t, app.UseLatestWins(false))
+		assert.HasNEvents(t, 1, &app, "should have nothing new since the policy is disabled by default")
+
+		assert.Nil(t, app.UseLatestWins(true))
+		assert.Nil(t, app.UseLatestWins(true))
+
+		assert.True(t, app.LatestWins())
+		assert.HasNEvents(t, 2, &app, "should raise the event only once")
+		assert.Equal(t, domain.AppLatestWinsChanged{
+			ID:      app.ID(),
+			Enabled: true,
+		}, assert.EventIs[domain.AppLatestWinsChanged](t, &app, 1))
+	})
+
+	t.Run("does not allow to change the latest wins policy if the app is marked for deletion", func(t *testing.T) {
+		app := fixture.App()
+		app.RequestCleanup("uid")
+
+		assert.ErrorIs(t, domain.ErrAppCleanupRequested, app.UseLatestWins(true))
+	})
+
 	t.Run("need the app naming to be available when modifying a configuration", func(t *testing.T) {
 		app := fixture.App()
 
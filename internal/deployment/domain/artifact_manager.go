@@ -20,6 +20,8 @@ type (
 		Cleanup(context.Context, AppID) error
 		// Returns the absolute path to a deployment log file.
 		LogPath(context.Context, Deployment) string
+		// Returns the phases a deployment went through based on its logs.
+		Timeline(context.Context, Deployment) ([]DeploymentPhaseTiming, error)
 	}
 )
 
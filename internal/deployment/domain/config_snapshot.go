@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/monad"
 )
@@ -15,22 +17,19 @@ type ConfigSnapshot struct {
 	environment Environment
 	target      TargetID
 	vars        monad.Maybe[ServicesEnv]
+	timeout     monad.Maybe[time.Duration]
+
+	requiresApproval bool // Only used when creating the deployment to determine its initial state
 }
 
 // Builds a new config snapshot for the given environment.
 func (a *App) configSnapshotFor(env Environment) (ConfigSnapshot, error) {
-	var (
-		conf     EnvironmentConfig
-		snapshot ConfigSnapshot
-	)
-
-	switch env {
-	case Production:
-		conf = a.production
-	case Staging:
-		conf = a.staging
-	default:
-		return snapshot, ErrInvalidEnvironmentName
+	var snapshot ConfigSnapshot
+
+	conf, err := a.ConfigFor(env)
+
+	if err != nil {
+		return snapshot, err
 	}
 
 	snapshot.appid = a.id
@@ -38,15 +37,18 @@ func (a *App) configSnapshotFor(env Environment) (ConfigSnapshot, error) {
 	snapshot.environment = env
 	snapshot.target = conf.Target()
 	snapshot.vars = conf.Vars()
+	snapshot.timeout = conf.Timeout()
+	snapshot.requiresApproval = conf.RequiresApproval()
 
 	return snapshot, nil
 }
 
-func (c ConfigSnapshot) AppID() AppID                   { return c.appid }
-func (c ConfigSnapshot) AppName() AppName               { return c.appname }
-func (c ConfigSnapshot) Environment() Environment       { return c.environment }
-func (c ConfigSnapshot) Target() TargetID               { return c.target }
-func (c ConfigSnapshot) Vars() monad.Maybe[ServicesEnv] { return c.vars } // FIXME: If I want to follow my mantra, it should returns a readonly map
+func (c ConfigSnapshot) AppID() AppID                        { return c.appid }
+func (c ConfigSnapshot) AppName() AppName                    { return c.appname }
+func (c ConfigSnapshot) Environment() Environment            { return c.environment }
+func (c ConfigSnapshot) Target() TargetID                    { return c.target }
+func (c ConfigSnapshot) Vars() monad.Maybe[ServicesEnv]      { return c.vars } // FIXME: If I want to follow my mantra, it should returns a readonly map
+func (c ConfigSnapshot) Timeout() monad.Maybe[time.Duration] { return c.timeout }
 
 // Retrieve environment variables associated with the given service name.
 // FIXME: If I want to follow my mantra, it should returns a readonly map
@@ -100,3 +102,44 @@ func (c ConfigSnapshot) imageName(service string) string {
 func (c ConfigSnapshot) qualifiedName(service string) string {
 	return c.ProjectName() + "-" + service
 }
+
+// Type needed to marshal an unexposed ConfigSnapshot data.
+type marshalledConfigSnapshot struct {
+	AppID            AppID                      `json:"app_id"`
+	AppName          AppName                    `json:"app_name"`
+	Environment      Environment                `json:"environment"`
+	Target           TargetID                   `json:"target"`
+	Vars             monad.Maybe[ServicesEnv]   `json:"vars"`
+	Timeout          monad.Maybe[time.Duration] `json:"timeout"`
+	RequiresApproval bool                       `json:"requires_approval"`
+}
+
+func (c ConfigSnapshot) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalledConfigSnapshot{
+		AppID:            c.appid,
+		AppName:          c.appname,
+		Environment:      c.environment,
+		Target:           c.target,
+		Vars:             c.vars,
+		Timeout:          c.timeout,
+		RequiresApproval: c.requiresApproval,
+	})
+}
+
+func (c *ConfigSnapshot) UnmarshalJSON(b []byte) error {
+	var m marshalledConfigSnapshot
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	c.appid = m.AppID
+	c.appname = m.AppName
+	c.environment = m.Environment
+	c.target = m.Target
+	c.vars = m.Vars
+	c.timeout = m.Timeout
+	c.requiresApproval = m.RequiresApproval
+
+	return nil
+}
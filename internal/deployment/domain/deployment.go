@@ -2,6 +2,8 @@ package domain
 
 import (
 	"context"
+	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	"github.com/YuukanOO/seelf/internal/auth/domain"
@@ -19,6 +21,15 @@ var (
 	ErrInvalidSourceDeployment             = apperr.New("invalid_source_deployment")
 	ErrNotInPendingState                   = apperr.New("not_in_pending_state")
 	ErrNotInRunningState                   = apperr.New("not_in_running_state")
+	ErrDeploymentCancelled                 = apperr.New("deployment_cancelled")
+	ErrDeploymentSuperseded                = apperr.New("deployment_superseded")
+	ErrDeploymentAlreadyEnded              = apperr.New("deployment_already_ended")
+	ErrDeploymentTimeout                   = apperr.New("deployment_timeout")
+	ErrNotAwaitingApproval                 = apperr.New("not_awaiting_approval")
+	ErrSelfApprovalNotAllowed              = apperr.New("self_approval_not_allowed")
+	ErrDeploymentRejected                  = apperr.New("deployment_rejected")
+	ErrDeploymentApprovalExpired           = apperr.New("deployment_approval_expired")
+	ErrServerReset                         = apperr.New("server_reset")
 )
 
 const (
@@ -26,6 +37,7 @@ const (
 	DeploymentStatusRunning
 	DeploymentStatusFailed
 	DeploymentStatusSucceeded
+	DeploymentStatusAwaitingApproval
 )
 
 type (
@@ -41,12 +53,19 @@ type (
 		state     DeploymentState
 		source    SourceData
 		requested shared.Action[domain.UserID]
+		notBefore monad.Maybe[time.Time] // Set for deployments scheduled at a later time
+		approved  monad.Maybe[shared.Action[domain.UserID]]
+		overrode  bool // Requested during a freeze window by the administrator
 	}
 
 	DeploymentsReader interface {
 		GetByID(context.Context, DeploymentID) (Deployment, error)
 		GetLastDeployment(context.Context, AppID, Environment) (Deployment, error)
+		GetLastSuccessfulDeployment(context.Context, AppID, Environment) (Deployment, error)
 		GetNextDeploymentNumber(context.Context, AppID) (DeploymentNumber, error)
+		// Retrieve deployments of an app environment older than the given number which could be superseded,
+		// ie. not started yet and not scheduled for a later time.
+		GetSupersedable(context.Context, AppID, Environment, DeploymentNumber) ([]Deployment, error)
 		HasRunningOrPendingDeploymentsOnTarget(context.Context, TargetID) (HasRunningOrPendingDeploymentsOnTarget, error)
 		// Retrieve running or pending deployments count for a specific app, target and environment and the successful deployments count
 		// during the specified interval.
@@ -54,10 +73,11 @@ type (
 	}
 
 	FailCriteria struct {
-		Status      monad.Maybe[DeploymentStatus]
+		Status      []DeploymentStatus // Empty means any status
 		Target      monad.Maybe[TargetID]
 		App         monad.Maybe[AppID]
 		Environment monad.Maybe[Environment]
+		Except      []DeploymentID // Deployments to leave untouched
 	}
 
 	DeploymentsWriter interface {
@@ -73,6 +93,7 @@ type (
 		State     DeploymentState
 		Source    SourceData
 		Requested shared.Action[domain.UserID]
+		NotBefore monad.Maybe[time.Time]
 	}
 
 	DeploymentStateChanged struct {
@@ -82,15 +103,39 @@ type (
 		Config ConfigSnapshot
 		State  DeploymentState
 	}
+
+	DeploymentApproved struct {
+		bus.Notification
+
+		ID        DeploymentID
+		Config    ConfigSnapshot
+		Approved  shared.Action[domain.UserID]
+		NotBefore monad.Maybe[time.Time]
+	}
+
+	DeploymentFreezeOverridden struct {
+		bus.Notification
+
+		ID DeploymentID
+	}
 )
 
 func (DeploymentCreated) Name_() string      { return "deployment.event.deployment_created" }
 func (DeploymentStateChanged) Name_() string { return "deployment.event.deployment_state_changed" }
+func (DeploymentApproved) Name_() string     { return "deployment.event.deployment_approved" }
+func (DeploymentFreezeOverridden) Name_() string {
+	return "deployment.event.deployment_freeze_overridden"
+}
 
 func (e DeploymentStateChanged) HasSucceeded() bool {
 	return e.State.status == DeploymentStatusSucceeded
 }
 
+// Returns true if the deployment must be approved before being run.
+func (e DeploymentCreated) IsAwaitingApproval() bool {
+	return e.State.status == DeploymentStatusAwaitingApproval
+}
+
 // Creates a new deployment for this app. This method acts as a factory for the deployment
 // entity to make sure a new deployment can be created for an app.
 func (a *App) NewDeployment(
@@ -98,6 +143,27 @@ func (a *App) NewDeployment(
 	meta SourceData,
 	env Environment,
 	requestedBy domain.UserID,
+) (Deployment, error) {
+	return a.newDeployment(deployNumber, meta, env, monad.None[time.Time](), requestedBy)
+}
+
+// Creates a new deployment which will not be started before the given time.
+func (a *App) ScheduleDeployment(
+	deployNumber DeploymentNumber,
+	meta SourceData,
+	env Environment,
+	notBefore time.Time,
+	requestedBy domain.UserID,
+) (Deployment, error) {
+	return a.newDeployment(deployNumber, meta, env, monad.Value(notBefore.UTC()), requestedBy)
+}
+
+func (a *App) newDeployment(
+	deployNumber DeploymentNumber,
+	meta SourceData,
+	env Environment,
+	notBefore monad.Maybe[time.Time],
+	requestedBy domain.UserID,
 ) (d Deployment, err error) {
 	if a.cleanupRequested.HasValue() {
 		return d, ErrAppCleanupRequested
@@ -113,11 +179,19 @@ func (a *App) NewDeployment(
 		return d, err
 	}
 
+	var state DeploymentState
+
+	if conf.requiresApproval {
+		state.status = DeploymentStatusAwaitingApproval
+	}
+
 	d.apply(DeploymentCreated{
 		ID:        DeploymentIDFrom(a.id, deployNumber),
 		Config:    conf,
+		State:     state,
 		Source:    meta,
 		Requested: shared.NewAction(requestedBy),
+		NotBefore: notBefore,
 	})
 
 	return d, nil
@@ -125,8 +199,11 @@ func (a *App) NewDeployment(
 
 func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 	var (
+		timeout                 monad.Maybe[int64]
 		requestedAt             time.Time
 		requestedBy             domain.UserID
+		approvedAt              monad.Maybe[time.Time]
+		approvedBy              monad.Maybe[string]
 		sourceMetaDiscriminator string
 		sourceMetaData          string
 	)
@@ -139,6 +216,7 @@ func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 		&d.config.environment,
 		&d.config.target,
 		&d.config.vars,
+		&timeout,
 		&d.state.status,
 		&d.state.errcode,
 		&d.state.services,
@@ -148,12 +226,24 @@ func DeploymentFrom(scanner storage.Scanner) (d Deployment, err error) {
 		&sourceMetaData,
 		&requestedAt,
 		&requestedBy,
+		&d.notBefore,
+		&approvedAt,
+		&approvedBy,
+		&d.overrode,
 	)
 
 	if err != nil {
 		return d, err
 	}
 
+	if at, isSet := approvedAt.TryGet(); isSet {
+		d.approved.Set(shared.ActionFrom(domain.UserID(approvedBy.MustGet()), at))
+	}
+
+	if seconds, isSet := timeout.TryGet(); isSet {
+		d.config.timeout.Set(time.Duration(seconds) * time.Second)
+	}
+
 	d.source, err = SourceDataTypes.From(sourceMetaDiscriminator, sourceMetaData)
 	d.requested = shared.ActionFrom(requestedBy, requestedAt)
 
@@ -193,7 +283,13 @@ func (a *App) Promote(
 func (d *Deployment) ID() DeploymentID                        { return d.id }
 func (d *Deployment) Config() ConfigSnapshot                  { return d.config }
 func (d *Deployment) Source() SourceData                      { return d.source }
+func (d *Deployment) State() DeploymentState                  { return d.state }
 func (d *Deployment) Requested() shared.Action[domain.UserID] { return d.requested }
+func (d *Deployment) NotBefore() monad.Maybe[time.Time]       { return d.notBefore }
+func (d *Deployment) Approved() monad.Maybe[shared.Action[domain.UserID]] {
+	return d.approved
+}
+func (d *Deployment) FreezeOverridden() bool { return d.overrode }
 
 // Mark a deployment has started.
 func (d *Deployment) HasStarted() error {
@@ -218,6 +314,113 @@ func (d *Deployment) HasEnded(services Services, deploymentErr error) error {
 	return nil
 }
 
+// Cancel a pending or running deployment. It will be marked as failed with the
+// ErrDeploymentCancelled error code.
+func (d *Deployment) Cancel() error {
+	if err := d.state.cancelled(); err != nil {
+		return err
+	}
+
+	d.stateChanged()
+
+	return nil
+}
+
+// Fail a deployment which has not been started yet because a newer one has been queued
+// for the same environment, with the ErrDeploymentSuperseded error code.
+func (d *Deployment) Supersede() error {
+	if err := d.state.superseded(); err != nil {
+		return err
+	}
+
+	d.stateChanged()
+
+	return nil
+}
+
+// Approve a deployment awaiting approval so it could be run. The approver must be
+// another user than the one who requested the deployment.
+func (d *Deployment) Approve(approvedBy domain.UserID) error {
+	if d.state.status == DeploymentStatusAwaitingApproval && d.requested.By() == approvedBy {
+		return ErrSelfApprovalNotAllowed
+	}
+
+	if err := d.state.approved(); err != nil {
+		return err
+	}
+
+	d.stateChanged()
+	d.apply(DeploymentApproved{
+		ID:        d.id,
+		Config:    d.config,
+		Approved:  shared.NewAction(approvedBy),
+		NotBefore: d.notBefore,
+	})
+
+	return nil
+}
+
+// Reject a deployment awaiting approval. It will be marked as failed with the
+// ErrDeploymentRejected error code.
+func (d *Deployment) Reject() error {
+	if err := d.state.dismissed(ErrDeploymentRejected); err != nil {
+		return err
+	}
+
+	d.stateChanged()
+
+	return nil
+}
+
+// Fail a deployment which has not been approved in time with the ErrDeploymentApprovalExpired
+// error code.
+func (d *Deployment) ApprovalExpired() error {
+	if err := d.state.dismissed(ErrDeploymentApprovalExpired); err != nil {
+		return err
+	}
+
+	d.stateChanged()
+
+	return nil
+}
+
+// Check the given freeze windows against the time at which this deployment should run.
+// When frozen, the deployment is only allowed if the override flag is set by someone
+// allowed to do so, in which case it is recorded on the deployment.
+func (d *Deployment) CheckFreezeWindows(
+	windows []FreezeWindow,
+	override bool,
+	allowed FreezeOverrideAllowed,
+) error {
+	at := d.notBefore.Get(d.requested.At())
+	frozen := false
+
+	for _, w := range windows {
+		if w.IsFrozen(d.config.appid, d.config.environment, at) {
+			frozen = true
+			break
+		}
+	}
+
+	if !frozen {
+		return nil
+	}
+
+	if !override {
+		return ErrEnvironmentFrozen
+	}
+
+	if !allowed {
+		return ErrFreezeOverrideNotAllowed
+	}
+
+	d.apply(DeploymentFreezeOverridden{
+		ID: d.id,
+	})
+
+	return nil
+}
+
 func (d *Deployment) stateChanged() {
 	d.apply(DeploymentStateChanged{
 		ID:     d.id,
@@ -234,8 +437,13 @@ func (d *Deployment) apply(e event.Event) {
 		d.state = evt.State
 		d.source = evt.Source
 		d.requested = evt.Requested
+		d.notBefore = evt.NotBefore
 	case DeploymentStateChanged:
 		d.state = evt.State
+	case DeploymentApproved:
+		d.approved.Set(evt.Approved)
+	case DeploymentFreezeOverridden:
+		d.overrode = true
 	}
 
 	event.Store(d, e)
@@ -256,6 +464,11 @@ type (
 	}
 )
 
+// Returns true if the status is a final one, meaning the deployment will not change anymore.
+func (s DeploymentStatus) IsFinal() bool {
+	return s == DeploymentStatusFailed || s == DeploymentStatusSucceeded
+}
+
 func (s *DeploymentState) started() error {
 	if s.status != DeploymentStatusPending {
 		return ErrNotInPendingState
@@ -291,8 +504,168 @@ func (s *DeploymentState) ended(services Services, err error) error {
 	return nil
 }
 
+func (s *DeploymentState) cancelled() error {
+	if s.status.IsFinal() {
+		return ErrDeploymentAlreadyEnded
+	}
+
+	now := time.Now().UTC()
+
+	if !s.startedAt.HasValue() {
+		s.startedAt.Set(now)
+	}
+
+	s.status = DeploymentStatusFailed
+	s.errcode.Set(ErrDeploymentCancelled.Error())
+	s.finishedAt.Set(now)
+
+	return nil
+}
+
+func (s *DeploymentState) superseded() error {
+	if s.status != DeploymentStatusPending && s.status != DeploymentStatusAwaitingApproval {
+		return ErrNotInPendingState
+	}
+
+	now := time.Now().UTC()
+
+	s.status = DeploymentStatusFailed
+	s.errcode.Set(ErrDeploymentSuperseded.Error())
+	s.startedAt.Set(now)
+	s.finishedAt.Set(now)
+
+	return nil
+}
+
+func (s *DeploymentState) approved() error {
+	if s.status != DeploymentStatusAwaitingApproval {
+		return ErrNotAwaitingApproval
+	}
+
+	s.status = DeploymentStatusPending
+
+	return nil
+}
+
+func (s *DeploymentState) dismissed(reason error) error {
+	if s.status != DeploymentStatusAwaitingApproval {
+		return ErrNotAwaitingApproval
+	}
+
+	now := time.Now().UTC()
+
+	s.status = DeploymentStatusFailed
+	s.errcode.Set(reason.Error())
+	s.startedAt.Set(now)
+	s.finishedAt.Set(now)
+
+	return nil
+}
+
 func (s DeploymentState) Status() DeploymentStatus           { return s.status }
 func (s DeploymentState) ErrCode() monad.Maybe[string]       { return s.errcode }
 func (s DeploymentState) Services() monad.Maybe[Services]    { return s.services }
 func (s DeploymentState) StartedAt() monad.Maybe[time.Time]  { return s.startedAt }
 func (s DeploymentState) FinishedAt() monad.Maybe[time.Time] { return s.finishedAt }
+
+// Type needed to marshal a DeploymentCreated event, its source data being discriminated.
+type marshalledDeploymentCreated struct {
+	ID          DeploymentID           `json:"id"`
+	Config      ConfigSnapshot         `json:"config"`
+	State       DeploymentState        `json:"state"`
+	SourceKind  string                 `json:"source_kind"`
+	Source      string                 `json:"source"`
+	RequestedAt time.Time              `json:"requested_at"`
+	RequestedBy domain.UserID          `json:"requested_by"`
+	NotBefore   monad.Maybe[time.Time] `json:"not_before"`
+}
+
+func (e DeploymentCreated) MarshalJSON() ([]byte, error) {
+	// Source data are marshalled the same way they are persisted so they could be rehydrated
+	// with the SourceDataTypes mapper.
+	source, err := driver.DefaultParameterConverter.ConvertValue(e.Source)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var data string
+
+	switch v := source.(type) {
+	case string:
+		data = v
+	case []byte:
+		data = string(v)
+	default:
+		return nil, ErrInvalidSourcePayload
+	}
+
+	return json.Marshal(marshalledDeploymentCreated{
+		ID:          e.ID,
+		Config:      e.Config,
+		State:       e.State,
+		SourceKind:  e.Source.Kind(),
+		Source:      data,
+		RequestedAt: e.Requested.At(),
+		RequestedBy: e.Requested.By(),
+		NotBefore:   e.NotBefore,
+	})
+}
+
+func (e *DeploymentCreated) UnmarshalJSON(b []byte) error {
+	var m marshalledDeploymentCreated
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	source, err := SourceDataTypes.From(m.SourceKind, m.Source)
+
+	if err != nil {
+		return err
+	}
+
+	e.ID = m.ID
+	e.Config = m.Config
+	e.State = m.State
+	e.Source = source
+	e.Requested = shared.ActionFrom(m.RequestedBy, m.RequestedAt)
+	e.NotBefore = m.NotBefore
+
+	return nil
+}
+
+// Type needed to marshal an unexposed DeploymentState data.
+type marshalledDeploymentState struct {
+	Status     DeploymentStatus       `json:"status"`
+	ErrCode    monad.Maybe[string]    `json:"error_code"`
+	Services   monad.Maybe[Services]  `json:"services"`
+	StartedAt  monad.Maybe[time.Time] `json:"started_at"`
+	FinishedAt monad.Maybe[time.Time] `json:"finished_at"`
+}
+
+func (s DeploymentState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalledDeploymentState{
+		Status:     s.status,
+		ErrCode:    s.errcode,
+		Services:   s.services,
+		StartedAt:  s.startedAt,
+		FinishedAt: s.finishedAt,
+	})
+}
+
+func (s *DeploymentState) UnmarshalJSON(b []byte) error {
+	var m marshalledDeploymentState
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	s.status = m.Status
+	s.errcode = m.ErrCode
+	s.services = m.Services
+	s.startedAt = m.StartedAt
+	s.finishedAt = m.FinishedAt
+
+	return nil
+}
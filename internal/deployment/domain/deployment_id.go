@@ -1,5 +1,7 @@
 package domain
 
+import "encoding/json"
+
 type (
 	// The deployment unique identifier is a composite key
 	// based on the app id and the deployment number.
@@ -18,3 +20,26 @@ func DeploymentIDFrom(app AppID, number DeploymentNumber) DeploymentID {
 
 func (i DeploymentID) AppID() AppID                       { return i.appID }
 func (i DeploymentID) DeploymentNumber() DeploymentNumber { return i.deploymentNumber }
+
+// Type needed to marshal an unexposed DeploymentID data.
+type marshalledDeploymentID struct {
+	AppID            AppID            `json:"app_id"`
+	DeploymentNumber DeploymentNumber `json:"deployment_number"`
+}
+
+func (i DeploymentID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalledDeploymentID{i.appID, i.deploymentNumber})
+}
+
+func (i *DeploymentID) UnmarshalJSON(b []byte) error {
+	var m marshalledDeploymentID
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	i.appID = m.AppID
+	i.deploymentNumber = m.DeploymentNumber
+
+	return nil
+}
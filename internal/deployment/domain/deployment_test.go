@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 
@@ -167,6 +168,192 @@ func Test_Deployment(t *testing.T) {
 		})
 	})
 
+	t.Run("should raise created events which could be (un)marshalled to JSON", func(t *testing.T) {
+		deployment := fixture.Deployment(fixture.WithSourceData(fixture.SourceData(fixture.WithCommit("abcdef"))))
+
+		evt := assert.EventIs[domain.DeploymentCreated](t, &deployment, 0)
+
+		data, err := json.Marshal(evt)
+		assert.Nil(t, err)
+
+		var unmarshalled domain.DeploymentCreated
+		assert.Nil(t, json.Unmarshal(data, &unmarshalled))
+
+		assert.DeepEqual(t, evt, unmarshalled)
+	})
+
+	t.Run("should raise state changed events which could be (un)marshalled to JSON", func(t *testing.T) {
+		deployment := fixture.Deployment()
+		builder := deployment.Config().ServicesBuilder()
+		builder.AddService("service", "an/image").AddHttpEntrypoint(80, true)
+		assert.Nil(t, deployment.HasStarted())
+		assert.Nil(t, deployment.HasEnded(builder.Services(), nil))
+
+		evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 2)
+
+		data, err := json.Marshal(evt)
+		assert.Nil(t, err)
+
+		var unmarshalled domain.DeploymentStateChanged
+		assert.Nil(t, json.Unmarshal(data, &unmarshalled))
+
+		assert.DeepEqual(t, evt, unmarshalled)
+	})
+
+	t.Run("could be superseded", func(t *testing.T) {
+		t.Run("should fail if the deployment has already started", func(t *testing.T) {
+			deployment := fixture.Deployment()
+			assert.Nil(t, deployment.HasStarted())
+
+			err := deployment.Supersede()
+
+			assert.ErrorIs(t, domain.ErrNotInPendingState, err)
+		})
+
+		t.Run("should succeed if the deployment is pending", func(t *testing.T) {
+			deployment := fixture.Deployment()
+
+			err := deployment.Supersede()
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 2, &deployment)
+
+			evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 1)
+
+			assert.Equal(t, domain.DeploymentStatusFailed, evt.State.Status())
+			assert.Equal(t, domain.ErrDeploymentSuperseded.Error(), evt.State.ErrCode().MustGet())
+			assert.NotZero(t, evt.State.StartedAt())
+			assert.NotZero(t, evt.State.FinishedAt())
+		})
+	})
+
+	t.Run("could be cancelled", func(t *testing.T) {
+		t.Run("should fail if the deployment has already ended", func(t *testing.T) {
+			deployment := fixture.Deployment()
+			assert.Nil(t, deployment.HasStarted())
+			assert.Nil(t, deployment.HasEnded(nil, nil))
+
+			err := deployment.Cancel()
+
+			assert.ErrorIs(t, domain.ErrDeploymentAlreadyEnded, err)
+		})
+
+		t.Run("should succeed if the deployment is pending", func(t *testing.T) {
+			deployment := fixture.Deployment()
+
+			err := deployment.Cancel()
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 2, &deployment)
+
+			evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 1)
+
+			assert.Equal(t, domain.DeploymentStatusFailed, evt.State.Status())
+			assert.Equal(t, domain.ErrDeploymentCancelled.Error(), evt.State.ErrCode().MustGet())
+			assert.NotZero(t, evt.State.StartedAt())
+			assert.NotZero(t, evt.State.FinishedAt())
+			assert.ErrorIs(t, domain.ErrNotInPendingState, deployment.HasStarted())
+		})
+
+		t.Run("should succeed if the deployment is running", func(t *testing.T) {
+			deployment := fixture.Deployment()
+			assert.Nil(t, deployment.HasStarted())
+
+			err := deployment.Cancel()
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 3, &deployment)
+
+			evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 2)
+
+			assert.Equal(t, domain.DeploymentStatusFailed, evt.State.Status())
+			assert.Equal(t, domain.ErrDeploymentCancelled.Error(), evt.State.ErrCode().MustGet())
+			assert.NotZero(t, evt.State.FinishedAt())
+			assert.ErrorIs(t, domain.ErrNotInRunningState, deployment.HasEnded(nil, nil))
+		})
+	})
+
+	t.Run("could require an approval", func(t *testing.T) {
+		gatedApp := func() domain.App {
+			config := domain.NewEnvironmentConfig("production-target")
+			config.RequireApproval()
+			return fixture.App(fixture.WithProductionConfig(config))
+		}
+
+		t.Run("should be created awaiting approval if the environment requires it", func(t *testing.T) {
+			deployment := fixture.Deployment(fixture.FromApp(gatedApp()))
+
+			evt := assert.EventIs[domain.DeploymentCreated](t, &deployment, 0)
+
+			assert.Equal(t, domain.DeploymentStatusAwaitingApproval, evt.State.Status())
+			assert.True(t, evt.IsAwaitingApproval())
+			assert.ErrorIs(t, domain.ErrNotInPendingState, deployment.HasStarted())
+		})
+
+		t.Run("should fail to approve a deployment not awaiting approval", func(t *testing.T) {
+			deployment := fixture.Deployment()
+
+			assert.ErrorIs(t, domain.ErrNotAwaitingApproval, deployment.Approve("another-user"))
+			assert.ErrorIs(t, domain.ErrNotAwaitingApproval, deployment.Reject())
+			assert.ErrorIs(t, domain.ErrNotAwaitingApproval, deployment.ApprovalExpired())
+		})
+
+		t.Run("should not be approved by the user who requested it", func(t *testing.T) {
+			deployment := fixture.Deployment(fixture.FromApp(gatedApp()), fixture.WithDeploymentRequestedBy("uid"))
+
+			assert.ErrorIs(t, domain.ErrSelfApprovalNotAllowed, deployment.Approve("uid"))
+		})
+
+		t.Run("should be approved by another user", func(t *testing.T) {
+			deployment := fixture.Deployment(fixture.FromApp(gatedApp()), fixture.WithDeploymentRequestedBy("uid"))
+
+			err := deployment.Approve("another-user")
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 3, &deployment)
+
+			changed := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 1)
+			assert.Equal(t, domain.DeploymentStatusPending, changed.State.Status())
+
+			approved := assert.EventIs[domain.DeploymentApproved](t, &deployment, 2)
+			assert.DeepEqual(t, domain.DeploymentApproved{
+				ID:       deployment.ID(),
+				Config:   deployment.Config(),
+				Approved: shared.ActionFrom[auth.UserID]("another-user", assert.NotZero(t, approved.Approved.At())),
+			}, approved)
+			assert.Equal(t, approved.Approved, deployment.Approved().MustGet())
+			assert.Nil(t, deployment.HasStarted())
+		})
+
+		t.Run("should be rejected", func(t *testing.T) {
+			deployment := fixture.Deployment(fixture.FromApp(gatedApp()))
+
+			err := deployment.Reject()
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 2, &deployment)
+
+			evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 1)
+			assert.Equal(t, domain.DeploymentStatusFailed, evt.State.Status())
+			assert.Equal(t, domain.ErrDeploymentRejected.Error(), evt.State.ErrCode().MustGet())
+			assert.NotZero(t, evt.State.StartedAt())
+			assert.NotZero(t, evt.State.FinishedAt())
+		})
+
+		t.Run("should expire", func(t *testing.T) {
+			deployment := fixture.Deployment(fixture.FromApp(gatedApp()))
+
+			err := deployment.ApprovalExpired()
+
+			assert.Nil(t, err)
+			assert.HasNEvents(t, 2, &deployment)
+
+			evt := assert.EventIs[domain.DeploymentStateChanged](t, &deployment, 1)
+			assert.Equal(t, domain.DeploymentStatusFailed, evt.State.Status())
+			assert.Equal(t, domain.ErrDeploymentApprovalExpired.Error(), evt.State.ErrCode().MustGet())
+		})
+	})
+
 	t.Run("could be redeployed", func(t *testing.T) {
 		app := fixture.App()
 		sourceDeployment := fixture.Deployment(fixture.FromApp(app))
@@ -268,3 +455,12 @@ func Test_DeploymentEvents(t *testing.T) {
 		assert.False(t, evt.HasSucceeded())
 	})
 }
+
+func Test_DeploymentStatus(t *testing.T) {
+	t.Run("should expose a method to check if the status is a final one", func(t *testing.T) {
+		assert.False(t, domain.DeploymentStatusPending.IsFinal())
+		assert.False(t, domain.DeploymentStatusRunning.IsFinal())
+		assert.True(t, domain.DeploymentStatusFailed.IsFinal())
+		assert.True(t, domain.DeploymentStatusSucceeded.IsFinal())
+	})
+}
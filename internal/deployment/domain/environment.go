@@ -11,7 +11,8 @@ import (
 )
 
 var (
-	ErrInvalidEnvironmentName = apperr.New("invalid_environment_name")
+	ErrInvalidEnvironmentName   = apperr.New("invalid_environment_name")
+	ErrInvalidDeploymentTimeout = apperr.New("invalid_deployment_timeout")
 )
 
 const (
@@ -30,9 +31,11 @@ type (
 	// The version field is used during the cleanup process to check for successfull deployments
 	// during a specific interval (the last target change).
 	EnvironmentConfig struct {
-		target  TargetID
-		version time.Time
-		vars    monad.Maybe[ServicesEnv]
+		target   TargetID
+		version  time.Time
+		vars     monad.Maybe[ServicesEnv]
+		timeout  monad.Maybe[time.Duration]
+		approval bool // Deployments on this environment must be approved by another user
 	}
 )
 
@@ -55,7 +58,7 @@ func (e Environment) IsProduction() bool { return e == Production }
 func NewEnvironmentConfig(target TargetID) EnvironmentConfig {
 	return EnvironmentConfig{
 		target:  target,
-		version: time.Now().UTC(),
+		version: time.Now().UTC().Truncate(time.Microsecond), // Same as the target version, must survive a database round trip
 	}
 }
 
@@ -64,14 +67,29 @@ func (e *EnvironmentConfig) HasEnvironmentVariables(vars ServicesEnv) {
 	e.vars.Set(vars)
 }
 
+// Limit the time a deployment on this environment can take, overriding the global timeout.
+func (e *EnvironmentConfig) HasTimeout(timeout time.Duration) {
+	e.timeout.Set(timeout)
+}
+
+// Require deployments on this environment to be approved by another user before being run.
+func (e *EnvironmentConfig) RequireApproval() {
+	e.approval = true
+}
+
 // Check if two environment config are equals, does not compare version.
 func (e EnvironmentConfig) Equals(other EnvironmentConfig) bool {
-	return e.target == other.target && reflect.DeepEqual(e.vars, other.vars)
+	return e.target == other.target &&
+		e.timeout == other.timeout &&
+		e.approval == other.approval &&
+		reflect.DeepEqual(e.vars, other.vars)
 }
 
-func (e EnvironmentConfig) Target() TargetID               { return e.target }
-func (e EnvironmentConfig) Version() time.Time             { return e.version }
-func (e EnvironmentConfig) Vars() monad.Maybe[ServicesEnv] { return e.vars }
+func (e EnvironmentConfig) Target() TargetID                    { return e.target }
+func (e EnvironmentConfig) Version() time.Time                  { return e.version }
+func (e EnvironmentConfig) Vars() monad.Maybe[ServicesEnv]      { return e.vars }
+func (e EnvironmentConfig) Timeout() monad.Maybe[time.Duration] { return e.timeout }
+func (e EnvironmentConfig) RequiresApproval() bool              { return e.approval }
 
 func (e *EnvironmentConfig) consolidate(other EnvironmentConfig) {
 	if e.target != other.target {
@@ -81,6 +99,15 @@ func (e *EnvironmentConfig) consolidate(other EnvironmentConfig) {
 	e.version = other.version
 }
 
+// Builds a deployment timeout from a number of seconds.
+func DeploymentTimeoutFrom(seconds int) (time.Duration, error) {
+	if seconds <= 0 {
+		return 0, ErrInvalidDeploymentTimeout
+	}
+
+	return time.Duration(seconds) * time.Second, nil
+}
+
 // Builds the map of services variables from a raw value.
 func ServicesEnvFrom(raw map[string]map[string]string) ServicesEnv {
 	result := make(ServicesEnv, len(raw))
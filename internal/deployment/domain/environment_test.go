@@ -3,6 +3,7 @@ package domain_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/assert"
@@ -110,6 +111,23 @@ func Test_ServicesEnv(t *testing.T) {
 	})
 }
 
+func Test_DeploymentTimeout(t *testing.T) {
+	t.Run("should reject non positive values", func(t *testing.T) {
+		_, err := domain.DeploymentTimeoutFrom(0)
+		assert.ErrorIs(t, domain.ErrInvalidDeploymentTimeout, err)
+
+		_, err = domain.DeploymentTimeoutFrom(-10)
+		assert.ErrorIs(t, domain.ErrInvalidDeploymentTimeout, err)
+	})
+
+	t.Run("should convert seconds to a duration", func(t *testing.T) {
+		d, err := domain.DeploymentTimeoutFrom(90)
+
+		assert.Nil(t, err)
+		assert.Equal(t, 90*time.Second, d)
+	})
+}
+
 func Test_EnvironmentConfig(t *testing.T) {
 	t.Run("should be able to build a new environment config", func(t *testing.T) {
 		target := domain.TargetID("target")
@@ -135,6 +153,13 @@ func Test_EnvironmentConfig(t *testing.T) {
 		assert.DeepEqual(t, vars, r.Vars().MustGet())
 	})
 
+	t.Run("should be able to configure a deployment timeout", func(t *testing.T) {
+		r := domain.NewEnvironmentConfig("target")
+		r.HasTimeout(10 * time.Minute)
+
+		assert.Equal(t, 10*time.Minute, r.Timeout().MustGet())
+	})
+
 	t.Run("should be able to compare itself with another config", func(t *testing.T) {
 		tests := []struct {
 			a        func() domain.EnvironmentConfig
@@ -186,6 +211,15 @@ func Test_EnvironmentConfig(t *testing.T) {
 				},
 				expected: false,
 			},
+			{
+				a: func() domain.EnvironmentConfig {
+					conf := domain.NewEnvironmentConfig("1")
+					conf.HasTimeout(time.Minute)
+					return conf
+				},
+				b:        func() domain.EnvironmentConfig { return domain.NewEnvironmentConfig("1") },
+				expected: false,
+			},
 		}
 
 		for _, test := range tests {
@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/cron"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrEnvironmentFrozen        = apperr.New("environment_frozen")
+	ErrFreezeOverrideNotAllowed = apperr.New("freeze_override_not_allowed")
+	ErrInvalidFreezePeriod      = apperr.New("invalid_freeze_period") // Either recurring or fixed
+	ErrInvalidFreezeDuration    = apperr.New("invalid_freeze_duration")
+	ErrInvalidFreezeRange       = apperr.New("invalid_freeze_range")
+)
+
+type (
+	FreezeWindowID        string
+	FreezeOverrideAllowed bool // Only administrators could deploy during a freeze
+
+	// Period during which deployments are blocked. Either recurring, starting at each
+	// occurrence of a cron expression (evaluated in UTC) and lasting for a duration, or
+	// a fixed date range.
+	FreezePeriod struct {
+		expression monad.Maybe[cron.Expression]
+		duration   time.Duration
+		from       time.Time
+		to         time.Time
+	}
+
+	// Blocks deployments during a period, on every applications or only on a specific
+	// application and/or environment.
+	FreezeWindow struct {
+		event.Emitter
+
+		id          FreezeWindowID
+		name        string
+		app         monad.Maybe[AppID]
+		environment monad.Maybe[Environment]
+		period      FreezePeriod
+		created     shared.Action[auth.UserID]
+	}
+
+	FreezeWindowsReader interface {
+		GetByID(context.Context, FreezeWindowID) (FreezeWindow, error)
+		// Retrieve freeze windows which may apply to the given app environment, global ones included.
+		GetForAppEnv(context.Context, AppID, Environment) ([]FreezeWindow, error)
+		IsFreezeOverrideAllowed(context.Context, auth.UserID) (FreezeOverrideAllowed, error)
+	}
+
+	FreezeWindowsWriter interface {
+		Write(context.Context, ...*FreezeWindow) error
+	}
+
+	FreezeWindowCreated struct {
+		bus.Notification
+
+		ID          FreezeWindowID
+		Name        string
+		App         monad.Maybe[AppID]
+		Environment monad.Maybe[Environment]
+		Period      FreezePeriod
+		Created     shared.Action[auth.UserID]
+	}
+
+	FreezeWindowDeleted struct {
+		bus.Notification
+
+		ID FreezeWindowID
+	}
+)
+
+func (FreezeWindowCreated) Name_() string { return "deployment.event.freeze_window_created" }
+func (FreezeWindowDeleted) Name_() string { return "deployment.event.freeze_window_deleted" }
+
+// Builds a recurring freeze period starting at each occurrence of the given expression.
+func RecurringFreezePeriod(expr cron.Expression, duration time.Duration) (FreezePeriod, error) {
+	if duration <= 0 {
+		return FreezePeriod{}, ErrInvalidFreezeDuration
+	}
+
+	return FreezePeriod{
+		expression: monad.Value(expr),
+		duration:   duration,
+	}, nil
+}
+
+// Builds a freeze period covering the given date range.
+func FixedFreezePeriod(from, to time.Time) (FreezePeriod, error) {
+	if !to.After(from) {
+		return FreezePeriod{}, ErrInvalidFreezeRange
+	}
+
+	return FreezePeriod{
+		from: from.UTC(),
+		to:   to.UTC(),
+	}, nil
+}
+
+// Check if the given time is inside this period.
+func (p FreezePeriod) Contains(t time.Time) bool {
+	t = t.UTC()
+
+	expr, isRecurring := p.expression.TryGet()
+
+	if !isRecurring {
+		return !t.Before(p.from) && t.Before(p.to)
+	}
+
+	// An occurrence started in ]t - duration, t] means the period is still running
+	start := expr.Next(t.Add(-p.duration))
+
+	return !start.IsZero() && !start.After(t)
+}
+
+func (p FreezePeriod) Expression() monad.Maybe[cron.Expression] { return p.expression }
+func (p FreezePeriod) Duration() time.Duration                  { return p.duration }
+func (p FreezePeriod) From() time.Time                          { return p.from }
+func (p FreezePeriod) To() time.Time                            { return p.to }
+
+// Creates a new freeze window. Without an app and an environment, it applies everywhere.
+func NewFreezeWindow(
+	name string,
+	app monad.Maybe[AppID],
+	env monad.Maybe[Environment],
+	period FreezePeriod,
+	createdBy auth.UserID,
+) (w FreezeWindow) {
+	w.apply(FreezeWindowCreated{
+		ID:          id.New[FreezeWindowID](),
+		Name:        name,
+		App:         app,
+		Environment: env,
+		Period:      period,
+		Created:     shared.NewAction(createdBy),
+	})
+
+	return w
+}
+
+// Recreates a freeze window from the persistent storage.
+func FreezeWindowFrom(scanner storage.Scanner) (w FreezeWindow, err error) {
+	var (
+		app        monad.Maybe[string]
+		env        monad.Maybe[string]
+		expression monad.Maybe[string]
+		duration   monad.Maybe[int64]
+		startsAt   monad.Maybe[time.Time]
+		endsAt     monad.Maybe[time.Time]
+		createdAt  time.Time
+		createdBy  auth.UserID
+	)
+
+	err = scanner.Scan(
+		&w.id,
+		&w.name,
+		&app,
+		&env,
+		&expression,
+		&duration,
+		&startsAt,
+		&endsAt,
+		&createdAt,
+		&createdBy,
+	)
+
+	if err != nil {
+		return w, err
+	}
+
+	w.created = shared.ActionFrom(createdBy, createdAt)
+
+	if a, isSet := app.TryGet(); isSet {
+		w.app.Set(AppID(a))
+	}
+
+	if e, isSet := env.TryGet(); isSet {
+		w.environment.Set(Environment(e))
+	}
+
+	if raw, isSet := expression.TryGet(); isSet {
+		expr, err := cron.Parse(raw)
+
+		if err != nil {
+			return w, err
+		}
+
+		w.period.expression.Set(expr)
+		w.period.duration = time.Duration(duration.Get(0)) * time.Second
+
+		return w, nil
+	}
+
+	w.period.from = startsAt.Get(time.Time{})
+	w.period.to = endsAt.Get(time.Time{})
+
+	return w, nil
+}
+
+// Check if this freeze window blocks deployments on the given app environment at the given time.
+func (w *FreezeWindow) IsFrozen(app AppID, env Environment, at time.Time) bool {
+	if a, isSet := w.app.TryGet(); isSet && a != app {
+		return false
+	}
+
+	if e, isSet := w.environment.TryGet(); isSet && e != env {
+		return false
+	}
+
+	return w.period.Contains(at)
+}
+
+func (w *FreezeWindow) Delete() {
+	w.apply(FreezeWindowDeleted{
+		ID: w.id,
+	})
+}
+
+func (w *FreezeWindow) ID() FreezeWindowID                    { return w.id }
+func (w *FreezeWindow) Name() string                          { return w.name }
+func (w *FreezeWindow) App() monad.Maybe[AppID]               { return w.app }
+func (w *FreezeWindow) Environment() monad.Maybe[Environment] { return w.environment }
+func (w *FreezeWindow) Period() FreezePeriod                  { return w.period }
+func (w *FreezeWindow) Created() shared.Action[auth.UserID]   { return w.created }
+
+func (w *FreezeWindow) apply(e event.Event) {
+	switch evt := e.(type) {
+	case FreezeWindowCreated:
+		w.id = evt.ID
+		w.name = evt.Name
+		w.app = evt.App
+		w.environment = evt.Environment
+		w.period = evt.Period
+		w.created = evt.Created
+	}
+
+	event.Store(w, e)
+}
@@ -0,0 +1,128 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+func Test_FreezeWindow(t *testing.T) {
+	friday := time.Date(2024, time.March, 15, 18, 0, 0, 0, time.UTC)
+	weekends := must.Panic(domain.RecurringFreezePeriod(
+		must.Panic(domain.ScheduleExpressionFrom("0 18 * * 5")),
+		62*time.Hour, // Till monday 08:00
+	))
+
+	t.Run("should validate the period", func(t *testing.T) {
+		_, err := domain.RecurringFreezePeriod(must.Panic(domain.ScheduleExpressionFrom("@daily")), 0)
+		assert.ErrorIs(t, domain.ErrInvalidFreezeDuration, err)
+
+		_, err = domain.FixedFreezePeriod(friday, friday.Add(-time.Hour))
+		assert.ErrorIs(t, domain.ErrInvalidFreezeRange, err)
+	})
+
+	t.Run("should check if a recurring period contains a date", func(t *testing.T) {
+		assert.False(t, weekends.Contains(friday.Add(-time.Minute)))
+		assert.True(t, weekends.Contains(friday))
+		assert.True(t, weekends.Contains(friday.Add(24*time.Hour)))
+		assert.True(t, weekends.Contains(friday.Add(62*time.Hour-time.Minute)))
+		assert.False(t, weekends.Contains(friday.Add(62*time.Hour)))
+		assert.True(t, weekends.Contains(friday.AddDate(0, 0, 7).Add(time.Hour)))
+	})
+
+	t.Run("should check if a fixed period contains a date", func(t *testing.T) {
+		period := must.Panic(domain.FixedFreezePeriod(friday, friday.Add(time.Hour)))
+
+		assert.False(t, period.Contains(friday.Add(-time.Second)))
+		assert.True(t, period.Contains(friday))
+		assert.True(t, period.Contains(friday.In(time.FixedZone("UTC+1", 3600))))
+		assert.False(t, period.Contains(friday.Add(time.Hour)))
+	})
+
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			uid auth.UserID = "uid"
+			app             = monad.Value[domain.AppID]("my-app")
+			env             = monad.Value(domain.Production)
+		)
+
+		w := domain.NewFreezeWindow("weekends", app, env, weekends, uid)
+
+		assert.NotZero(t, w.ID())
+		assert.Equal(t, "weekends", w.Name())
+		assert.Equal(t, app, w.App())
+		assert.Equal(t, env, w.Environment())
+
+		created := assert.EventIs[domain.FreezeWindowCreated](t, &w, 0)
+
+		assert.Equal(t, w.ID(), created.ID)
+		assert.Equal(t, uid, created.Created.By())
+	})
+
+	t.Run("should only block the targeted app environment", func(t *testing.T) {
+		w := domain.NewFreezeWindow("weekends", monad.Value[domain.AppID]("my-app"), monad.Value(domain.Production), weekends, "uid")
+
+		assert.True(t, w.IsFrozen("my-app", domain.Production, friday))
+		assert.False(t, w.IsFrozen("my-app", domain.Staging, friday))
+		assert.False(t, w.IsFrozen("another-app", domain.Production, friday))
+		assert.False(t, w.IsFrozen("my-app", domain.Production, friday.Add(-time.Hour)))
+
+		global := domain.NewFreezeWindow("weekends", monad.None[domain.AppID](), monad.None[domain.Environment](), weekends, "uid")
+
+		assert.True(t, global.IsFrozen("another-app", domain.Staging, friday))
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		w := domain.NewFreezeWindow("weekends", monad.None[domain.AppID](), monad.None[domain.Environment](), weekends, "uid")
+
+		w.Delete()
+
+		deleted := assert.EventIs[domain.FreezeWindowDeleted](t, &w, 1)
+		assert.Equal(t, domain.FreezeWindowDeleted{ID: w.ID()}, deleted)
+	})
+
+	t.Run("should reject deployments during a freeze", func(t *testing.T) {
+		deployment := fixture.Deployment()
+		now := must.Panic(domain.FixedFreezePeriod(time.Now().Add(-time.Hour), time.Now().Add(time.Hour)))
+		windows := []domain.FreezeWindow{
+			domain.NewFreezeWindow("now", monad.None[domain.AppID](), monad.None[domain.Environment](), now, "uid"),
+		}
+
+		assert.ErrorIs(t, domain.ErrEnvironmentFrozen, deployment.CheckFreezeWindows(windows, false, true))
+		assert.ErrorIs(t, domain.ErrFreezeOverrideNotAllowed, deployment.CheckFreezeWindows(windows, true, false))
+		assert.False(t, deployment.FreezeOverridden())
+		assert.HasNEvents(t, 1, &deployment)
+	})
+
+	t.Run("should record the freeze override on the deployment", func(t *testing.T) {
+		deployment := fixture.Deployment()
+		now := must.Panic(domain.FixedFreezePeriod(time.Now().Add(-time.Hour), time.Now().Add(time.Hour)))
+		windows := []domain.FreezeWindow{
+			domain.NewFreezeWindow("now", monad.None[domain.AppID](), monad.None[domain.Environment](), now, "uid"),
+		}
+
+		assert.Nil(t, deployment.CheckFreezeWindows(windows, true, true))
+		assert.True(t, deployment.FreezeOverridden())
+
+		evt := assert.EventIs[domain.DeploymentFreezeOverridden](t, &deployment, 1)
+		assert.Equal(t, deployment.ID(), evt.ID)
+	})
+
+	t.Run("should allow deployments outside of a freeze", func(t *testing.T) {
+		deployment := fixture.Deployment()
+		past := must.Panic(domain.FixedFreezePeriod(time.Now().Add(-2*time.Hour), time.Now().Add(-time.Hour)))
+		windows := []domain.FreezeWindow{
+			domain.NewFreezeWindow("past", monad.None[domain.AppID](), monad.None[domain.Environment](), past, "uid"),
+		}
+
+		assert.Nil(t, deployment.CheckFreezeWindows(windows, true, true))
+		assert.False(t, deployment.FreezeOverridden())
+		assert.HasNEvents(t, 1, &deployment)
+	})
+}
@@ -1,13 +1,41 @@
 package domain
 
-import "io"
+import (
+	"io"
+	"time"
 
-// Specific logger interface use by deployment jobs to document the deployment process.
-type DeploymentLogger interface {
-	io.WriteCloser
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
 
-	Stepf(string, ...any)
-	Warnf(string, ...any)
-	Infof(string, ...any)
-	Error(error)
-}
+const (
+	DeploymentPhaseFetch     DeploymentPhase = "fetch"     // Retrieving deployment sources
+	DeploymentPhaseConfigure DeploymentPhase = "configure" // Connecting to the target and preparing the project
+	DeploymentPhasePull      DeploymentPhase = "pull"      // Pulling missing images
+	DeploymentPhaseBuild     DeploymentPhase = "build"     // Building services images
+	DeploymentPhaseUp        DeploymentPhase = "up"        // Running services
+	DeploymentPhasePrune     DeploymentPhase = "prune"     // Removing unused resources
+)
+
+type (
+	// Specific logger interface use by deployment jobs to document the deployment process.
+	DeploymentLogger interface {
+		io.WriteCloser
+
+		Phase(DeploymentPhase)         // Mark the beginning of a new phase, used to build the deployment timeline
+		CurrentPhase() DeploymentPhase // Returns the phase currently in progress, empty if none has started yet
+		Stepf(string, ...any)
+		Warnf(string, ...any)
+		Infof(string, ...any)
+		Error(error)
+	}
+
+	// Phase of a deployment process.
+	DeploymentPhase string
+
+	// Time spent in a deployment phase as recorded in the deployment logs.
+	DeploymentPhaseTiming struct {
+		Phase      DeploymentPhase
+		StartedAt  time.Time
+		FinishedAt monad.Maybe[time.Time] // Not set if the phase is still in progress
+	}
+)
@@ -0,0 +1,377 @@
+package domain
+
+import (
+	"context"
+	"database/sql/driver"
+	"net/mail"
+	"slices"
+	"text/template"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidNotificationChannelKind = apperr.New("invalid_notification_channel_kind")
+	ErrInvalidNotificationEvent       = apperr.New("invalid_notification_event")
+	ErrInvalidNotificationDestination = apperr.New("invalid_notification_destination")
+	ErrInvalidNotificationTemplate    = apperr.New("invalid_notification_template")
+)
+
+const (
+	NotificationChannelKindSlack   NotificationChannelKind = "slack"
+	NotificationChannelKindDiscord NotificationChannelKind = "discord"
+	NotificationChannelKindTeams   NotificationChannelKind = "teams"
+	NotificationChannelKindWebhook NotificationChannelKind = "webhook" // Generic webhook receiving a JSON payload
+	NotificationChannelKindEmail   NotificationChannelKind = "email"   // Email sent using the configured SMTP server
+)
+
+const (
+	NotificationEventDeploymentSucceeded NotificationEvent = "deployment_succeeded"
+	NotificationEventDeploymentFailed    NotificationEvent = "deployment_failed"
+	NotificationEventTargetFailed        NotificationEvent = "target_failed"
+)
+
+type (
+	NotificationChannelID   string
+	NotificationChannelKind string
+	NotificationEvent       string
+
+	// Subscription rule of a notification channel. Empty app and environment means
+	// every apps and environments.
+	NotificationRule struct {
+		App         monad.Maybe[AppID]       `json:"app_id"`
+		Environment monad.Maybe[Environment] `json:"environment"`
+		Events      []NotificationEvent      `json:"events"`
+	}
+
+	NotificationRules []NotificationRule
+
+	// Represents a destination where notifications will be sent when specific events
+	// occurs. The destination is an URL for webhook based channels and a comma separated
+	// list of addresses for the email one.
+	NotificationChannel struct {
+		event.Emitter
+
+		id          NotificationChannelID
+		name        string
+		kind        NotificationChannelKind
+		destination string
+		template    monad.Maybe[string]
+		rules       NotificationRules
+		created     shared.Action[auth.UserID]
+	}
+
+	NotificationChannelsReader interface {
+		GetByID(context.Context, NotificationChannelID) (NotificationChannel, error)
+		GetAll(context.Context) ([]NotificationChannel, error)
+	}
+
+	NotificationChannelsWriter interface {
+		Write(context.Context, ...*NotificationChannel) error
+	}
+
+	// Data available when rendering a notification.
+	NotificationMessage struct {
+		Event            NotificationEvent
+		OccurredAt       time.Time
+		AppID            string
+		AppName          string
+		Environment      string
+		DeploymentNumber int
+		TargetID         string
+		TargetName       string
+		ErrCode          string
+		Urls             []string // Urls of exposed services if any
+		LogsUrl          string   // Link to the deployment details in the seelf dashboard if known
+	}
+
+	// Send notifications to a channel.
+	Notifier interface {
+		Notify(context.Context, NotificationChannel, NotificationMessage) error
+	}
+
+	NotificationChannelCreated struct {
+		bus.Notification
+
+		ID          NotificationChannelID
+		Name        string
+		Kind        NotificationChannelKind
+		Destination string
+		Created     shared.Action[auth.UserID]
+	}
+
+	NotificationChannelRenamed struct {
+		bus.Notification
+
+		ID   NotificationChannelID
+		Name string
+	}
+
+	NotificationChannelDestinationChanged struct {
+		bus.Notification
+
+		ID          NotificationChannelID
+		Destination string
+	}
+
+	NotificationChannelTemplateChanged struct {
+		bus.Notification
+
+		ID       NotificationChannelID
+		Template monad.Maybe[string]
+	}
+
+	NotificationChannelRulesChanged struct {
+		bus.Notification
+
+		ID    NotificationChannelID
+		Rules NotificationRules
+	}
+
+	NotificationChannelDeleted struct {
+		bus.Notification
+
+		ID NotificationChannelID
+	}
+)
+
+func (NotificationChannelCreated) Name_() string {
+	return "deployment.event.notification_channel_created"
+}
+func (NotificationChannelRenamed) Name_() string {
+	return "deployment.event.notification_channel_renamed"
+}
+func (NotificationChannelDeleted) Name_() string {
+	return "deployment.event.notification_channel_deleted"
+}
+
+func (NotificationChannelDestinationChanged) Name_() string {
+	return "deployment.event.notification_channel_destination_changed"
+}
+func (NotificationChannelTemplateChanged) Name_() string {
+	return "deployment.event.notification_channel_template_changed"
+}
+func (NotificationChannelRulesChanged) Name_() string {
+	return "deployment.event.notification_channel_rules_changed"
+}
+
+// Parses a notification channel kind from a raw value.
+func NotificationChannelKindFrom(value string) (NotificationChannelKind, error) {
+	switch kind := NotificationChannelKind(value); kind {
+	case NotificationChannelKindSlack,
+		NotificationChannelKindDiscord,
+		NotificationChannelKindTeams,
+		NotificationChannelKindWebhook,
+		NotificationChannelKindEmail:
+		return kind, nil
+	default:
+		return "", ErrInvalidNotificationChannelKind
+	}
+}
+
+// Parses a notification event from a raw value.
+func NotificationEventFrom(value string) (NotificationEvent, error) {
+	switch evt := NotificationEvent(value); evt {
+	case NotificationEventDeploymentSucceeded,
+		NotificationEventDeploymentFailed,
+		NotificationEventTargetFailed:
+		return evt, nil
+	default:
+		return "", ErrInvalidNotificationEvent
+	}
+}
+
+// Validates the given notification template.
+func NotificationTemplateFrom(value string) (string, error) {
+	if _, err := template.New("").Parse(value); err != nil {
+		return "", ErrInvalidNotificationTemplate
+	}
+
+	return value, nil
+}
+
+// Validates the given destination for this kind of channel: an absolute URL for webhook
+// based channels and a list of email addresses for the email one.
+func (k NotificationChannelKind) Destination(value string) (string, error) {
+	if k == NotificationChannelKindEmail {
+		if _, err := mail.ParseAddressList(value); err != nil {
+			return "", ErrInvalidNotificationDestination
+		}
+
+		return value, nil
+	}
+
+	if _, err := UrlFrom(value); err != nil {
+		return "", ErrInvalidNotificationDestination
+	}
+
+	return value, nil
+}
+
+// Creates a new notification channel. The destination should have been validated
+// with the kind Destination method.
+func NewNotificationChannel(
+	name string,
+	kind NotificationChannelKind,
+	destination string,
+	uid auth.UserID,
+) (c NotificationChannel) {
+	c.apply(NotificationChannelCreated{
+		ID:          id.New[NotificationChannelID](),
+		Name:        name,
+		Kind:        kind,
+		Destination: destination,
+		Created:     shared.NewAction(uid),
+	})
+
+	return c
+}
+
+// Recreates a notification channel from the persistent storage.
+func NotificationChannelFrom(scanner storage.Scanner) (c NotificationChannel, err error) {
+	var (
+		createdAt time.Time
+		createdBy auth.UserID
+	)
+
+	err = scanner.Scan(
+		&c.id,
+		&c.name,
+		&c.kind,
+		&c.destination,
+		&c.template,
+		&c.rules,
+		&createdAt,
+		&createdBy,
+	)
+
+	c.created = shared.ActionFrom(createdBy, createdAt)
+
+	return c, err
+}
+
+// Renames the channel.
+func (c *NotificationChannel) Rename(name string) {
+	if c.name == name {
+		return
+	}
+
+	c.apply(NotificationChannelRenamed{
+		ID:   c.id,
+		Name: name,
+	})
+}
+
+// Updates where notifications are sent.
+func (c *NotificationChannel) HasDestination(destination string) {
+	if c.destination == destination {
+		return
+	}
+
+	c.apply(NotificationChannelDestinationChanged{
+		ID:          c.id,
+		Destination: destination,
+	})
+}
+
+// Use a custom template to render messages. If not set, a default one will be used.
+func (c *NotificationChannel) UseTemplate(tmpl monad.Maybe[string]) {
+	if c.template == tmpl {
+		return
+	}
+
+	c.apply(NotificationChannelTemplateChanged{
+		ID:       c.id,
+		Template: tmpl,
+	})
+}
+
+// Replaces the subscription rules of this channel.
+func (c *NotificationChannel) SubscribeTo(rules NotificationRules) {
+	if slices.EqualFunc(c.rules, rules, NotificationRule.equals) {
+		return
+	}
+
+	c.apply(NotificationChannelRulesChanged{
+		ID:    c.id,
+		Rules: rules,
+	})
+}
+
+func (c *NotificationChannel) Delete() {
+	c.apply(NotificationChannelDeleted{
+		ID: c.id,
+	})
+}
+
+// Checks if the channel should be notified of the given event. Target related events
+// have no app and environment and will only match rules without them.
+func (c *NotificationChannel) IsSubscribedTo(
+	evt NotificationEvent,
+	app monad.Maybe[AppID],
+	env monad.Maybe[Environment],
+) bool {
+	return slices.ContainsFunc(c.rules, func(rule NotificationRule) bool {
+		return slices.Contains(rule.Events, evt) &&
+			matchesOptional(rule.App, app) &&
+			matchesOptional(rule.Environment, env)
+	})
+}
+
+func (c *NotificationChannel) ID() NotificationChannelID     { return c.id }
+func (c *NotificationChannel) Name() string                  { return c.name }
+func (c *NotificationChannel) Kind() NotificationChannelKind { return c.kind }
+func (c *NotificationChannel) Destination() string           { return c.destination }
+func (c *NotificationChannel) Template() monad.Maybe[string] { return c.template }
+func (c *NotificationChannel) Rules() NotificationRules      { return c.rules }
+
+func (c *NotificationChannel) apply(e event.Event) {
+	switch v := e.(type) {
+	case NotificationChannelCreated:
+		c.id = v.ID
+		c.name = v.Name
+		c.kind = v.Kind
+		c.destination = v.Destination
+		c.created = v.Created
+	case NotificationChannelRenamed:
+		c.name = v.Name
+	case NotificationChannelDestinationChanged:
+		c.destination = v.Destination
+	case NotificationChannelTemplateChanged:
+		c.template = v.Template
+	case NotificationChannelRulesChanged:
+		c.rules = v.Rules
+	}
+
+	event.Store(c, e)
+}
+
+func (r NotificationRules) Value() (driver.Value, error) { return storage.ValueJSON(r) }
+func (r *NotificationRules) Scan(value any) error        { return storage.ScanJSON(value, r) }
+
+func (r NotificationRule) equals(other NotificationRule) bool {
+	return r.App == other.App &&
+		r.Environment == other.Environment &&
+		slices.Equal(r.Events, other.Events)
+}
+
+// A rule value matches if it has not been set or if it equals the given one.
+func matchesOptional[T comparable](rule monad.Maybe[T], value monad.Maybe[T]) bool {
+	expected, isSet := rule.TryGet()
+
+	if !isSet {
+		return true
+	}
+
+	actual, hasValue := value.TryGet()
+
+	return hasValue && actual == expected
+}
@@ -0,0 +1,185 @@
+package domain_test
+
+import (
+	"testing"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+func Test_NotificationChannel(t *testing.T) {
+	t.Run("should validate the kind", func(t *testing.T) {
+		_, err := domain.NotificationChannelKindFrom("pigeon")
+
+		assert.ErrorIs(t, domain.ErrInvalidNotificationChannelKind, err)
+
+		kind, err := domain.NotificationChannelKindFrom("slack")
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.NotificationChannelKindSlack, kind)
+	})
+
+	t.Run("should validate the event", func(t *testing.T) {
+		_, err := domain.NotificationEventFrom("deployment_started")
+
+		assert.ErrorIs(t, domain.ErrInvalidNotificationEvent, err)
+
+		evt, err := domain.NotificationEventFrom("target_failed")
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.NotificationEventTargetFailed, evt)
+	})
+
+	t.Run("should validate the template", func(t *testing.T) {
+		_, err := domain.NotificationTemplateFrom("{{ .AppName ")
+
+		assert.ErrorIs(t, domain.ErrInvalidNotificationTemplate, err)
+
+		tmpl, err := domain.NotificationTemplateFrom("{{ .AppName }} deployed")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "{{ .AppName }} deployed", tmpl)
+	})
+
+	t.Run("should validate the destination based on the kind", func(t *testing.T) {
+		_, err := domain.NotificationChannelKindWebhook.Destination("not an url")
+		assert.ErrorIs(t, domain.ErrInvalidNotificationDestination, err)
+
+		_, err = domain.NotificationChannelKindEmail.Destination("http://example.com")
+		assert.ErrorIs(t, domain.ErrInvalidNotificationDestination, err)
+
+		_, err = domain.NotificationChannelKindSlack.Destination("https://hooks.slack.com/services/xxx")
+		assert.Nil(t, err)
+
+		_, err = domain.NotificationChannelKindEmail.Destination("john@doe.com, jane@doe.com")
+		assert.Nil(t, err)
+	})
+
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			name                    = "ops"
+			destination             = "http://example.com/hook"
+			uid         auth.UserID = "uid"
+		)
+
+		c := domain.NewNotificationChannel(name, domain.NotificationChannelKindWebhook, destination, uid)
+
+		assert.NotZero(t, c.ID())
+		assert.Equal(t, name, c.Name())
+		assert.Equal(t, domain.NotificationChannelKindWebhook, c.Kind())
+		assert.Equal(t, destination, c.Destination())
+		assert.False(t, c.Template().HasValue())
+
+		created := assert.EventIs[domain.NotificationChannelCreated](t, &c, 0)
+
+		assert.Equal(t, domain.NotificationChannelCreated{
+			ID:          c.ID(),
+			Name:        name,
+			Kind:        domain.NotificationChannelKindWebhook,
+			Destination: destination,
+			Created:     shared.ActionFrom(uid, assert.NotZero(t, created.Created.At())),
+		}, created)
+	})
+
+	t.Run("could be renamed and raise the event only if different", func(t *testing.T) {
+		c := fixture.NotificationChannel(fixture.WithNotificationChannelName("ops"))
+
+		c.Rename("ops")
+		c.Rename("alerts")
+		c.Rename("alerts")
+
+		assert.HasNEvents(t, 2, &c, "should raise the event once per different name")
+		assert.Equal(t, domain.NotificationChannelRenamed{
+			ID:   c.ID(),
+			Name: "alerts",
+		}, assert.EventIs[domain.NotificationChannelRenamed](t, &c, 1))
+	})
+
+	t.Run("could have its destination changed and raise the event only if different", func(t *testing.T) {
+		c := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindWebhook, "http://example.com"))
+
+		c.HasDestination("http://example.com")
+		c.HasDestination("http://another.com")
+
+		assert.HasNEvents(t, 2, &c, "should raise the event once per different destination")
+		assert.Equal(t, domain.NotificationChannelDestinationChanged{
+			ID:          c.ID(),
+			Destination: "http://another.com",
+		}, assert.EventIs[domain.NotificationChannelDestinationChanged](t, &c, 1))
+	})
+
+	t.Run("could use a custom template and raise the event only if different", func(t *testing.T) {
+		c := fixture.NotificationChannel()
+
+		c.UseTemplate(monad.None[string]())
+		c.UseTemplate(monad.Value("{{ .AppName }}"))
+		c.UseTemplate(monad.Value("{{ .AppName }}"))
+		c.UseTemplate(monad.None[string]())
+
+		assert.HasNEvents(t, 3, &c, "should raise the event once per different template")
+		assert.Equal(t, domain.NotificationChannelTemplateChanged{
+			ID:       c.ID(),
+			Template: monad.Value("{{ .AppName }}"),
+		}, assert.EventIs[domain.NotificationChannelTemplateChanged](t, &c, 1))
+		assert.Equal(t, domain.NotificationChannelTemplateChanged{
+			ID: c.ID(),
+		}, assert.EventIs[domain.NotificationChannelTemplateChanged](t, &c, 2))
+	})
+
+	t.Run("could have its rules changed and raise the event only if different", func(t *testing.T) {
+		c := fixture.NotificationChannel()
+		rules := domain.NotificationRules{
+			{Events: []domain.NotificationEvent{domain.NotificationEventDeploymentFailed}},
+		}
+
+		c.SubscribeTo(rules)
+		c.SubscribeTo(domain.NotificationRules{
+			{Events: []domain.NotificationEvent{domain.NotificationEventDeploymentFailed}},
+		})
+
+		assert.HasNEvents(t, 2, &c, "should raise the event once per different rules")
+		assert.DeepEqual(t, domain.NotificationChannelRulesChanged{
+			ID:    c.ID(),
+			Rules: rules,
+		}, assert.EventIs[domain.NotificationChannelRulesChanged](t, &c, 1))
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		c := fixture.NotificationChannel()
+
+		c.Delete()
+
+		assert.Equal(t, domain.NotificationChannelDeleted{
+			ID: c.ID(),
+		}, assert.EventIs[domain.NotificationChannelDeleted](t, &c, 1))
+	})
+
+	t.Run("should match events based on its rules", func(t *testing.T) {
+		c := fixture.NotificationChannel(fixture.WithNotificationRules(
+			domain.NotificationRule{
+				App:         monad.Value(domain.AppID("app")),
+				Environment: monad.Value(domain.Production),
+				Events:      []domain.NotificationEvent{domain.NotificationEventDeploymentSucceeded},
+			},
+			domain.NotificationRule{
+				Events: []domain.NotificationEvent{domain.NotificationEventDeploymentFailed, domain.NotificationEventTargetFailed},
+			},
+		))
+
+		var (
+			app  = monad.Value(domain.AppID("app"))
+			prod = monad.Value(domain.Production)
+		)
+
+		assert.True(t, c.IsSubscribedTo(domain.NotificationEventDeploymentSucceeded, app, prod))
+		assert.False(t, c.IsSubscribedTo(domain.NotificationEventDeploymentSucceeded, app, monad.Value(domain.Staging)))
+		assert.False(t, c.IsSubscribedTo(domain.NotificationEventDeploymentSucceeded, monad.Value(domain.AppID("other")), prod))
+		assert.False(t, c.IsSubscribedTo(domain.NotificationEventDeploymentSucceeded, monad.None[domain.AppID](), monad.None[domain.Environment]()))
+		assert.True(t, c.IsSubscribedTo(domain.NotificationEventDeploymentFailed, monad.Value(domain.AppID("other")), prod))
+		assert.True(t, c.IsSubscribedTo(domain.NotificationEventTargetFailed, monad.None[domain.AppID](), monad.None[domain.Environment]()))
+	})
+}
@@ -2,8 +2,11 @@ package domain
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
 )
 
@@ -37,5 +40,53 @@ type (
 		CleanupTarget(context.Context, Target, CleanupStrategy) error
 		// Cleanup an application on the specified target and environment, which means removing every possible stuff related to it
 		Cleanup(context.Context, AppID, Target, Environment, CleanupStrategy) error
+		// Retrieve the logs of a running service of an application on the specified target and environment.
+		// You MUST close the returned reader once done.
+		ServiceLogs(context.Context, AppID, Target, Environment, string, ServiceLogsOptions) (io.ReadCloser, error)
+		// Inspect the services currently running on the specified target and returns their runtime status.
+		RuntimeStatus(context.Context, Target, RuntimeStatusCriteria) ([]ServiceStatus, error)
 	}
+
+	// Options used when retrieving the logs of a running service.
+	ServiceLogsOptions struct {
+		Since  monad.Maybe[time.Time] // Only returns logs written after this date
+		Tail   monad.Maybe[uint]      // Number of lines to return from the end of the logs
+		Follow bool                   // Keep the stream open to return logs as they are written
+	}
+
+	// Criteria used to filter services when retrieving their runtime status.
+	RuntimeStatusCriteria struct {
+		App         monad.Maybe[AppID]
+		Environment monad.Maybe[Environment]
+	}
+
+	// Runtime status of a service as reported by the provider.
+	ServiceStatus struct {
+		App          AppID
+		Environment  Environment
+		Name         string
+		State        ServiceState
+		Health       monad.Maybe[string] // Health check status (starting, healthy, unhealthy) if the service defines one
+		RestartCount int
+		StartedAt    monad.Maybe[time.Time]
+		Image        string
+		ImageDigest  monad.Maybe[string]
+	}
+
+	ServiceState string
 )
+
+const (
+	ServiceStateCreated    ServiceState = "created"
+	ServiceStateRunning    ServiceState = "running"
+	ServiceStateRestarting ServiceState = "restarting"
+	ServiceStatePaused     ServiceState = "paused"
+	ServiceStateExited     ServiceState = "exited"
+	ServiceStateDead       ServiceState = "dead"
+	ServiceStateUnknown    ServiceState = "unknown"
+)
+
+// Whether the service is up and, if it defines an health check, healthy.
+func (s ServiceStatus) IsHealthy() bool {
+	return s.State == ServiceStateRunning && s.Health.Get("healthy") == "healthy"
+}
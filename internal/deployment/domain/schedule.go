@@ -0,0 +1,226 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/cron"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+var (
+	ErrInvalidScheduleExpression = apperr.New("invalid_schedule_expression")
+	ErrInvalidNotBefore          = apperr.New("invalid_not_before")
+)
+
+type (
+	ScheduleID string
+
+	// Recurring redeploy of the latest successful deployment of an application environment.
+	// Occurrences are computed in UTC.
+	Schedule struct {
+		event.Emitter
+
+		id          ScheduleID
+		app         AppID
+		environment Environment
+		expression  cron.Expression
+		nextRunAt   time.Time
+		lastRunAt   monad.Maybe[time.Time]
+		created     shared.Action[auth.UserID]
+	}
+
+	SchedulesReader interface {
+		GetByID(context.Context, ScheduleID) (Schedule, error)
+		GetDue(context.Context, time.Time) ([]Schedule, error) // Retrieve schedules which should have run at the given time
+	}
+
+	SchedulesWriter interface {
+		Write(context.Context, ...*Schedule) error
+	}
+
+	ScheduleCreated struct {
+		bus.Notification
+
+		ID          ScheduleID
+		App         AppID
+		Environment Environment
+		Expression  cron.Expression
+		NextRunAt   time.Time
+		Created     shared.Action[auth.UserID]
+	}
+
+	ScheduleExpressionChanged struct {
+		bus.Notification
+
+		ID         ScheduleID
+		Expression cron.Expression
+		NextRunAt  time.Time
+	}
+
+	ScheduleTriggered struct {
+		bus.Notification
+
+		ID        ScheduleID
+		RanAt     time.Time
+		NextRunAt time.Time
+	}
+
+	ScheduleDeleted struct {
+		bus.Notification
+
+		ID ScheduleID
+	}
+)
+
+func (ScheduleCreated) Name_() string {
+	return "deployment.event.schedule_created"
+}
+func (ScheduleExpressionChanged) Name_() string {
+	return "deployment.event.schedule_expression_changed"
+}
+func (ScheduleTriggered) Name_() string {
+	return "deployment.event.schedule_triggered"
+}
+func (ScheduleDeleted) Name_() string {
+	return "deployment.event.schedule_deleted"
+}
+
+// Parses a cron expression, making sure it will actually run at some point.
+func ScheduleExpressionFrom(value string) (cron.Expression, error) {
+	expr, err := cron.Parse(value)
+
+	if err != nil || expr.Next(time.Now().UTC()).IsZero() {
+		return cron.Expression{}, ErrInvalidScheduleExpression
+	}
+
+	return expr, nil
+}
+
+// Parses a RFC3339 date used to delay a deployment.
+func NotBeforeFrom(value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+
+	if err != nil {
+		return t, ErrInvalidNotBefore
+	}
+
+	return t, nil
+}
+
+// Creates a new schedule on the given app environment. The expression should have been
+// validated with ScheduleExpressionFrom.
+func (a *App) NewSchedule(
+	env Environment,
+	expr cron.Expression,
+	createdBy auth.UserID,
+) (s Schedule, err error) {
+	if a.cleanupRequested.HasValue() {
+		return s, ErrAppCleanupRequested
+	}
+
+	s.apply(ScheduleCreated{
+		ID:          id.New[ScheduleID](),
+		App:         a.id,
+		Environment: env,
+		Expression:  expr,
+		NextRunAt:   expr.Next(time.Now().UTC()),
+		Created:     shared.NewAction(createdBy),
+	})
+
+	return s, nil
+}
+
+// Recreates a schedule from the persistent storage.
+func ScheduleFrom(scanner storage.Scanner) (s Schedule, err error) {
+	var (
+		expression string
+		createdAt  time.Time
+		createdBy  auth.UserID
+	)
+
+	err = scanner.Scan(
+		&s.id,
+		&s.app,
+		&s.environment,
+		&expression,
+		&s.nextRunAt,
+		&s.lastRunAt,
+		&createdAt,
+		&createdBy,
+	)
+
+	if err != nil {
+		return s, err
+	}
+
+	s.created = shared.ActionFrom(createdBy, createdAt)
+	s.expression, err = cron.Parse(expression)
+
+	return s, err
+}
+
+// Updates the schedule expression, the next run time is computed again.
+func (s *Schedule) HasExpression(expr cron.Expression) {
+	if s.expression.String() == expr.String() {
+		return
+	}
+
+	s.apply(ScheduleExpressionChanged{
+		ID:         s.id,
+		Expression: expr,
+		NextRunAt:  expr.Next(time.Now().UTC()),
+	})
+}
+
+// Mark the schedule has been triggered at the given time and plan the next run.
+func (s *Schedule) Triggered(at time.Time) {
+	at = at.UTC()
+
+	s.apply(ScheduleTriggered{
+		ID:        s.id,
+		RanAt:     at,
+		NextRunAt: s.expression.Next(at),
+	})
+}
+
+func (s *Schedule) Delete() {
+	s.apply(ScheduleDeleted{
+		ID: s.id,
+	})
+}
+
+func (s *Schedule) ID() ScheduleID                      { return s.id }
+func (s *Schedule) App() AppID                          { return s.app }
+func (s *Schedule) Environment() Environment            { return s.environment }
+func (s *Schedule) Expression() cron.Expression         { return s.expression }
+func (s *Schedule) NextRunAt() time.Time                { return s.nextRunAt }
+func (s *Schedule) LastRunAt() monad.Maybe[time.Time]   { return s.lastRunAt }
+func (s *Schedule) Created() shared.Action[auth.UserID] { return s.created }
+
+func (s *Schedule) apply(e event.Event) {
+	switch evt := e.(type) {
+	case ScheduleCreated:
+		s.id = evt.ID
+		s.app = evt.App
+		s.environment = evt.Environment
+		s.expression = evt.Expression
+		s.nextRunAt = evt.NextRunAt
+		s.created = evt.Created
+	case ScheduleExpressionChanged:
+		s.expression = evt.Expression
+		s.nextRunAt = evt.NextRunAt
+	case ScheduleTriggered:
+		s.lastRunAt.Set(evt.RanAt)
+		s.nextRunAt = evt.NextRunAt
+	}
+
+	event.Store(s, e)
+}
@@ -0,0 +1,113 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	shared "github.com/YuukanOO/seelf/pkg/domain"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+func Test_Schedule(t *testing.T) {
+	t.Run("should validate the expression", func(t *testing.T) {
+		_, err := domain.ScheduleExpressionFrom("every night")
+		assert.ErrorIs(t, domain.ErrInvalidScheduleExpression, err)
+
+		_, err = domain.ScheduleExpressionFrom("0 0 30 2 *")
+		assert.ErrorIs(t, domain.ErrInvalidScheduleExpression, err, "should reject expressions which never match")
+
+		expr, err := domain.ScheduleExpressionFrom("0 22 * * *")
+		assert.Nil(t, err)
+		assert.Equal(t, "0 22 * * *", expr.String())
+	})
+
+	t.Run("should validate the not before date", func(t *testing.T) {
+		_, err := domain.NotBeforeFrom("tonight")
+		assert.ErrorIs(t, domain.ErrInvalidNotBefore, err)
+
+		value, err := domain.NotBeforeFrom("2024-03-15T22:00:00+01:00")
+		assert.Nil(t, err)
+		assert.Equal(t, time.Date(2024, time.March, 15, 21, 0, 0, 0, time.UTC), value.UTC())
+	})
+
+	t.Run("could be created", func(t *testing.T) {
+		var (
+			app              = fixture.App()
+			uid  auth.UserID = "uid"
+			expr             = must.Panic(domain.ScheduleExpressionFrom("0 22 * * *"))
+		)
+
+		s, err := app.NewSchedule(domain.Staging, expr, uid)
+
+		assert.Nil(t, err)
+		assert.NotZero(t, s.ID())
+		assert.Equal(t, app.ID(), s.App())
+		assert.Equal(t, domain.Staging, s.Environment())
+		assert.Equal(t, 22, s.NextRunAt().Hour())
+		assert.False(t, s.LastRunAt().HasValue())
+
+		created := assert.EventIs[domain.ScheduleCreated](t, &s, 0)
+
+		assert.Equal(t, domain.ScheduleCreated{
+			ID:          s.ID(),
+			App:         app.ID(),
+			Environment: domain.Staging,
+			Expression:  expr,
+			NextRunAt:   s.NextRunAt(),
+			Created:     shared.ActionFrom(uid, assert.NotZero(t, created.Created.At())),
+		}, created)
+	})
+
+	t.Run("could not be created if the app cleanup has been requested", func(t *testing.T) {
+		app := fixture.App()
+		app.RequestCleanup("uid")
+
+		_, err := app.NewSchedule(domain.Production, must.Panic(domain.ScheduleExpressionFrom("@daily")), "uid")
+
+		assert.ErrorIs(t, domain.ErrAppCleanupRequested, err)
+	})
+
+	t.Run("could have its expression changed and raise the event only if different", func(t *testing.T) {
+		s := fixture.Schedule(fixture.WithScheduleExpression("@daily"))
+		expr := must.Panic(domain.ScheduleExpressionFrom("@hourly"))
+
+		s.HasExpression(must.Panic(domain.ScheduleExpressionFrom("@daily")))
+		s.HasExpression(expr)
+
+		assert.HasNEvents(t, 2, &s, "should raise the event only if the expression is different")
+		assert.Equal(t, domain.ScheduleExpressionChanged{
+			ID:         s.ID(),
+			Expression: expr,
+			NextRunAt:  s.NextRunAt(),
+		}, assert.EventIs[domain.ScheduleExpressionChanged](t, &s, 1))
+		assert.Equal(t, 0, s.NextRunAt().Minute())
+	})
+
+	t.Run("could be triggered and plan the next run", func(t *testing.T) {
+		s := fixture.Schedule(fixture.WithScheduleExpression("0 22 * * *"))
+		at := time.Date(2024, time.March, 15, 22, 0, 30, 0, time.UTC)
+
+		s.Triggered(at)
+
+		assert.Equal(t, at, s.LastRunAt().MustGet())
+		assert.Equal(t, domain.ScheduleTriggered{
+			ID:        s.ID(),
+			RanAt:     at,
+			NextRunAt: time.Date(2024, time.March, 16, 22, 0, 0, 0, time.UTC),
+		}, assert.EventIs[domain.ScheduleTriggered](t, &s, 1))
+	})
+
+	t.Run("could be deleted", func(t *testing.T) {
+		s := fixture.Schedule()
+
+		s.Delete()
+
+		assert.Equal(t, domain.ScheduleDeleted{
+			ID: s.ID(),
+		}, assert.EventIs[domain.ScheduleDeleted](t, &s, 1))
+	})
+}
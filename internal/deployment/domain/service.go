@@ -192,6 +192,27 @@ func (s Services) Entrypoints() []Entrypoint {
 	return result
 }
 
+// Urls of http entrypoints automatically exposed on the given target url.
+func (s Services) HttpUrls(targetUrl Url) []Url {
+	var urls []Url
+
+	for _, entrypoint := range s.Entrypoints() {
+		if entrypoint.IsCustom() || entrypoint.Router() != RouterHttp {
+			continue
+		}
+
+		url := targetUrl
+
+		if subdomain, isSet := entrypoint.Subdomain().TryGet(); isSet {
+			url = url.SubDomain(subdomain)
+		}
+
+		urls = append(urls, url)
+	}
+
+	return urls
+}
+
 // Retrieve all custom entrypoints. Ones that are not natively
 // managed by the target and requires a manual configuration.
 func (s Services) CustomEntrypoints() []Entrypoint {
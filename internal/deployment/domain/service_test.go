@@ -8,6 +8,7 @@ import (
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/fixture"
 	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/must"
 )
 
 func Test_ServicesBuilder(t *testing.T) {
@@ -173,6 +174,25 @@ func Test_Services(t *testing.T) {
 		assert.Equal(t, tcp, entrypoints[1])
 	})
 
+	t.Run("should be able to return urls of automatically exposed http entrypoints", func(t *testing.T) {
+		deployment := fixture.Deployment(fixture.FromApp(fixture.App(fixture.WithAppName("my-app"))))
+		builder := deployment.Config().ServicesBuilder()
+
+		service := builder.AddService("app", "")
+		service.AddHttpEntrypoint(80, false)
+		service.AddHttpEntrypoint(8080, true)
+		service.AddTCPEntrypoint(5432, false)
+		service = builder.AddService("other", "")
+		service.AddHttpEntrypoint(3000, false)
+		services := builder.Services()
+
+		urls := services.HttpUrls(must.Panic(domain.UrlFrom("https://example.com")))
+
+		assert.HasLength(t, 2, urls)
+		assert.Equal(t, "https://my-app.example.com", urls[0].String())
+		assert.Equal(t, "https://other.my-app.example.com", urls[1].String())
+	})
+
 	t.Run("should implement the valuer interface", func(t *testing.T) {
 		app := fixture.App(fixture.WithAppName("my-app"))
 		deployment := fixture.Deployment(fixture.FromApp(app))
@@ -22,6 +22,12 @@ type (
 		NeedVersionControl() bool
 	}
 
+	// Implemented by source data targeting a specific commit of the app repository.
+	CommitSourceData interface {
+		SourceData
+		Commit() string
+	}
+
 	// Represents a source which has initiated a deployment.
 	Source interface {
 		Prepare(context.Context, App, any) (SourceData, error)      // Prepare the given payload for the given application, doing any needed validation
@@ -3,6 +3,7 @@ package domain
 import (
 	"context"
 	"database/sql/driver"
+	"encoding/json"
 	"time"
 
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
@@ -24,6 +25,7 @@ var (
 	ErrTargetProviderUpdateNotPermitted = apperr.New("target_provider_update_not_permitted")
 	ErrTargetCleanupNeeded              = apperr.New("target_cleanup_needed")
 	ErrTargetCleanupRequested           = apperr.New("target_cleanup_requested")
+	ErrInvalidMaxConcurrentDeployments  = apperr.New("invalid_max_concurrent_deployments")
 )
 
 const (
@@ -53,6 +55,7 @@ type (
 		provider          ProviderConfig
 		state             TargetState
 		customEntrypoints TargetEntrypoints
+		maxDeployments    monad.Maybe[int] // Maximum number of deployments running at the same time, unlimited if not set
 		cleanupRequested  monad.Maybe[shared.Action[auth.UserID]]
 		created           shared.Action[auth.UserID]
 	}
@@ -120,6 +123,19 @@ type (
 		Entrypoints TargetEntrypoints
 	}
 
+	TargetMaxDeploymentsChanged struct {
+		bus.Notification
+
+		ID  TargetID
+		Max int
+	}
+
+	TargetMaxDeploymentsRemoved struct {
+		bus.Notification
+
+		ID TargetID
+	}
+
 	TargetCleanupRequested struct {
 		bus.Notification
 
@@ -144,6 +160,14 @@ func (TargetEntrypointsChanged) Name_() string { return "deployment.event.target
 func (TargetCleanupRequested) Name_() string   { return "deployment.event.target_cleanup_requested" }
 func (TargetDeleted) Name_() string            { return "deployment.event.target_deleted" }
 
+func (TargetMaxDeploymentsChanged) Name_() string {
+	return "deployment.event.target_max_deployments_changed"
+}
+
+func (TargetMaxDeploymentsRemoved) Name_() string {
+	return "deployment.event.target_max_deployments_removed"
+}
+
 func (e TargetStateChanged) WentToConfiguringState() bool {
 	return e.State.status == TargetStatusConfiguring
 }
@@ -176,6 +200,7 @@ func TargetFrom(scanner storage.Scanner) (t Target, err error) {
 	var (
 		createdAt             time.Time
 		createdBy             auth.UserID
+		maxDeployments        monad.Maybe[int64]
 		deleteRequestedAt     monad.Maybe[time.Time]
 		deleteRequestedBy     monad.Maybe[string]
 		providerDiscriminator string
@@ -193,6 +218,7 @@ func TargetFrom(scanner storage.Scanner) (t Target, err error) {
 		&t.state.errcode,
 		&t.state.lastReadyVersion,
 		&t.customEntrypoints,
+		&maxDeployments,
 		&deleteRequestedAt,
 		&deleteRequestedBy,
 		&createdAt,
@@ -203,6 +229,10 @@ func TargetFrom(scanner storage.Scanner) (t Target, err error) {
 		return t, err
 	}
 
+	if max, isSet := maxDeployments.TryGet(); isSet {
+		t.maxDeployments.Set(int(max))
+	}
+
 	if requestedAt, isSet := deleteRequestedAt.TryGet(); isSet {
 		t.cleanupRequested.Set(
 			shared.ActionFrom(auth.UserID(deleteRequestedBy.MustGet()), requestedAt),
@@ -233,6 +263,51 @@ func (t *Target) Rename(name string) error {
 	return nil
 }
 
+// Validates the maximum number of deployments allowed to run at the same time on a target.
+func MaxConcurrentDeploymentsFrom(value int) (int, error) {
+	if value < 1 {
+		return 0, ErrInvalidMaxConcurrentDeployments
+	}
+
+	return value, nil
+}
+
+// Limit the number of deployments running at the same time on this target. The value
+// should have been validated with MaxConcurrentDeploymentsFrom.
+func (t *Target) LimitConcurrentDeployments(max int) error {
+	if t.cleanupRequested.HasValue() {
+		return ErrTargetCleanupRequested
+	}
+
+	if existing, isSet := t.maxDeployments.TryGet(); isSet && existing == max {
+		return nil
+	}
+
+	t.apply(TargetMaxDeploymentsChanged{
+		ID:  t.id,
+		Max: max,
+	})
+
+	return nil
+}
+
+// Remove the limit of deployments running at the same time on this target.
+func (t *Target) UnlimitConcurrentDeployments() error {
+	if t.cleanupRequested.HasValue() {
+		return ErrTargetCleanupRequested
+	}
+
+	if !t.maxDeployments.HasValue() {
+		return nil
+	}
+
+	t.apply(TargetMaxDeploymentsRemoved{
+		ID: t.id,
+	})
+
+	return nil
+}
+
 // Mark this target as exposing automatically services on the given root url.
 func (t *Target) ExposeServicesAutomatically(urlRequirement TargetUrlRequirement) error {
 	if t.cleanupRequested.HasValue() {
@@ -487,12 +562,18 @@ func (t *Target) Delete(cleanedUp bool) error {
 }
 
 func (t *Target) ID() TargetID                         { return t.id }
+func (t *Target) Name() string                         { return t.name }
+func (t *Target) State() TargetState                   { return t.state }
 func (t *Target) Url() monad.Maybe[Url]                { return t.url }
 func (t *Target) IsManual() bool                       { return !t.url.HasValue() }
 func (t *Target) Provider() ProviderConfig             { return t.provider }
 func (t *Target) CustomEntrypoints() TargetEntrypoints { return t.customEntrypoints } // FIXME: Should we return a copy?
 func (t *Target) CurrentVersion() time.Time            { return t.state.version }
 
+func (t *Target) MaxConcurrentDeployments() monad.Maybe[int] {
+	return t.maxDeployments
+}
+
 // Returns true if the given configuration version is different from the current one.
 func (t *Target) IsOutdated(version time.Time) bool {
 	return t.state.isOutdated(version)
@@ -539,6 +620,10 @@ func (t *Target) apply(e event.Event) {
 		t.provider = evt.Provider
 	case TargetEntrypointsChanged:
 		t.customEntrypoints = evt.Entrypoints
+	case TargetMaxDeploymentsChanged:
+		t.maxDeployments.Set(evt.Max)
+	case TargetMaxDeploymentsRemoved:
+		t.maxDeployments.Unset()
 	case TargetCleanupRequested:
 		t.cleanupRequested.Set(evt.Requested)
 	case TargetStateChanged:
@@ -567,7 +652,7 @@ func newTargetState() (t TargetState) {
 // Mark the state as configuring and update the version.
 func (t *TargetState) reconfigure() {
 	t.status = TargetStatusConfiguring
-	t.version = time.Now().UTC()
+	t.version = time.Now().UTC().Truncate(time.Microsecond) // Versions are compared for equality so keep the precision every database could store
 	t.errcode.Unset()
 }
 
@@ -608,6 +693,38 @@ func (t TargetState) ErrCode() monad.Maybe[string]             { return t.errcod
 func (t TargetState) Version() time.Time                       { return t.version }
 func (t TargetState) LastReadyVersion() monad.Maybe[time.Time] { return t.lastReadyVersion }
 
+// Type needed to marshal an unexposed TargetState data.
+type marshalledTargetState struct {
+	Status           TargetStatus           `json:"status"`
+	Version          time.Time              `json:"version"`
+	ErrCode          monad.Maybe[string]    `json:"error_code"`
+	LastReadyVersion monad.Maybe[time.Time] `json:"last_ready_version"`
+}
+
+func (t TargetState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(marshalledTargetState{
+		Status:           t.status,
+		Version:          t.version,
+		ErrCode:          t.errcode,
+		LastReadyVersion: t.lastReadyVersion,
+	})
+}
+
+func (t *TargetState) UnmarshalJSON(b []byte) error {
+	var m marshalledTargetState
+
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+
+	t.status = m.Status
+	t.version = m.Version
+	t.errcode = m.ErrCode
+	t.lastReadyVersion = m.LastReadyVersion
+
+	return nil
+}
+
 func (e TargetEntrypoints) Value() (driver.Value, error) { return storage.ValueJSON(e) }
 func (e *TargetEntrypoints) Scan(value any) error        { return storage.ScanJSON(value, e) }
 
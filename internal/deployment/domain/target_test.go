@@ -1,6 +1,7 @@
 package domain_test
 
 import (
+	"encoding/json"
 	"errors"
 	"testing"
 	"time"
@@ -61,6 +62,22 @@ func Test_Target(t *testing.T) {
 		})
 	})
 
+	t.Run("should raise state changed events which could be (un)marshalled to JSON", func(t *testing.T) {
+		target := fixture.Target()
+		target.Configured(target.CurrentVersion(), nil, nil)
+
+		evt := assert.EventIs[domain.TargetStateChanged](t, &target, 1)
+
+		data, err := json.Marshal(evt)
+		assert.Nil(t, err)
+
+		var unmarshalled domain.TargetStateChanged
+		assert.Nil(t, json.Unmarshal(data, &unmarshalled))
+
+		assert.DeepEqual(t, evt, unmarshalled)
+		assert.Equal(t, domain.TargetStatusReady, unmarshalled.State.Status())
+	})
+
 	t.Run("should expose a method to check if a version is outdated or not", func(t *testing.T) {
 		t.Run("should return true if the version is outdated", func(t *testing.T) {
 			target := fixture.Target()
@@ -111,6 +128,55 @@ func Test_Target(t *testing.T) {
 		})
 	})
 
+	t.Run("could limit its concurrent deployments", func(t *testing.T) {
+		t.Run("should require a positive limit", func(t *testing.T) {
+			_, err := domain.MaxConcurrentDeploymentsFrom(0)
+
+			assert.ErrorIs(t, domain.ErrInvalidMaxConcurrentDeployments, err)
+		})
+
+		t.Run("should raise the event if the limit is different", func(t *testing.T) {
+			target := fixture.Target()
+
+			assert.Nil(t, target.LimitConcurrentDeployments(2))
+			assert.Nil(t, target.LimitConcurrentDeployments(2))
+
+			assert.HasNEvents(t, 2, &target)
+			changed := assert.EventIs[domain.TargetMaxDeploymentsChanged](t, &target, 1)
+			assert.Equal(t, domain.TargetMaxDeploymentsChanged{
+				ID:  target.ID(),
+				Max: 2,
+			}, changed)
+			assert.Equal(t, 2, target.MaxConcurrentDeployments().MustGet())
+		})
+
+		t.Run("should raise the event when the limit is removed", func(t *testing.T) {
+			target := fixture.Target()
+
+			assert.Nil(t, target.UnlimitConcurrentDeployments())
+			assert.HasNEvents(t, 1, &target)
+
+			assert.Nil(t, target.LimitConcurrentDeployments(2))
+			assert.Nil(t, target.UnlimitConcurrentDeployments())
+
+			assert.HasNEvents(t, 3, &target)
+			removed := assert.EventIs[domain.TargetMaxDeploymentsRemoved](t, &target, 2)
+			assert.Equal(t, domain.TargetMaxDeploymentsRemoved{
+				ID: target.ID(),
+			}, removed)
+			assert.False(t, target.MaxConcurrentDeployments().HasValue())
+		})
+
+		t.Run("should returns an error if the target cleanup has been requested", func(t *testing.T) {
+			target := fixture.Target()
+			target.Configured(target.CurrentVersion(), nil, nil)
+			assert.Nil(t, target.RequestCleanup(false, "uid"))
+
+			assert.ErrorIs(t, domain.ErrTargetCleanupRequested, target.LimitConcurrentDeployments(2))
+			assert.ErrorIs(t, domain.ErrTargetCleanupRequested, target.UnlimitConcurrentDeployments())
+		})
+	})
+
 	t.Run("could be configured as exposing services automatically with an url", func(t *testing.T) {
 		t.Run("should require the url to be unique", func(t *testing.T) {
 			target := fixture.Target()
@@ -1,14 +1,58 @@
 package domain
 
 import (
+	"context"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/monad"
 )
 
-// Holds the vcs configuration of an application.
-type VersionControl struct {
-	url   Url
-	token monad.Maybe[string]
-}
+var ErrInvalidForgeKind = apperr.New("invalid_forge_kind")
+
+const (
+	ForgeKindGithub ForgeKind = "github"
+	ForgeKindGitlab ForgeKind = "gitlab"
+	ForgeKindGitea  ForgeKind = "gitea"
+)
+
+const (
+	CommitStatePending CommitState = "pending"
+	CommitStateSuccess CommitState = "success"
+	CommitStateFailure CommitState = "failure"
+)
+
+type (
+	// Holds the vcs configuration of an application.
+	VersionControl struct {
+		url   Url
+		token monad.Maybe[string]
+		forge monad.Maybe[Forge]
+	}
+
+	ForgeKind string
+
+	// Git forge hosting the repository on which commit statuses will be reported.
+	Forge struct {
+		kind  ForgeKind
+		token string
+	}
+
+	CommitState string
+
+	// Status of a deployment to report on a specific commit.
+	CommitStatus struct {
+		Hash        string
+		State       CommitState
+		Context     string // Identify the status on the forge, one per environment
+		Description string
+		TargetUrl   monad.Maybe[string]
+	}
+
+	// Report deployment statuses on commits to the forge configured on a version control.
+	CommitStatusReporter interface {
+		Report(context.Context, VersionControl, CommitStatus) error
+	}
+)
 
 // Instantiates a new version control config object.
 func NewVersionControl(url Url) VersionControl {
@@ -32,5 +76,37 @@ func (c *VersionControl) HasUrl(url Url) {
 	c.url = url
 }
 
+// Report deployment statuses on commits to the given forge.
+func (c *VersionControl) ReportStatusesTo(forge Forge) {
+	c.forge.Set(forge)
+}
+
+// Stop reporting deployment statuses.
+func (c *VersionControl) NoStatusReporting() {
+	c.forge.Unset()
+}
+
 func (c VersionControl) Url() Url                   { return c.url }
 func (c VersionControl) Token() monad.Maybe[string] { return c.token }
+func (c VersionControl) Forge() monad.Maybe[Forge]  { return c.forge }
+
+// Parses the given forge kind.
+func ForgeKindFrom(value string) (ForgeKind, error) {
+	switch kind := ForgeKind(value); kind {
+	case ForgeKindGithub, ForgeKindGitlab, ForgeKindGitea:
+		return kind, nil
+	default:
+		return "", ErrInvalidForgeKind
+	}
+}
+
+// Builds a new forge using the given token to authenticate against its API.
+func NewForge(kind ForgeKind, token string) Forge {
+	return Forge{
+		kind:  kind,
+		token: token,
+	}
+}
+
+func (f Forge) Kind() ForgeKind { return f.kind }
+func (f Forge) Token() string   { return f.token }
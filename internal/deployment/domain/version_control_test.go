@@ -55,4 +55,33 @@ func Test_VersionControl(t *testing.T) {
 		assert.Equal(t, url, conf.Url())
 		assert.False(t, conf.Token().HasValue())
 	})
+	t.Run("could report statuses to a forge", func(t *testing.T) {
+		url, _ := domain.UrlFrom("https://github.com/owner/repo.git")
+
+		conf := domain.NewVersionControl(url)
+		conf.ReportStatusesTo(domain.NewForge(domain.ForgeKindGithub, "forge token"))
+
+		forge := conf.Forge().MustGet()
+		assert.Equal(t, domain.ForgeKindGithub, forge.Kind())
+		assert.Equal(t, "forge token", forge.Token())
+
+		conf.NoStatusReporting()
+
+		assert.False(t, conf.Forge().HasValue())
+	})
+}
+
+func Test_ForgeKind(t *testing.T) {
+	t.Run("should be parsed from a valid value", func(t *testing.T) {
+		kind, err := domain.ForgeKindFrom("gitea")
+
+		assert.Nil(t, err)
+		assert.Equal(t, domain.ForgeKindGitea, kind)
+	})
+
+	t.Run("should returns an error for an unknown value", func(t *testing.T) {
+		_, err := domain.ForgeKindFrom("bitbucket")
+
+		assert.ErrorIs(t, domain.ErrInvalidForgeKind, err)
+	})
 }
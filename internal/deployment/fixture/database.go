@@ -9,15 +9,16 @@ import (
 
 	"github.com/YuukanOO/seelf/cmd/config"
 	auth "github.com/YuukanOO/seelf/internal/auth/domain"
-	authsqlite "github.com/YuukanOO/seelf/internal/auth/infra/sqlite"
+	authsqldb "github.com/YuukanOO/seelf/internal/auth/infra/sqldb"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
-	deployment "github.com/YuukanOO/seelf/internal/deployment/infra/sqlite"
+	deployment "github.com/YuukanOO/seelf/internal/deployment/infra/sqldb"
 	"github.com/YuukanOO/seelf/pkg/bus/spy"
-	scheduler "github.com/YuukanOO/seelf/pkg/bus/sqlite"
+	scheduler "github.com/YuukanOO/seelf/pkg/bus/sqldb"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/must"
 	"github.com/YuukanOO/seelf/pkg/ostools"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
 )
 
 type (
@@ -27,6 +28,9 @@ type (
 		apps        []*domain.App
 		deployments []*domain.Deployment
 		registries  []*domain.Registry
+		channels    []*domain.NotificationChannel
+		schedules   []*domain.Schedule
+		freezes     []*domain.FreezeWindow
 	}
 
 	Context struct {
@@ -37,6 +41,9 @@ type (
 		AppsStore        deployment.AppsStore
 		DeploymentsStore deployment.DeploymentsStore
 		RegistriesStore  deployment.RegistriesStore
+		ChannelsStore    deployment.NotificationChannelsStore
+		SchedulesStore   deployment.SchedulesStore
+		FreezesStore     deployment.FreezeWindowsStore
 	}
 
 	SeedBuilder func(*seed)
@@ -53,7 +60,13 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 		t.Fatal(err)
 	}
 
-	db, err := sqlite.Open(result.Config.ConnectionString(), must.Panic(log.NewLogger()), result.Dispatcher)
+	dsn := result.Config.ConnectionString()
+
+	if result.Config.Dialect() == postgres.Dialect {
+		dsn = postgres.PrepareTestSchema(t, dsn)
+	}
+
+	db, err := sqldb.Open(result.Config.Dialect(), dsn, must.Panic(log.NewLogger()), result.Dispatcher)
 
 	if err != nil {
 		t.Fatal(err)
@@ -66,7 +79,7 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 
 	// FIXME: scheduler migrations are needed because some migrations may queue a job by inserting inside
 	// the scheduled_jobs table. That's a mistake from my side and I should fix it later.
-	if err = db.Migrate(scheduler.Migrations, authsqlite.Migrations, deployment.Migrations); err != nil {
+	if err = db.Migrate(scheduler.Migrations, authsqldb.Migrations, deployment.Migrations); err != nil {
 		t.Fatal(err)
 	}
 
@@ -74,6 +87,9 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 	result.TargetsStore = deployment.NewTargetsStore(db)
 	result.DeploymentsStore = deployment.NewDeploymentsStore(db)
 	result.RegistriesStore = deployment.NewRegistriesStore(db)
+	result.ChannelsStore = deployment.NewNotificationChannelsStore(db)
+	result.SchedulesStore = deployment.NewSchedulesStore(db)
+	result.FreezesStore = deployment.NewFreezeWindowsStore(db, authsqldb.NewUsersStore(db))
 
 	// Seed the database
 	var s seed
@@ -83,7 +99,7 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 	}
 
 	if len(s.users) > 0 {
-		if err := authsqlite.NewUsersStore(db).Write(result.Context, s.users...); err != nil {
+		if err := authsqldb.NewUsersStore(db).Write(result.Context, s.users...); err != nil {
 			t.Fatal(err)
 		}
 		result.Context = auth.WithUserID(result.Context, s.users[0].ID()) // The first created user will be used as the authenticated one
@@ -105,6 +121,18 @@ func PrepareDatabase(t testing.TB, options ...SeedBuilder) *Context {
 		t.Fatal(err)
 	}
 
+	if err := result.ChannelsStore.Write(result.Context, s.channels...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := result.SchedulesStore.Write(result.Context, s.schedules...); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := result.FreezesStore.Write(result.Context, s.freezes...); err != nil {
+		t.Fatal(err)
+	}
+
 	// Reset the dispatcher after seeding
 	result.Dispatcher.Reset()
 
@@ -140,3 +168,21 @@ func WithRegistries(registries ...*domain.Registry) SeedBuilder {
 		s.registries = registries
 	}
 }
+
+func WithNotificationChannels(channels ...*domain.NotificationChannel) SeedBuilder {
+	return func(s *seed) {
+		s.channels = channels
+	}
+}
+
+func WithSchedules(schedules ...*domain.Schedule) SeedBuilder {
+	return func(s *seed) {
+		s.schedules = schedules
+	}
+}
+
+func WithFreezeWindows(windows ...*domain.FreezeWindow) SeedBuilder {
+	return func(s *seed) {
+		s.freezes = windows
+	}
+}
@@ -65,6 +65,7 @@ func ForEnvironment(environment domain.Environment) DeploymentOptionBuilder {
 type (
 	sourceDataOption struct {
 		UseVersionControl bool
+		Hash              string
 	}
 
 	SourceDataOptionBuilder func(*sourceDataOption)
@@ -83,6 +84,7 @@ func SourceData(options ...SourceDataOptionBuilder) domain.SourceData {
 func (sourceDataOption) Kind() string                   { return "test" }
 func (m sourceDataOption) NeedVersionControl() bool     { return m.UseVersionControl }
 func (m sourceDataOption) Value() (driver.Value, error) { return storage.ValueJSON(m) }
+func (m sourceDataOption) Commit() string               { return m.Hash }
 
 func WithVersionControlNeeded() SourceDataOptionBuilder {
 	return func(o *sourceDataOption) {
@@ -90,6 +92,12 @@ func WithVersionControlNeeded() SourceDataOptionBuilder {
 	}
 }
 
+func WithCommit(hash string) SourceDataOptionBuilder {
+	return func(o *sourceDataOption) {
+		o.Hash = hash
+	}
+}
+
 func init() {
 	domain.SourceDataTypes.Register(sourceDataOption{}, func(s string) (domain.SourceData, error) {
 		return storage.UnmarshalJSON[sourceDataOption](s)
@@ -0,0 +1,64 @@
+//go:build !release
+
+package fixture
+
+import (
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/id"
+)
+
+type (
+	notificationChannelOption struct {
+		name        string
+		kind        domain.NotificationChannelKind
+		destination string
+		rules       domain.NotificationRules
+		uid         auth.UserID
+	}
+
+	NotificationChannelOptionBuilder func(*notificationChannelOption)
+)
+
+func NotificationChannel(options ...NotificationChannelOptionBuilder) domain.NotificationChannel {
+	opts := notificationChannelOption{
+		name:        id.New[string](),
+		kind:        domain.NotificationChannelKindWebhook,
+		destination: "http://" + id.New[string]() + ".com/hook",
+		uid:         id.New[auth.UserID](),
+	}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	channel := domain.NewNotificationChannel(opts.name, opts.kind, opts.destination, opts.uid)
+	channel.SubscribeTo(opts.rules)
+
+	return channel
+}
+
+func WithNotificationChannelName(name string) NotificationChannelOptionBuilder {
+	return func(o *notificationChannelOption) {
+		o.name = name
+	}
+}
+
+func WithNotificationChannelDestination(kind domain.NotificationChannelKind, destination string) NotificationChannelOptionBuilder {
+	return func(o *notificationChannelOption) {
+		o.kind = kind
+		o.destination = destination
+	}
+}
+
+func WithNotificationRules(rules ...domain.NotificationRule) NotificationChannelOptionBuilder {
+	return func(o *notificationChannelOption) {
+		o.rules = rules
+	}
+}
+
+func WithNotificationChannelCreatedBy(uid auth.UserID) NotificationChannelOptionBuilder {
+	return func(o *notificationChannelOption) {
+		o.uid = uid
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !release
+
+package fixture
+
+import (
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+type (
+	scheduleOption struct {
+		app         domain.App
+		environment domain.Environment
+		expression  string
+		uid         auth.UserID
+	}
+
+	ScheduleOptionBuilder func(*scheduleOption)
+)
+
+func Schedule(options ...ScheduleOptionBuilder) domain.Schedule {
+	opts := scheduleOption{
+		app:         App(),
+		environment: domain.Production,
+		expression:  "@daily",
+		uid:         id.New[auth.UserID](),
+	}
+
+	for _, o := range options {
+		o(&opts)
+	}
+
+	return must.Panic(opts.app.NewSchedule(
+		opts.environment,
+		must.Panic(domain.ScheduleExpressionFrom(opts.expression)),
+		opts.uid,
+	))
+}
+
+func WithScheduleApp(app domain.App) ScheduleOptionBuilder {
+	return func(o *scheduleOption) {
+		o.app = app
+	}
+}
+
+func WithScheduleEnvironment(env domain.Environment) ScheduleOptionBuilder {
+	return func(o *scheduleOption) {
+		o.environment = env
+	}
+}
+
+func WithScheduleExpression(expr string) ScheduleOptionBuilder {
+	return func(o *scheduleOption) {
+		o.expression = expr
+	}
+}
+
+func WithScheduleCreatedBy(uid auth.UserID) ScheduleOptionBuilder {
+	return func(o *scheduleOption) {
+		o.uid = uid
+	}
+}
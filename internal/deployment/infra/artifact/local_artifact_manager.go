@@ -117,6 +117,25 @@ func (a *localArtifactManager) LogPath(ctx context.Context, depl domain.Deployme
 	)
 }
 
+func (a *localArtifactManager) Timeline(
+	ctx context.Context,
+	depl domain.Deployment,
+) ([]domain.DeploymentPhaseTiming, error) {
+	file, err := os.Open(a.LogPath(ctx, depl))
+
+	if errors.Is(err, os.ErrNotExist) {
+		return []domain.DeploymentPhaseTiming{}, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	return timeline(file)
+}
+
 func (a *localArtifactManager) appPath(appID domain.AppID) string {
 	return filepath.Join(a.appsDirectory, string(appID))
 }
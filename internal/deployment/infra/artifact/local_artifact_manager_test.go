@@ -57,4 +57,42 @@ func Test_LocalArtifactManager(t *testing.T) {
 		_, err = os.ReadDir(ctx.BuildDirectory())
 		assert.True(t, os.IsNotExist(err))
 	})
+
+	t.Run("should returns an empty timeline if the deployment has no log yet", func(t *testing.T) {
+		manager := sut()
+
+		phases, err := manager.Timeline(context.Background(), depl)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, phases)
+	})
+
+	t.Run("should build the deployment timeline from its logs", func(t *testing.T) {
+		manager := sut()
+
+		ctx, err := manager.PrepareBuild(context.Background(), depl)
+		assert.Nil(t, err)
+
+		ctx.Logger().Phase(domain.DeploymentPhaseFetch)
+		ctx.Logger().Stepf("fetching sources")
+		ctx.Logger().Phase(domain.DeploymentPhaseBuild)
+
+		phases, err := manager.Timeline(context.Background(), depl)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 2, phases)
+		assert.Equal(t, domain.DeploymentPhaseFetch, phases[0].Phase)
+		assert.True(t, phases[0].FinishedAt.HasValue())
+		assert.Equal(t, phases[1].StartedAt, phases[0].FinishedAt.MustGet())
+		assert.Equal(t, domain.DeploymentPhaseBuild, phases[1].Phase)
+		assert.False(t, phases[1].FinishedAt.HasValue(), "should still be in progress")
+
+		ctx.Logger().Close()
+
+		phases, err = manager.Timeline(context.Background(), depl)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 2, phases)
+		assert.True(t, phases[1].FinishedAt.HasValue())
+	})
 }
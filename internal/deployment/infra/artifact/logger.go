@@ -1,45 +1,130 @@
 package artifact
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
+	"sync"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 )
 
+// Logger writing timestamped structured records, one JSON object per line.
+// Raw output written to it (by docker for example) is split in lines and recorded
+// with the output level.
+//
+// Multiple goroutines can use the same logger at the same time.
 type stepLogger struct {
-	writer io.WriteCloser
+	mu      sync.Mutex
+	writer  io.WriteCloser
+	encoder *json.Encoder
+	start   time.Time
+	step    int
+	phase   domain.DeploymentPhase
+	pending []byte // Raw output not terminated by a line feed yet
 }
 
 // Instantiates a new step logger to provide a simple way to build a deployment logfile.
 func newLogger(writer io.WriteCloser) domain.DeploymentLogger {
-	return &stepLogger{writer}
+	encoder := json.NewEncoder(writer)
+	encoder.SetEscapeHTML(false)
+
+	return &stepLogger{
+		writer:  writer,
+		encoder: encoder,
+		start:   time.Now(),
+	}
+}
+
+func (l *stepLogger) Phase(phase domain.DeploymentPhase) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.phase = phase
+	l.print(levelPhase, string(phase))
+}
+
+func (l *stepLogger) CurrentPhase() domain.DeploymentPhase {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.phase
 }
 
 func (l *stepLogger) Stepf(format string, args ...any) {
-	l.print("[STEP]", format, args)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.step++
+	l.print(levelStep, fmt.Sprintf(format, args...))
 }
 
 func (l *stepLogger) Warnf(format string, args ...any) {
-	l.print("[WARN]", format, args)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.print(levelWarn, fmt.Sprintf(format, args...))
 }
 
 func (l *stepLogger) Infof(format string, args ...any) {
-	l.print("[INFO]", format, args)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.print(levelInfo, fmt.Sprintf(format, args...))
 }
 
 func (l *stepLogger) Error(err error) {
-	l.print("[ERROR]", err.Error(), nil)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.print(levelError, err.Error())
 }
 
 func (l *stepLogger) Write(p []byte) (n int, err error) {
-	return l.writer.Write(p)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.pending = append(l.pending, p...)
+
+	for {
+		idx := bytes.IndexByte(l.pending, '\n')
+
+		if idx < 0 {
+			break
+		}
+
+		l.print(levelOutput, string(bytes.TrimRight(l.pending[:idx], "\r")))
+		l.pending = l.pending[idx+1:]
+	}
+
+	return len(p), nil
 }
 
 func (l *stepLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.pending) > 0 {
+		l.print(levelOutput, string(bytes.TrimRight(l.pending, "\r")))
+		l.pending = nil
+	}
+
+	l.print(levelEnd, "")
+
 	return l.writer.Close()
 }
 
-func (l *stepLogger) print(prefix string, format string, args []any) {
-	_, _ = l.Write([]byte(prefix + " " + fmt.Sprintf(format, args...) + "\n"))
+func (l *stepLogger) print(level level, message string) {
+	now := time.Now()
+
+	_ = l.encoder.Encode(record{
+		Time:    now.UTC(),
+		Level:   level,
+		Step:    l.step,
+		Phase:   l.phase,
+		Message: message,
+		Elapsed: now.Sub(l.start).Seconds(),
+	})
 }
@@ -0,0 +1,155 @@
+package artifact
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const (
+	levelPhase  level = "phase"  // Beginning of a deployment phase
+	levelStep   level = "step"   // Beginning of a deployment step
+	levelInfo   level = "info"   // Informational message
+	levelWarn   level = "warn"   // Something went wrong but the deployment can continue
+	levelError  level = "error"  // Something went wrong and the deployment may fail
+	levelOutput level = "output" // Raw output written by a third party (docker, git, ...)
+	levelEnd    level = "end"    // The logger has been closed
+
+	renderedTimeFormat = "15:04:05"
+)
+
+type (
+	level string
+
+	// Structured record written on its own line in a deployment log file.
+	record struct {
+		Time    time.Time              `json:"time"`
+		Level   level                  `json:"level"`
+		Step    int                    `json:"step"`
+		Phase   domain.DeploymentPhase `json:"phase,omitempty"`
+		Message string                 `json:"message"`
+		Elapsed float64                `json:"elapsed"` // Seconds elapsed since the logger has been created
+	}
+)
+
+// Render a deployment log line as plain text. Lines which are not structured records
+// (written by previous versions of seelf) are returned as is.
+func RenderLine(line string) string {
+	r, ok := parseRecord(line)
+
+	if !ok {
+		return line
+	}
+
+	prefix := r.Time.Format(renderedTimeFormat) + " "
+
+	switch r.Level {
+	case levelPhase:
+		return prefix + "[PHASE] " + r.Message
+	case levelStep:
+		return prefix + "[STEP] " + r.Message
+	case levelInfo:
+		return prefix + "[INFO] " + r.Message
+	case levelWarn:
+		return prefix + "[WARN] " + r.Message
+	case levelError:
+		return prefix + "[ERROR] " + r.Message
+	case levelEnd:
+		return prefix + "[END] finished in " + time.Duration(r.Elapsed*float64(time.Second)).Round(time.Millisecond).String()
+	default:
+		return prefix + r.Message
+	}
+}
+
+// Wraps the given deployment log reader to render every lines as plain text.
+func NewTextRenderer(r io.Reader) io.ReadCloser {
+	reader, writer := io.Pipe()
+
+	go func() {
+		writer.CloseWithError(render(writer, r))
+	}()
+
+	return reader
+}
+
+func render(w io.Writer, r io.Reader) error {
+	buffered := bufio.NewReader(r)
+
+	for {
+		line, err := buffered.ReadString('\n')
+
+		if line != "" {
+			if _, werr := io.WriteString(w, RenderLine(strings.TrimSuffix(line, "\n"))+"\n"); werr != nil {
+				return werr
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// Build the deployment timeline from records read from the given reader.
+// A phase ends when the next one begins or when the logger has been closed.
+func timeline(r io.Reader) ([]domain.DeploymentPhaseTiming, error) {
+	var (
+		phases   []domain.DeploymentPhaseTiming
+		current  = -1
+		buffered = bufio.NewReader(r)
+	)
+
+	for {
+		line, err := buffered.ReadString('\n')
+
+		if rec, ok := parseRecord(strings.TrimSuffix(line, "\n")); ok {
+			switch rec.Level {
+			case levelPhase:
+				if current >= 0 {
+					phases[current].FinishedAt.Set(rec.Time)
+				}
+
+				phases = append(phases, domain.DeploymentPhaseTiming{
+					Phase:     rec.Phase,
+					StartedAt: rec.Time,
+				})
+				current = len(phases) - 1
+			case levelEnd:
+				if current >= 0 {
+					phases[current].FinishedAt.Set(rec.Time)
+				}
+
+				current = -1
+			}
+		}
+
+		if errors.Is(err, io.EOF) {
+			return phases, nil
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func parseRecord(line string) (r record, ok bool) {
+	if !strings.HasPrefix(line, "{") {
+		return r, false
+	}
+
+	if err := json.Unmarshal([]byte(line), &r); err != nil || r.Level == "" {
+		return r, false
+	}
+
+	return r, true
+}
@@ -0,0 +1,55 @@
+package artifact_test
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/pkg/assert"
+)
+
+func Test_RenderLine(t *testing.T) {
+	t.Run("should render structured records as plain text", func(t *testing.T) {
+		tests := []struct {
+			line     string
+			expected string
+		}{
+			{`{"time":"2024-05-02T08:10:12Z","level":"phase","step":0,"phase":"fetch","message":"fetch","elapsed":0}`, "08:10:12 [PHASE] fetch"},
+			{`{"time":"2024-05-02T08:10:12Z","level":"step","step":1,"phase":"fetch","message":"cloning","elapsed":0.1}`, "08:10:12 [STEP] cloning"},
+			{`{"time":"2024-05-02T08:10:13Z","level":"info","step":1,"message":"some info","elapsed":1}`, "08:10:13 [INFO] some info"},
+			{`{"time":"2024-05-02T08:10:13Z","level":"warn","step":1,"message":"careful","elapsed":1}`, "08:10:13 [WARN] careful"},
+			{`{"time":"2024-05-02T08:10:13Z","level":"error","step":1,"message":"failed","elapsed":1}`, "08:10:13 [ERROR] failed"},
+			{`{"time":"2024-05-02T08:10:14Z","level":"output","step":1,"message":"raw output","elapsed":2}`, "08:10:14 raw output"},
+			{`{"time":"2024-05-02T08:10:15Z","level":"end","step":1,"message":"","elapsed":3.5}`, "08:10:15 [END] finished in 3.5s"},
+		}
+
+		for _, test := range tests {
+			t.Run(test.expected, func(t *testing.T) {
+				assert.Equal(t, test.expected, artifact.RenderLine(test.line))
+			})
+		}
+	})
+
+	t.Run("should keep lines written by previous versions as is", func(t *testing.T) {
+		assert.Equal(t, "[STEP] some legacy line", artifact.RenderLine("[STEP] some legacy line"))
+		assert.Equal(t, "{not json", artifact.RenderLine("{not json"))
+	})
+}
+
+func Test_TextRenderer(t *testing.T) {
+	t.Run("should render every lines of the given reader", func(t *testing.T) {
+		r := artifact.NewTextRenderer(strings.NewReader(`[STEP] legacy line
+{"time":"2024-05-02T08:10:12Z","level":"step","step":1,"message":"cloning","elapsed":0.1}
+{"time":"2024-05-02T08:10:14Z","level":"output","step":1,"message":"raw output","elapsed":2}`))
+		defer r.Close()
+
+		content, err := io.ReadAll(r)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `[STEP] legacy line
+08:10:12 [STEP] cloning
+08:10:14 raw output
+`, string(content))
+	})
+}
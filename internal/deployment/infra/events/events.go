@@ -0,0 +1,73 @@
+// Package events relays deployment related signals to external subscribers, such as
+// clients of the realtime events stream.
+package events
+
+import (
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+)
+
+const (
+	DeploymentStateChanged = "deployment_state_changed"
+	TargetStateChanged     = "target_state_changed"
+	AppEnvChanged          = "app_env_changed"
+)
+
+type (
+	DeploymentStateChangedData struct {
+		AppID            string                  `json:"app_id"`
+		DeploymentNumber int                     `json:"deployment_number"`
+		Environment      string                  `json:"environment"`
+		TargetID         string                  `json:"target_id"`
+		Status           domain.DeploymentStatus `json:"status"`
+		ErrCode          string                  `json:"error_code,omitempty"`
+	}
+
+	TargetStateChangedData struct {
+		TargetID string              `json:"target_id"`
+		Status   domain.TargetStatus `json:"status"`
+		ErrCode  string              `json:"error_code,omitempty"`
+		Version  time.Time           `json:"version"`
+	}
+
+	AppEnvChangedData struct {
+		AppID       string `json:"app_id"`
+		Environment string `json:"environment"`
+		TargetID    string `json:"target_id"`
+	}
+)
+
+// Attach the needed signal handlers to relay deployment events once committed. Only identifiers
+// and states are sent so subscribers should refetch resources if they need more data.
+func Setup(b bus.Bus, outbox bus.Outbox, r *relay.Relay) {
+	relay.Forward(b, outbox, r, DeploymentStateChanged, func(evt domain.DeploymentStateChanged) any {
+		return DeploymentStateChangedData{
+			AppID:            string(evt.ID.AppID()),
+			DeploymentNumber: int(evt.ID.DeploymentNumber()),
+			Environment:      string(evt.Config.Environment()),
+			TargetID:         string(evt.Config.Target()),
+			Status:           evt.State.Status(),
+			ErrCode:          evt.State.ErrCode().Get(""),
+		}
+	})
+
+	relay.Forward(b, outbox, r, TargetStateChanged, func(evt domain.TargetStateChanged) any {
+		return TargetStateChangedData{
+			TargetID: string(evt.ID),
+			Status:   evt.State.Status(),
+			ErrCode:  evt.State.ErrCode().Get(""),
+			Version:  evt.State.Version(),
+		}
+	})
+
+	relay.Forward(b, outbox, r, AppEnvChanged, func(evt domain.AppEnvChanged) any {
+		return AppEnvChangedData{
+			AppID:       string(evt.ID),
+			Environment: string(evt.Environment),
+			TargetID:    string(evt.Config.Target()),
+		}
+	})
+}
@@ -0,0 +1,151 @@
+// Package forge reports deployment statuses on commits using the GitHub, GitLab or
+// Gitea API.
+package forge
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const requestTimeout = 10 * time.Second
+
+var (
+	ErrUnexpectedStatus = errors.New("unexpected_forge_status")
+	ErrInvalidRepoUrl   = errors.New("invalid_forge_repository_url")
+	ErrUnsupportedForge = errors.New("unsupported_forge")
+)
+
+type reporter struct {
+	client *http.Client
+}
+
+// Builds a new reporter calling the appropriate forge API based on the
+// version control configuration.
+func New() domain.CommitStatusReporter {
+	return &reporter{
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+func (r *reporter) Report(ctx context.Context, vcs domain.VersionControl, status domain.CommitStatus) error {
+	forge, isSet := vcs.Forge().TryGet()
+
+	if !isSet {
+		return nil
+	}
+
+	base, repo, err := repository(vcs.Url())
+
+	if err != nil {
+		return err
+	}
+
+	switch forge.Kind() {
+	case domain.ForgeKindGithub:
+		api := base + "/api/v3"
+
+		if strings.EqualFold(vcs.Url().Host(), "github.com") {
+			api = "https://api.github.com"
+		}
+
+		return r.post(ctx, api+"/repos/"+repo+"/statuses/"+status.Hash, "Authorization", "Bearer "+forge.Token(), payload(status))
+	case domain.ForgeKindGitea:
+		return r.post(ctx, base+"/api/v1/repos/"+repo+"/statuses/"+status.Hash, "Authorization", "token "+forge.Token(), payload(status))
+	case domain.ForgeKindGitlab:
+		state := string(status.State)
+
+		if status.State == domain.CommitStateFailure {
+			state = "failed"
+		}
+
+		body := map[string]string{
+			"state":       state,
+			"name":        status.Context,
+			"description": status.Description,
+		}
+
+		if targetUrl, isSet := status.TargetUrl.TryGet(); isSet {
+			body["target_url"] = targetUrl
+		}
+
+		return r.post(ctx, base+"/api/v4/projects/"+url.PathEscape(repo)+"/statuses/"+status.Hash, "PRIVATE-TOKEN", forge.Token(), body)
+	default:
+		return ErrUnsupportedForge
+	}
+}
+
+func (r *reporter) post(ctx context.Context, endpoint, authHeader, authValue string, body any) error {
+	data, err := json.Marshal(body)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(authHeader, authValue)
+
+	resp, err := r.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Payload shared by the GitHub and Gitea APIs.
+func payload(status domain.CommitStatus) map[string]string {
+	body := map[string]string{
+		"state":       string(status.State),
+		"context":     status.Context,
+		"description": status.Description,
+	}
+
+	if targetUrl, isSet := status.TargetUrl.TryGet(); isSet {
+		body["target_url"] = targetUrl
+	}
+
+	return body
+}
+
+// Extract the forge base url and the repository path (owner/name) from a clone url.
+func repository(u domain.Url) (base string, repo string, err error) {
+	parsed, err := url.Parse(u.String())
+
+	if err != nil {
+		return "", "", err
+	}
+
+	repo = strings.TrimSuffix(strings.Trim(parsed.Path, "/"), ".git")
+
+	if !strings.Contains(repo, "/") {
+		return "", "", ErrInvalidRepoUrl
+	}
+
+	return parsed.Scheme + "://" + parsed.Host, repo, nil
+}
@@ -0,0 +1,112 @@
+package forge_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/forge"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+type request struct {
+	path    string
+	headers http.Header
+	body    map[string]string
+}
+
+func Test_Reporter(t *testing.T) {
+
+	arrange := func(tb testing.TB, status int, kind domain.ForgeKind, repo string) (domain.CommitStatusReporter, domain.VersionControl, *[]request) {
+		var requests []request
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			req := request{path: r.URL.EscapedPath(), headers: r.Header}
+			_ = json.NewDecoder(r.Body).Decode(&req.body)
+			requests = append(requests, req)
+			w.WriteHeader(status)
+		}))
+
+		tb.Cleanup(server.Close)
+
+		vcs := domain.NewVersionControl(must.Panic(domain.UrlFrom(server.URL + repo)))
+		vcs.ReportStatusesTo(domain.NewForge(kind, "forge-token"))
+
+		return forge.New(), vcs, &requests
+	}
+
+	status := domain.CommitStatus{
+		Hash:        "abcdef",
+		State:       domain.CommitStateFailure,
+		Context:     "seelf/production",
+		Description: "Deployment #1 failed",
+		TargetUrl:   monad.Value("https://seelf.example.com/apps/an-app/deployments/1"),
+	}
+
+	t.Run("should report the status to a GitHub enterprise instance", func(t *testing.T) {
+		reporter, vcs, requests := arrange(t, http.StatusCreated, domain.ForgeKindGithub, "/owner/repo.git")
+
+		err := reporter.Report(context.Background(), vcs, status)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *requests)
+		assert.Equal(t, "/api/v3/repos/owner/repo/statuses/abcdef", (*requests)[0].path)
+		assert.Equal(t, "Bearer forge-token", (*requests)[0].headers.Get("Authorization"))
+		assert.DeepEqual(t, map[string]string{
+			"state":       "failure",
+			"context":     "seelf/production",
+			"description": "Deployment #1 failed",
+			"target_url":  "https://seelf.example.com/apps/an-app/deployments/1",
+		}, (*requests)[0].body)
+	})
+
+	t.Run("should report the status to a Gitea instance", func(t *testing.T) {
+		reporter, vcs, requests := arrange(t, http.StatusCreated, domain.ForgeKindGitea, "/owner/repo")
+
+		err := reporter.Report(context.Background(), vcs, status)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *requests)
+		assert.Equal(t, "/api/v1/repos/owner/repo/statuses/abcdef", (*requests)[0].path)
+		assert.Equal(t, "token forge-token", (*requests)[0].headers.Get("Authorization"))
+	})
+
+	t.Run("should report the status to a GitLab instance", func(t *testing.T) {
+		reporter, vcs, requests := arrange(t, http.StatusCreated, domain.ForgeKindGitlab, "/group/subgroup/repo.git")
+
+		err := reporter.Report(context.Background(), vcs, status)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *requests)
+		assert.Equal(t, "/api/v4/projects/group%2Fsubgroup%2Frepo/statuses/abcdef", (*requests)[0].path)
+		assert.Equal(t, "forge-token", (*requests)[0].headers.Get("PRIVATE-TOKEN"))
+		assert.DeepEqual(t, map[string]string{
+			"state":       "failed",
+			"name":        "seelf/production",
+			"description": "Deployment #1 failed",
+			"target_url":  "https://seelf.example.com/apps/an-app/deployments/1",
+		}, (*requests)[0].body)
+	})
+
+	t.Run("should returns an error if the forge does not respond with a success status", func(t *testing.T) {
+		reporter, vcs, _ := arrange(t, http.StatusUnauthorized, domain.ForgeKindGitea, "/owner/repo")
+
+		err := reporter.Report(context.Background(), vcs, status)
+
+		assert.ErrorIs(t, forge.ErrUnexpectedStatus, err)
+	})
+
+	t.Run("should returns an error if the repository could not be determined", func(t *testing.T) {
+		reporter, vcs, requests := arrange(t, http.StatusCreated, domain.ForgeKindGitea, "/repo")
+
+		err := reporter.Report(context.Background(), vcs, status)
+
+		assert.ErrorIs(t, forge.ErrInvalidRepoUrl, err)
+		assert.HasLength(t, 0, *requests)
+	})
+}
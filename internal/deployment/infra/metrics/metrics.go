@@ -0,0 +1,167 @@
+// Package metrics exposes deployment related Prometheus metrics.
+package metrics
+
+import (
+	"context"
+	"errors"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var deploymentDurationBuckets = []float64{5, 10, 30, 60, 120, 300, 600, 1200, 1800, 3600}
+
+type (
+	metrics struct {
+		deploymentsDuration *prometheus.HistogramVec
+		targetConfigured    *prometheus.CounterVec
+	}
+
+	// Collector reading the number of deployments per app, environment and status
+	// from the database each time metrics are gathered.
+	deploymentsCollector struct {
+		db          *sqldb.Database
+		deployments *prometheus.Desc
+	}
+
+	deploymentsStats struct {
+		app         string
+		environment string
+		status      domain.DeploymentStatus
+		count       int
+	}
+)
+
+// Register deployment related metrics on the given registerer and attach the needed
+// signal handlers to keep them up to date. Those handlers go through the outbox so only
+// committed changes are observed, by a single instance.
+func Setup(registerer prometheus.Registerer, db *sqldb.Database, b bus.Bus, outbox bus.Outbox) error {
+	m := &metrics{
+		deploymentsDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "seelf_deployment_duration_seconds",
+			Help:    "Duration of finished deployments.",
+			Buckets: deploymentDurationBuckets,
+		}, []string{"app", "environment", "status"}),
+		targetConfigured: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "seelf_target_configurations_total",
+			Help: "Number of target configurations by outcome (ready or failed).",
+		}, []string{"target", "status"}),
+	}
+
+	if err := errors.Join(
+		registerer.Register(m.deploymentsDuration),
+		registerer.Register(m.targetConfigured),
+		registerer.Register(&deploymentsCollector{
+			db: db,
+			deployments: prometheus.NewDesc(
+				"seelf_deployments",
+				"Number of deployments per app, environment and status.",
+				[]string{"app", "environment", "status"}, nil,
+			),
+		}),
+	); err != nil {
+		return err
+	}
+
+	bus.OnAsync(b, outbox, "deployment.metrics.on_deployment_state_changed", m.onDeploymentStateChanged())
+	bus.OnAsync(b, outbox, "deployment.metrics.on_target_state_changed", m.onTargetStateChanged())
+
+	return nil
+}
+
+func (m *metrics) onDeploymentStateChanged() bus.SignalHandler[domain.DeploymentStateChanged] {
+	return func(_ context.Context, evt domain.DeploymentStateChanged) error {
+		startedAt, hasStarted := evt.State.StartedAt().TryGet()
+		finishedAt, hasFinished := evt.State.FinishedAt().TryGet()
+
+		if !hasStarted || !hasFinished {
+			return nil
+		}
+
+		m.deploymentsDuration.
+			WithLabelValues(
+				string(evt.Config.AppName()),
+				string(evt.Config.Environment()),
+				statusLabel(evt.State.Status()),
+			).
+			Observe(finishedAt.Sub(startedAt).Seconds())
+
+		return nil
+	}
+}
+
+func (m *metrics) onTargetStateChanged() bus.SignalHandler[domain.TargetStateChanged] {
+	return func(_ context.Context, evt domain.TargetStateChanged) error {
+		var status string
+
+		switch evt.State.Status() {
+		case domain.TargetStatusReady:
+			status = "ready"
+		case domain.TargetStatusFailed:
+			status = "failed"
+		default:
+			return nil
+		}
+
+		m.targetConfigured.WithLabelValues(string(evt.ID), status).Inc()
+
+		return nil
+	}
+}
+
+func (c *deploymentsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.deployments
+}
+
+func (c *deploymentsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := builder.
+		Query[deploymentsStats](`
+			SELECT
+				config_appname
+				,config_environment
+				,state_status
+				,COUNT(*)
+			FROM deployments
+			GROUP BY config_appname, config_environment, state_status`).
+		All(c.db, context.Background(), deploymentsStatsMapper)
+
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.deployments, err)
+		return
+	}
+
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(c.deployments, prometheus.GaugeValue, float64(s.count),
+			s.app, s.environment, statusLabel(s.status))
+	}
+}
+
+func deploymentsStatsMapper(scanner storage.Scanner) (s deploymentsStats, err error) {
+	err = scanner.Scan(
+		&s.app,
+		&s.environment,
+		&s.status,
+		&s.count,
+	)
+
+	return s, err
+}
+
+func statusLabel(status domain.DeploymentStatus) string {
+	switch status {
+	case domain.DeploymentStatusRunning:
+		return "running"
+	case domain.DeploymentStatusFailed:
+		return "failed"
+	case domain.DeploymentStatusSucceeded:
+		return "succeeded"
+	case domain.DeploymentStatusAwaitingApproval:
+		return "awaiting_approval"
+	default:
+		return "pending"
+	}
+}
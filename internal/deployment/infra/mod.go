@@ -1,48 +1,77 @@
 package infra
 
 import (
-	"context"
-	"errors"
+	"time"
 
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/app/approve_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/cancel_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_app"
 	"github.com/YuukanOO/seelf/internal/deployment/app/cleanup_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/configure_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_notification_channel"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/create_schedule"
 	"github.com/YuukanOO/seelf/internal/deployment/app/create_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_notification_channel"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/delete_schedule"
 	"github.com/YuukanOO/seelf/internal/deployment/app/delete_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/expire_deployment_approval"
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
+	"github.com/YuukanOO/seelf/internal/deployment/app/fail_interrupted_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/fail_pending_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_log"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment_timeline"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_runtime_status"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_service_logs"
+	"github.com/YuukanOO/seelf/internal/deployment/app/notify"
 	"github.com/YuukanOO/seelf/internal/deployment/app/promote"
 	"github.com/YuukanOO/seelf/internal/deployment/app/queue_deployment"
 	"github.com/YuukanOO/seelf/internal/deployment/app/reconfigure_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/redeploy"
+	"github.com/YuukanOO/seelf/internal/deployment/app/reject_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/report_commit_status"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_app_cleanup"
 	"github.com/YuukanOO/seelf/internal/deployment/app/request_target_cleanup"
+	"github.com/YuukanOO/seelf/internal/deployment/app/trigger_schedules"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_notification_channel"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/update_schedule"
 	"github.com/YuukanOO/seelf/internal/deployment/app/update_target"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/artifact"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/forge"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/notifier"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/provider/docker"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/archive"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/git"
 	"github.com/YuukanOO/seelf/internal/deployment/infra/source/raw"
-	deploymentsqlite "github.com/YuukanOO/seelf/internal/deployment/infra/sqlite"
+	deploymentsqldb "github.com/YuukanOO/seelf/internal/deployment/infra/sqldb"
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/YuukanOO/seelf/pkg/monad"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
 )
 
+// How long the runtime status of services is kept before inspecting targets again.
+const runtimeStatusCacheDuration = 10 * time.Second
+
 type Options interface {
 	artifact.LocalOptions
+	notifier.Options
+
+	NotificationsDashboardUrl() monad.Maybe[domain.Url] // Public url of the dashboard used to build links in notifications
+	DeploymentTimeout() time.Duration                   // Default maximum duration of a deployment, zero to disable it
+	ApprovalExpiration() time.Duration                  // Delay after which unapproved deployments are failed, zero to disable it
 }
 
 // Setup the deployment module and register everything needed in the given
@@ -50,15 +79,20 @@ type Options interface {
 func Setup(
 	opts Options,
 	logger log.Logger,
-	db *sqlite.Database,
+	db *sqldb.Database,
 	b bus.Bus,
 	scheduler bus.Scheduler,
+	outbox bus.Outbox,
+	admins auth.AdminsReader,
 ) error {
-	appsStore := deploymentsqlite.NewAppsStore(db)
-	deploymentsStore := deploymentsqlite.NewDeploymentsStore(db)
-	targetsStore := deploymentsqlite.NewTargetsStore(db)
-	registriesStore := deploymentsqlite.NewRegistriesStore(db)
-	deploymentQueryHandler := deploymentsqlite.NewGateway(db)
+	appsStore := deploymentsqldb.NewAppsStore(db)
+	deploymentsStore := deploymentsqldb.NewDeploymentsStore(db)
+	targetsStore := deploymentsqldb.NewTargetsStore(db)
+	registriesStore := deploymentsqldb.NewRegistriesStore(db)
+	notificationChannelsStore := deploymentsqldb.NewNotificationChannelsStore(db)
+	schedulesStore := deploymentsqldb.NewSchedulesStore(db)
+	freezeWindowsStore := deploymentsqldb.NewFreezeWindowsStore(db, admins)
+	deploymentQueryHandler := deploymentsqldb.NewGateway(db)
 
 	artifactManager := artifact.NewLocal(opts, logger)
 
@@ -76,14 +110,21 @@ func Setup(
 	bus.Register(b, expose_seelf_container.Handler(targetsStore, targetsStore, dock))
 	bus.Register(b, create_app.Handler(appsStore, appsStore))
 	bus.Register(b, update_app.Handler(appsStore, appsStore))
-	bus.Register(b, queue_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, sourceFacade))
-	bus.Register(b, deploy.Handler(deploymentsStore, deploymentsStore, artifactManager, sourceFacade, providerFacade, targetsStore, registriesStore))
+	bus.Register(b, queue_deployment.Handler(appsStore, deploymentsStore, deploymentsStore, freezeWindowsStore, sourceFacade))
+	bus.Register(b, deploy.Handler(deploymentsStore, deploymentsStore, artifactManager, sourceFacade, providerFacade, targetsStore, registriesStore, opts.DeploymentTimeout()))
 	bus.Register(b, request_app_cleanup.Handler(appsStore, appsStore))
 	bus.Register(b, delete_app.Handler(appsStore, appsStore, artifactManager))
 	bus.Register(b, cleanup_app.Handler(targetsStore, deploymentsStore, providerFacade))
 	bus.Register(b, get_deployment_log.Handler(deploymentsStore, artifactManager))
-	bus.Register(b, redeploy.Handler(appsStore, deploymentsStore, deploymentsStore))
-	bus.Register(b, promote.Handler(appsStore, deploymentsStore, deploymentsStore))
+	bus.Register(b, get_deployment_timeline.Handler(deploymentsStore, artifactManager))
+	bus.Register(b, get_service_logs.Handler(appsStore, targetsStore, providerFacade))
+	bus.Register(b, get_runtime_status.Handler(targetsStore, providerFacade, runtimeStatusCacheDuration))
+	bus.Register(b, redeploy.Handler(appsStore, deploymentsStore, deploymentsStore, freezeWindowsStore))
+	bus.Register(b, cancel_deployment.Handler(deploymentsStore, deploymentsStore, scheduler))
+	bus.Register(b, approve_deployment.Handler(deploymentsStore, deploymentsStore))
+	bus.Register(b, reject_deployment.Handler(deploymentsStore, deploymentsStore))
+	bus.Register(b, expire_deployment_approval.Handler(deploymentsStore, deploymentsStore))
+	bus.Register(b, promote.Handler(appsStore, deploymentsStore, deploymentsStore, freezeWindowsStore))
 	bus.Register(b, create_target.Handler(targetsStore, targetsStore, providerFacade))
 	bus.Register(b, configure_target.Handler(targetsStore, targetsStore, providerFacade))
 	bus.Register(b, reconfigure_target.Handler(targetsStore, targetsStore))
@@ -94,6 +135,18 @@ func Setup(
 	bus.Register(b, create_registry.Handler(registriesStore, registriesStore))
 	bus.Register(b, update_registry.Handler(registriesStore, registriesStore))
 	bus.Register(b, delete_registry.Handler(registriesStore, registriesStore))
+	bus.Register(b, create_notification_channel.Handler(notificationChannelsStore))
+	bus.Register(b, update_notification_channel.Handler(notificationChannelsStore, notificationChannelsStore))
+	bus.Register(b, delete_notification_channel.Handler(notificationChannelsStore, notificationChannelsStore))
+	bus.Register(b, notify.Handler(notificationChannelsStore, deploymentsStore, targetsStore, notifier.New(opts), opts.NotificationsDashboardUrl()))
+	bus.Register(b, report_commit_status.Handler(appsStore, deploymentsStore, targetsStore, forge.New(), opts.NotificationsDashboardUrl()))
+	bus.Register(b, create_schedule.Handler(appsStore, schedulesStore))
+	bus.Register(b, update_schedule.Handler(schedulesStore, schedulesStore))
+	bus.Register(b, delete_schedule.Handler(schedulesStore, schedulesStore))
+	bus.Register(b, create_freeze_window.Handler(appsStore, freezeWindowsStore))
+	bus.Register(b, delete_freeze_window.Handler(freezeWindowsStore, freezeWindowsStore))
+	bus.Register(b, trigger_schedules.Handler(schedulesStore, schedulesStore, appsStore, deploymentsStore, deploymentsStore, freezeWindowsStore))
+	bus.Register(b, fail_interrupted_deployments.Handler(deploymentsStore, scheduler))
 	bus.Register(b, deploymentQueryHandler.GetAllApps)
 	bus.Register(b, deploymentQueryHandler.GetAppByID)
 	bus.Register(b, deploymentQueryHandler.GetAllDeploymentsByApp)
@@ -102,8 +155,16 @@ func Setup(
 	bus.Register(b, deploymentQueryHandler.GetTargetByID)
 	bus.Register(b, deploymentQueryHandler.GetRegistries)
 	bus.Register(b, deploymentQueryHandler.GetRegistryByID)
+	bus.Register(b, deploymentQueryHandler.GetNotificationChannels)
+	bus.Register(b, deploymentQueryHandler.GetNotificationChannelByID)
+	bus.Register(b, deploymentQueryHandler.GetSchedules)
+	bus.Register(b, deploymentQueryHandler.GetScheduleByID)
+	bus.Register(b, deploymentQueryHandler.GetFreezeWindows)
+	bus.Register(b, deploymentQueryHandler.GetFreezeWindowByID)
 
-	bus.On(b, deploy.OnDeploymentCreatedHandler(scheduler))
+	bus.On(b, deploy.OnDeploymentCreatedHandler(scheduler, targetsStore))
+	bus.On(b, deploy.OnDeploymentApprovedHandler(scheduler, targetsStore))
+	bus.On(b, expire_deployment_approval.OnDeploymentCreatedHandler(scheduler, opts.ApprovalExpiration()))
 	bus.On(b, redeploy.OnAppEnvChangedHandler(appsStore, deploymentsStore, deploymentsStore))
 	bus.On(b, delete_app.OnAppCleanupRequestedHandler(scheduler))
 	bus.On(b, cleanup_app.OnAppEnvChangedHandler(scheduler))
@@ -111,6 +172,7 @@ func Setup(
 	bus.On(b, fail_pending_deployments.OnTargetDeleteRequestedHandler(deploymentsStore))
 	bus.On(b, fail_pending_deployments.OnAppCleanupRequestedHandler(deploymentsStore))
 	bus.On(b, fail_pending_deployments.OnAppEnvChangedHandler(deploymentsStore))
+	bus.On(b, fail_pending_deployments.OnDeploymentCreatedHandler(appsStore, deploymentsStore, deploymentsStore, scheduler))
 	bus.On(b, cleanup_target.OnTargetCleanupRequestedHandler(scheduler))
 	bus.On(b, configure_target.OnTargetCreatedHandler(scheduler))
 	bus.On(b, configure_target.OnTargetStateChangedHandler(scheduler))
@@ -118,13 +180,10 @@ func Setup(
 	bus.On(b, configure_target.OnAppEnvChangedHandler(targetsStore, targetsStore))
 	bus.On(b, configure_target.OnAppCleanupRequestedHandler(targetsStore, targetsStore))
 	bus.On(b, delete_target.OnTargetCleanupRequestedHandler(scheduler))
+	bus.OnAsync(b, outbox, "deployment.notify.on_deployment_state_changed", notify.OnDeploymentStateChangedHandler(notificationChannelsStore, scheduler))
+	bus.OnAsync(b, outbox, "deployment.notify.on_target_state_changed", notify.OnTargetStateChangedHandler(notificationChannelsStore, scheduler))
+	bus.OnAsync(b, outbox, "deployment.report_commit_status.on_deployment_created", report_commit_status.OnDeploymentCreatedHandler(appsStore, scheduler))
+	bus.OnAsync(b, outbox, "deployment.report_commit_status.on_deployment_state_changed", report_commit_status.OnDeploymentStateChangedHandler(appsStore, scheduler))
 
-	if err := db.Migrate(deploymentsqlite.Migrations); err != nil {
-		return err
-	}
-
-	// Fail running deployments in case of a hard reset.
-	return deploymentsStore.FailDeployments(context.Background(), errors.New("server_reset"), domain.FailCriteria{
-		Status: monad.Value(domain.DeploymentStatusRunning),
-	})
+	return db.Migrate(deploymentsqldb.Migrations)
 }
@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Send the given text by email to the given comma separated list of recipients.
+// The first line is used as the subject.
+func (n *notifier) mail(recipients string, text string) error {
+	if n.smtp.Host == "" {
+		return ErrSmtpNotConfigured
+	}
+
+	addresses, err := mail.ParseAddressList(recipients)
+
+	if err != nil {
+		return err
+	}
+
+	to := make([]string, len(addresses))
+
+	for i, address := range addresses {
+		to[i] = address.Address
+	}
+
+	subject, _, _ := strings.Cut(text, "\n")
+
+	var msg strings.Builder
+
+	msg.WriteString("From: " + n.smtp.From + "\r\n")
+	msg.WriteString("To: " + strings.Join(to, ", ") + "\r\n")
+	msg.WriteString("Subject: " + subject + "\r\n")
+	msg.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(text, "\n", "\r\n"))
+
+	var auth smtp.Auth
+
+	if n.smtp.Username != "" {
+		auth = smtp.PlainAuth("", n.smtp.Username, n.smtp.Password, n.smtp.Host)
+	}
+
+	return smtp.SendMail(
+		net.JoinHostPort(n.smtp.Host, strconv.Itoa(n.smtp.Port)),
+		auth,
+		n.smtp.From,
+		to,
+		[]byte(msg.String()),
+	)
+}
@@ -0,0 +1,178 @@
+// Package notifier sends notifications to webhook based services (Slack, Discord,
+// Microsoft Teams or a generic endpoint) and by email.
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+)
+
+const (
+	requestTimeout   = 10 * time.Second
+	discordMaxLength = 2000
+)
+
+var (
+	ErrUnexpectedStatus   = errors.New("unexpected_notification_status")
+	ErrSmtpNotConfigured  = errors.New("smtp_not_configured")
+	ErrUnsupportedChannel = errors.New("unsupported_notification_channel")
+	defaultTemplates      = map[domain.NotificationEvent]string{
+		domain.NotificationEventDeploymentSucceeded: `Deployment #{{ .DeploymentNumber }} of {{ .AppName }} ({{ .Environment }}) succeeded on {{ .TargetName }}
+{{- range .Urls }}
+{{ . }}
+{{- end }}
+{{- if .LogsUrl }}
+Logs: {{ .LogsUrl }}
+{{- end }}`,
+		domain.NotificationEventDeploymentFailed: `Deployment #{{ .DeploymentNumber }} of {{ .AppName }} ({{ .Environment }}) failed on {{ .TargetName }}: {{ .ErrCode }}
+{{- if .LogsUrl }}
+Logs: {{ .LogsUrl }}
+{{- end }}`,
+		domain.NotificationEventTargetFailed: `Configuration of target {{ .TargetName }} failed: {{ .ErrCode }}
+{{- if .LogsUrl }}
+Details: {{ .LogsUrl }}
+{{- end }}`,
+	}
+)
+
+type (
+	// SMTP server used to send email notifications.
+	SmtpOptions struct {
+		Host     string
+		Port     int
+		Username string
+		Password string
+		From     string
+	}
+
+	Options interface {
+		NotificationsSmtp() SmtpOptions
+	}
+
+	notifier struct {
+		client *http.Client
+		smtp   SmtpOptions
+	}
+
+	// Payload sent to generic webhooks.
+	webhookPayload struct {
+		Event            domain.NotificationEvent `json:"event"`
+		OccurredAt       time.Time                `json:"occurred_at"`
+		Text             string                   `json:"text"`
+		AppID            string                   `json:"app_id,omitempty"`
+		AppName          string                   `json:"app_name,omitempty"`
+		Environment      string                   `json:"environment,omitempty"`
+		DeploymentNumber int                      `json:"deployment_number,omitempty"`
+		TargetID         string                   `json:"target_id"`
+		TargetName       string                   `json:"target_name"`
+		ErrCode          string                   `json:"error_code,omitempty"`
+		Urls             []string                 `json:"urls,omitempty"`
+		LogsUrl          string                   `json:"logs_url,omitempty"`
+	}
+)
+
+// Builds a new notifier sending messages through HTTP for webhook based channels and
+// using the configured SMTP server for email ones.
+func New(options Options) domain.Notifier {
+	return &notifier{
+		client: &http.Client{Timeout: requestTimeout},
+		smtp:   options.NotificationsSmtp(),
+	}
+}
+
+func (n *notifier) Notify(ctx context.Context, channel domain.NotificationChannel, msg domain.NotificationMessage) error {
+	text, err := render(channel, msg)
+
+	if err != nil {
+		return err
+	}
+
+	switch channel.Kind() {
+	case domain.NotificationChannelKindSlack, domain.NotificationChannelKindTeams:
+		return n.post(ctx, channel.Destination(), map[string]string{"text": text})
+	case domain.NotificationChannelKindDiscord:
+		if runes := []rune(text); len(runes) > discordMaxLength {
+			text = string(runes[:discordMaxLength])
+		}
+
+		return n.post(ctx, channel.Destination(), map[string]string{"content": text})
+	case domain.NotificationChannelKindWebhook:
+		return n.post(ctx, channel.Destination(), webhookPayload{
+			Event:            msg.Event,
+			OccurredAt:       msg.OccurredAt,
+			Text:             text,
+			AppID:            msg.AppID,
+			AppName:          msg.AppName,
+			Environment:      msg.Environment,
+			DeploymentNumber: msg.DeploymentNumber,
+			TargetID:         msg.TargetID,
+			TargetName:       msg.TargetName,
+			ErrCode:          msg.ErrCode,
+			Urls:             msg.Urls,
+			LogsUrl:          msg.LogsUrl,
+		})
+	case domain.NotificationChannelKindEmail:
+		return n.mail(channel.Destination(), text)
+	default:
+		return ErrUnsupportedChannel
+	}
+}
+
+func (n *notifier) post(ctx context.Context, url string, payload any) error {
+	body, err := json.Marshal(payload)
+
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.client.Do(req)
+
+	if err != nil {
+		return err
+	}
+
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Render the message using the channel template if any or the default one.
+func render(channel domain.NotificationChannel, msg domain.NotificationMessage) (string, error) {
+	tmpl, err := template.New("").Parse(channel.Template().Get(defaultTemplates[msg.Event]))
+
+	if err != nil {
+		return "", err
+	}
+
+	var w strings.Builder
+
+	if err = tmpl.Execute(&w, msg); err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(w.String()), nil
+}
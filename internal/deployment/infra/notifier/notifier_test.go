@@ -0,0 +1,107 @@
+package notifier_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/fixture"
+	"github.com/YuukanOO/seelf/internal/deployment/infra/notifier"
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/monad"
+)
+
+func Test_Notifier(t *testing.T) {
+
+	arrange := func(tb testing.TB, status int) (domain.Notifier, *httptest.Server, *[]map[string]any) {
+		var payloads []map[string]any
+
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var payload map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&payload)
+			payloads = append(payloads, payload)
+			w.WriteHeader(status)
+		}))
+
+		tb.Cleanup(server.Close)
+
+		return notifier.New(options{}), server, &payloads
+	}
+
+	msg := domain.NotificationMessage{
+		Event:            domain.NotificationEventDeploymentSucceeded,
+		AppID:            "an-app",
+		AppName:          "my-app",
+		Environment:      "production",
+		DeploymentNumber: 3,
+		TargetID:         "a-target",
+		TargetName:       "local",
+		Urls:             []string{"http://my-app.localhost"},
+		LogsUrl:          "https://seelf.example.com/apps/an-app/deployments/3",
+	}
+
+	t.Run("should send the default message to a slack channel", func(t *testing.T) {
+		n, server, payloads := arrange(t, http.StatusOK)
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindSlack, server.URL))
+
+		err := n.Notify(context.Background(), channel, msg)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *payloads)
+		assert.Equal(t, `Deployment #3 of my-app (production) succeeded on local
+http://my-app.localhost
+Logs: https://seelf.example.com/apps/an-app/deployments/3`, (*payloads)[0]["text"])
+	})
+
+	t.Run("should use the channel template if any", func(t *testing.T) {
+		n, server, payloads := arrange(t, http.StatusNoContent)
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindDiscord, server.URL))
+		channel.UseTemplate(monad.Value("{{ .AppName }} " + strings.Repeat("a", 3000)))
+
+		err := n.Notify(context.Background(), channel, msg)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *payloads)
+		assert.HasNRunes(t, 2000, (*payloads)[0]["content"].(string))
+		assert.Match(t, "^my-app a+$", (*payloads)[0]["content"].(string))
+	})
+
+	t.Run("should send the full payload to a generic webhook", func(t *testing.T) {
+		n, server, payloads := arrange(t, http.StatusOK)
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindWebhook, server.URL))
+
+		err := n.Notify(context.Background(), channel, msg)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, *payloads)
+		assert.Equal(t, "deployment_succeeded", (*payloads)[0]["event"])
+		assert.Equal(t, "an-app", (*payloads)[0]["app_id"])
+		assert.Equal[any](t, float64(3), (*payloads)[0]["deployment_number"])
+	})
+
+	t.Run("should returns an error if the endpoint does not respond with a success status", func(t *testing.T) {
+		n, server, _ := arrange(t, http.StatusInternalServerError)
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindTeams, server.URL))
+
+		err := n.Notify(context.Background(), channel, msg)
+
+		assert.ErrorIs(t, notifier.ErrUnexpectedStatus, err)
+	})
+
+	t.Run("should returns an error when sending an email without SMTP configured", func(t *testing.T) {
+		n, _, _ := arrange(t, http.StatusOK)
+		channel := fixture.NotificationChannel(fixture.WithNotificationChannelDestination(domain.NotificationChannelKindEmail, "john@doe.com"))
+
+		err := n.Notify(context.Background(), channel, msg)
+
+		assert.ErrorIs(t, notifier.ErrSmtpNotConfigured, err)
+	})
+}
+
+type options struct{}
+
+func (options) NotificationsSmtp() notifier.SmtpOptions { return notifier.SmtpOptions{} }
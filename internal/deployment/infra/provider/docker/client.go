@@ -1,33 +1,50 @@
 package docker
 
 import (
+	"cmp"
 	"context"
 	"io"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/ssh"
+	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/cli/cli/command"
 	clitypes "github.com/docker/cli/cli/config/types"
 	"github.com/docker/cli/cli/flags"
 	"github.com/docker/compose/v2/pkg/api"
 	"github.com/docker/compose/v2/pkg/compose"
+	dockertypes "github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	dclient "github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
+	"github.com/docker/docker/pkg/stdcopy"
 )
 
-// Wraps a docker API client and compose service and expose some utility methods.
-type client struct {
-	cli        command.Cli
-	api        dclient.APIClient
-	compose    api.Service
-	version    string
-	registries []string
-}
+type (
+	// Wraps a docker API client and compose service and expose some utility methods.
+	client struct {
+		cli        command.Cli
+		api        dclient.APIClient
+		compose    api.Service
+		version    string
+		registries []string
+	}
+
+	// Reader which closes every given closers when closed.
+	logsReader struct {
+		io.Reader
+		closers []io.Closer
+	}
+)
 
 func connect(ctx context.Context, out io.Writer, host monad.Maybe[ssh.Host], registries ...domain.Registry) (*client, error) {
 	stream := io.Discard
@@ -178,6 +195,233 @@ func (c *client) RemoveResources(ctx context.Context, criteria filters.Args) err
 	return nil
 }
 
+// Retrieve the logs of the first container matching the given filters. Stdout and
+// stderr are demultiplexed into a single stream when the container has no TTY attached.
+func (c *client) ContainerLogs(ctx context.Context, criteria filters.Args, options container.LogsOptions) (io.ReadCloser, error) {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: criteria,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, apperr.ErrNotFound
+	}
+
+	id := logsContainer(containers).ID
+	info, err := c.api.ContainerInspect(ctx, id)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := c.api.ContainerLogs(ctx, id, options)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if info.Config != nil && info.Config.Tty {
+		return logs, nil
+	}
+
+	reader, writer := io.Pipe()
+
+	go func() {
+		_, err := stdcopy.StdCopy(writer, writer, logs)
+		writer.CloseWithError(err)
+	}()
+
+	return &logsReader{reader, []io.Closer{reader, logs}}, nil
+}
+
+// Several containers may match a service, for example when it is being recreated or
+// scaled, so pick a running one if any, the most recently created otherwise.
+func logsContainer(containers []dockertypes.Container) dockertypes.Container {
+	return slices.MaxFunc(containers, func(a, b dockertypes.Container) int {
+		if aRunning, bRunning := a.State == string(domain.ServiceStateRunning), b.State == string(domain.ServiceStateRunning); aRunning != bRunning {
+			if aRunning {
+				return 1
+			}
+
+			return -1
+		}
+
+		return cmp.Compare(a.Created, b.Created)
+	})
+}
+
+// Inspect every containers matching the given filters and returns their runtime status.
+func (c *client) ContainersStatus(ctx context.Context, criteria filters.Args) ([]domain.ServiceStatus, error) {
+	containers, err := c.api.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: criteria,
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		result  = make([]domain.ServiceStatus, 0, len(containers))
+		digests = make(map[string]monad.Maybe[string]) // Cache image digests since services often share the same image
+	)
+
+	for _, cont := range containers {
+		info, err := c.api.ContainerInspect(ctx, cont.ID)
+
+		if err != nil {
+			// The container may have been removed in the meantime
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+
+			return nil, err
+		}
+
+		status := domain.ServiceStatus{
+			App:          domain.AppID(cont.Labels[AppLabel]),
+			Environment:  domain.Environment(cont.Labels[EnvironmentLabel]),
+			Name:         cont.Labels[api.ServiceLabel],
+			State:        domain.ServiceStateUnknown,
+			RestartCount: info.RestartCount,
+			Image:        cont.Image,
+		}
+
+		if info.Config != nil {
+			status.Image = info.Config.Image
+		}
+
+		if state := info.State; state != nil {
+			if state.Status != "" {
+				status.State = domain.ServiceState(state.Status)
+			}
+
+			if state.Health != nil && state.Health.Status != "" {
+				status.Health.Set(state.Health.Status)
+			}
+
+			if startedAt, err := time.Parse(time.RFC3339Nano, state.StartedAt); err == nil && !startedAt.IsZero() {
+				status.StartedAt.Set(startedAt)
+			}
+		}
+
+		digest, found := digests[info.Image]
+
+		if !found {
+			digest = c.imageDigest(ctx, info.Image)
+			digests[info.Image] = digest
+		}
+
+		status.ImageDigest = digest
+
+		result = append(result, status)
+	}
+
+	return result, nil
+}
+
+// Pull images of the given project which are not built locally and need to be pulled
+// according to their pull policy. It returns the names of services for which images
+// have been pulled.
+func (c *client) PullImages(ctx context.Context, project *types.Project) ([]string, error) {
+	var names []string
+
+	for _, name := range project.ServiceNames() {
+		service := project.Services[name]
+
+		if service.Build != nil {
+			continue
+		}
+
+		switch service.PullPolicy {
+		case types.PullPolicyNever, types.PullPolicyBuild:
+			continue
+		case types.PullPolicyAlways:
+		default:
+			if _, _, err := c.api.ImageInspectWithRaw(ctx, service.Image); err == nil {
+				continue
+			} else if !errdefs.IsNotFound(err) {
+				return nil, err
+			}
+		}
+
+		names = append(names, name)
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	selected, err := project.WithSelectedServices(names, types.IgnoreDependencies)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return names, c.compose.Pull(ctx, selected, api.PullOptions{Quiet: true})
+}
+
+// Build images of the given project which have a build definition. It returns the names
+// of services for which images have been built.
+func (c *client) BuildImages(ctx context.Context, project *types.Project) ([]string, error) {
+	var names []string
+
+	for _, name := range project.ServiceNames() {
+		if project.Services[name].Build != nil {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	return names, c.compose.Build(ctx, project, api.BuildOptions{
+		Quiet:    true,
+		Services: names,
+	})
+}
+
 func (c *client) Close() error {
 	return c.api.Close()
 }
+
+// Retrieve the repository digest of the given image if any, falling back to the
+// local image ID for images which have never been pushed to or pulled from a registry.
+func (c *client) imageDigest(ctx context.Context, id string) (m monad.Maybe[string]) {
+	if id == "" {
+		return m
+	}
+
+	img, _, err := c.api.ImageInspectWithRaw(ctx, id)
+
+	if err != nil || len(img.RepoDigests) == 0 {
+		m.Set(id)
+		return m
+	}
+
+	// Repo digests are in the form <repository>@<digest>
+	digest := img.RepoDigests[0]
+
+	if idx := strings.LastIndex(digest, "@"); idx >= 0 {
+		digest = digest[idx+1:]
+	}
+
+	m.Set(digest)
+
+	return m
+}
+
+func (r *logsReader) Close() (err error) {
+	for _, closer := range r.closers {
+		if cerr := closer.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+
+	return err
+}
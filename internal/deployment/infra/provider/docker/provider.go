@@ -6,7 +6,9 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app/expose_seelf_container"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
@@ -238,6 +240,9 @@ func (d *docker) Deploy(
 	registries []domain.Registry,
 ) (domain.Services, error) {
 	logger := deploymentCtx.Logger()
+
+	logger.Phase(domain.DeploymentPhaseConfigure)
+
 	client, err := d.connect(ctx, logger, target, registries...)
 
 	if err != nil {
@@ -259,13 +264,40 @@ func (d *docker) Deploy(
 		return nil, err
 	}
 
-	logger.Stepf("launching docker compose project (pulling, building and running)")
+	logger.Phase(domain.DeploymentPhasePull)
+	logger.Stepf("pulling missing images")
+
+	pulled, err := client.PullImages(ctx, project)
+
+	if err != nil {
+		logger.Error(err)
+		return nil, ErrComposeFailed
+	}
+
+	if len(pulled) > 0 {
+		logger.Infof("pulled images for services: %s", strings.Join(pulled, ", "))
+	}
+
+	logger.Phase(domain.DeploymentPhaseBuild)
+	logger.Stepf("building images")
+
+	built, err := client.BuildImages(ctx, project)
 
+	if err != nil {
+		logger.Error(err)
+		return nil, ErrComposeFailed
+	}
+
+	if len(built) > 0 {
+		logger.Infof("built images for services: %s", strings.Join(built, ", "))
+	}
+
+	logger.Phase(domain.DeploymentPhaseUp)
+	logger.Stepf("launching docker compose project")
+
+	// Images have already been pulled and built at this point so there's no need to build them again
 	if err = client.compose.Up(ctx, project, api.UpOptions{
 		Create: api.CreateOptions{
-			Build: &api.BuildOptions{
-				Quiet: true,
-			},
 			RemoveOrphans: true,
 		},
 		Start: api.StartOptions{
@@ -286,6 +318,8 @@ func (d *docker) Deploy(
 		}
 	}
 
+	logger.Phase(domain.DeploymentPhasePrune)
+
 	prunedCount, err := client.PruneImages(ctx, filters.NewArgs(
 		filters.Arg("dangling", "true"),
 		filters.Arg("label", AppLabel+"="+string(deployment.ID().AppID())),
@@ -341,6 +375,75 @@ func (d *docker) Cleanup(ctx context.Context, app domain.AppID, target domain.Ta
 	))
 }
 
+func (d *docker) ServiceLogs(
+	ctx context.Context,
+	app domain.AppID,
+	target domain.Target,
+	env domain.Environment,
+	service string,
+	options domain.ServiceLogsOptions,
+) (io.ReadCloser, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	logsOptions := dockercontainer.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     options.Follow,
+	}
+
+	if since, isSet := options.Since.TryGet(); isSet {
+		logsOptions.Since = since.Format(time.RFC3339Nano)
+	}
+
+	if tail, isSet := options.Tail.TryGet(); isSet {
+		logsOptions.Tail = strconv.FormatUint(uint64(tail), 10)
+	}
+
+	logs, err := client.ContainerLogs(ctx, filters.NewArgs(
+		filters.Arg("label", AppLabel+"="+string(app)),
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+		filters.Arg("label", EnvironmentLabel+"="+string(env)),
+		filters.Arg("label", api.ServiceLabel+"="+service),
+	), logsOptions)
+
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	// The client should be closed with the logs reader
+	return &logsReader{logs, []io.Closer{logs, client}}, nil
+}
+
+func (d *docker) RuntimeStatus(ctx context.Context, target domain.Target, criteria domain.RuntimeStatusCriteria) ([]domain.ServiceStatus, error) {
+	client, err := d.connect(ctx, nil, target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	defer client.Close()
+
+	args := filters.NewArgs(
+		filters.Arg("label", TargetLabel+"="+string(target.ID())),
+		filters.Arg("label", AppLabel),
+	)
+
+	if app, isSet := criteria.App.TryGet(); isSet {
+		args.Add("label", AppLabel+"="+string(app))
+	}
+
+	if env, isSet := criteria.Environment.TryGet(); isSet {
+		args.Add("label", EnvironmentLabel+"="+string(env))
+	}
+
+	return client.ContainersStatus(ctx, args)
+}
+
 func (d *docker) tryConnect(ctx context.Context, out io.Writer, host monad.Maybe[ssh.Host], registries ...domain.Registry) (*client, error) {
 	// For tests, bypass the initialization and use the provided one
 	if d.client != nil {
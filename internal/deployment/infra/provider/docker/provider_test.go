@@ -29,6 +29,7 @@ import (
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/client"
+	"github.com/docker/docker/errdefs"
 	"github.com/docker/go-connections/nat"
 )
 
@@ -611,7 +612,12 @@ volumes:
 			services, err := provider.Deploy(context.Background(), deploymentContext, deployment, target, nil)
 
 			assert.Nil(t, err)
+			assert.HasLength(t, 1, mock.pulls)
+			assert.DeepEqual(t, []string{"db", "sidecar"}, mock.pulls[0].ServiceNames())
+			assert.HasLength(t, 1, mock.builds)
+			assert.DeepEqual(t, []string{"app"}, mock.builds[0].Services)
 			assert.HasLength(t, 1, mock.ups)
+			assert.Zero(t, mock.ups[0].options.Create.Build, "images should not be built twice")
 			assert.HasLength(t, 3, services)
 
 			assert.Equal(t, "app", services[0].Name())
@@ -975,6 +981,8 @@ type (
 		containers   map[string]types.ServiceConfig
 		ups          []up
 		downs        []down
+		pulls        []*types.Project
+		builds       []api.BuildOptions
 		pruneFilters filters.Args
 	}
 
@@ -1014,6 +1022,16 @@ func (c *dockerMockService) Up(ctx context.Context, project *types.Project, opti
 	return nil
 }
 
+func (c *dockerMockService) Pull(ctx context.Context, project *types.Project, options api.PullOptions) error {
+	c.pulls = append(c.pulls, project)
+	return nil
+}
+
+func (c *dockerMockService) Build(ctx context.Context, project *types.Project, options api.BuildOptions) error {
+	c.builds = append(c.builds, options)
+	return nil
+}
+
 func (c *dockerMockService) Down(ctx context.Context, projectName string, options api.DownOptions) error {
 	c.downs = append(c.downs, down{
 		projectName: projectName,
@@ -1057,6 +1075,10 @@ func (d *dockerMockCli) ContainerInspect(_ context.Context, containerName string
 	return result, nil
 }
 
+func (d *dockerMockCli) ImageInspectWithRaw(_ context.Context, imageID string) (dockertypes.ImageInspect, []byte, error) {
+	return dockertypes.ImageInspect{}, nil, errdefs.NotFound(errors.New("not found"))
+}
+
 func (d *dockerMockCli) ImagesPrune(_ context.Context, criteria filters.Args) (image.PruneReport, error) {
 	d.parent.pruneFilters = criteria
 	return image.PruneReport{}, nil
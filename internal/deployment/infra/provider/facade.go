@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"io"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 )
@@ -83,6 +84,26 @@ func (f *facade) Cleanup(ctx context.Context, app domain.AppID, target domain.Ta
 	return provider.Cleanup(ctx, app, target, env, strategy)
 }
 
+func (f *facade) ServiceLogs(ctx context.Context, app domain.AppID, target domain.Target, env domain.Environment, service string, options domain.ServiceLogsOptions) (io.ReadCloser, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ServiceLogs(ctx, app, target, env, service, options)
+}
+
+func (f *facade) RuntimeStatus(ctx context.Context, target domain.Target, criteria domain.RuntimeStatusCriteria) ([]domain.ServiceStatus, error) {
+	provider, err := f.providerForTarget(target)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.RuntimeStatus(ctx, target, criteria)
+}
+
 func (f *facade) providerForTarget(target domain.Target) (Provider, error) {
 	config := target.Provider()
 
@@ -17,6 +17,7 @@ type Data struct {
 
 func (p Data) Kind() string                 { return "git" }
 func (p Data) NeedVersionControl() bool     { return true }
+func (p Data) Commit() string               { return p.Hash }
 func (p Data) Value() (driver.Value, error) { return storage.ValueJSON(p) }
 
 func init() {
@@ -1,4 +1,4 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
@@ -8,8 +8,8 @@ import (
 	"github.com/YuukanOO/seelf/pkg/event"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type (
@@ -19,11 +19,11 @@ type (
 	}
 
 	appsStore struct {
-		db *sqlite.Database
+		db *sqldb.Database
 	}
 )
 
-func NewAppsStore(db *sqlite.Database) AppsStore {
+func NewAppsStore(db *sqldb.Database) AppsStore {
 	return &appsStore{db}
 }
 
@@ -135,12 +135,19 @@ func (s *appsStore) GetByID(ctx context.Context, id domain.AppID) (domain.App, e
 			,name
 			,version_control_url
 			,version_control_token
+			,version_control_forge_kind
+			,version_control_forge_token
 			,production_target
 			,production_version
 			,production_vars
+			,production_timeout
+			,production_requires_approval
 			,staging_target
 			,staging_version
 			,staging_vars
+			,staging_timeout
+			,staging_requires_approval
+			,latest_wins
 			,cleanup_requested_at
 			,cleanup_requested_by
 			,created_at
@@ -151,21 +158,25 @@ func (s *appsStore) GetByID(ctx context.Context, id domain.AppID) (domain.App, e
 }
 
 func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
-	return sqlite.WriteAndDispatch(s.db, c, apps, func(ctx context.Context, e event.Event) error {
+	return sqldb.WriteAndDispatch(s.db, c, apps, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.AppCreated:
 			return builder.
 				Insert("apps", builder.Values{
-					"id":                 evt.ID,
-					"name":               evt.Name,
-					"production_target":  evt.Production.Target(),
-					"production_version": evt.Production.Version(),
-					"production_vars":    evt.Production.Vars(),
-					"staging_target":     evt.Staging.Target(),
-					"staging_version":    evt.Staging.Version(),
-					"staging_vars":       evt.Staging.Vars(),
-					"created_at":         evt.Created.At(),
-					"created_by":         evt.Created.By(),
+					"id":                           evt.ID,
+					"name":                         evt.Name,
+					"production_target":            evt.Production.Target(),
+					"production_version":           evt.Production.Version(),
+					"production_vars":              evt.Production.Vars(),
+					"production_timeout":           timeoutSeconds(evt.Production.Timeout()),
+					"production_requires_approval": evt.Production.RequiresApproval(),
+					"staging_target":               evt.Staging.Target(),
+					"staging_version":              evt.Staging.Version(),
+					"staging_vars":                 evt.Staging.Vars(),
+					"staging_timeout":              timeoutSeconds(evt.Staging.Timeout()),
+					"staging_requires_approval":    evt.Staging.RequiresApproval(),
+					"created_at":                   evt.Created.At(),
+					"created_by":                   evt.Created.By(),
 				}).
 				Exec(s.db, ctx)
 		case domain.AppEnvChanged:
@@ -173,25 +184,45 @@ func (s *appsStore) Write(c context.Context, apps ...*domain.App) error {
 			// own code.
 			return builder.
 				Update("apps", builder.Values{
-					string(evt.Environment) + "_target":  evt.Config.Target(),
-					string(evt.Environment) + "_version": evt.Config.Version(),
-					string(evt.Environment) + "_vars":    evt.Config.Vars(),
+					string(evt.Environment) + "_target":            evt.Config.Target(),
+					string(evt.Environment) + "_version":           evt.Config.Version(),
+					string(evt.Environment) + "_vars":              evt.Config.Vars(),
+					string(evt.Environment) + "_timeout":           timeoutSeconds(evt.Config.Timeout()),
+					string(evt.Environment) + "_requires_approval": evt.Config.RequiresApproval(),
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
 		case domain.AppVersionControlConfigured:
+			var forgeKind, forgeToken monad.Maybe[string]
+
+			if forge, isSet := evt.Config.Forge().TryGet(); isSet {
+				forgeKind.Set(string(forge.Kind()))
+				forgeToken.Set(forge.Token())
+			}
+
 			return builder.
 				Update("apps", builder.Values{
-					"version_control_url":   evt.Config.Url(),
-					"version_control_token": evt.Config.Token(),
+					"version_control_url":         evt.Config.Url(),
+					"version_control_token":       evt.Config.Token(),
+					"version_control_forge_kind":  forgeKind,
+					"version_control_forge_token": forgeToken,
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
 		case domain.AppVersionControlRemoved:
 			return builder.
 				Update("apps", builder.Values{
-					"version_control_url":   nil,
-					"version_control_token": nil,
+					"version_control_url":         nil,
+					"version_control_token":       nil,
+					"version_control_forge_kind":  nil,
+					"version_control_forge_token": nil,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.AppLatestWinsChanged:
+			return builder.
+				Update("apps", builder.Values{
+					"latest_wins": evt.Enabled,
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
@@ -1,4 +1,4 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
@@ -7,9 +7,10 @@ import (
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	shared "github.com/YuukanOO/seelf/pkg/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type (
@@ -19,11 +20,11 @@ type (
 	}
 
 	deploymentsStore struct {
-		db *sqlite.Database
+		db *sqldb.Database
 	}
 )
 
-func NewDeploymentsStore(db *sqlite.Database) DeploymentsStore {
+func NewDeploymentsStore(db *sqldb.Database) DeploymentsStore {
 	return &deploymentsStore{db}
 }
 
@@ -38,6 +39,7 @@ func (s *deploymentsStore) GetByID(ctx context.Context, id domain.DeploymentID)
 			,config_environment
 			,config_target
 			,config_vars
+			,config_timeout
 			,state_status
 			,state_errcode
 			,state_services
@@ -47,6 +49,10 @@ func (s *deploymentsStore) GetByID(ctx context.Context, id domain.DeploymentID)
 			,source
 			,requested_at
 			,requested_by
+			,not_before
+			,approved_at
+			,approved_by
+			,freeze_overridden
 		FROM deployments
 		WHERE app_id = ? AND deployment_number = ?`, id.AppID(), id.DeploymentNumber()).
 		One(s.db, ctx, domain.DeploymentFrom)
@@ -63,6 +69,7 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 			,config_environment
 			,config_target
 			,config_vars
+			,config_timeout
 			,state_status
 			,state_errcode
 			,state_services
@@ -72,6 +79,10 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 			,source
 			,requested_at
 			,requested_by
+			,not_before
+			,approved_at
+			,approved_by
+			,freeze_overridden
 		FROM deployments
 		WHERE app_id = ? AND config_environment = ?
 		ORDER BY deployment_number DESC
@@ -79,6 +90,38 @@ func (s *deploymentsStore) GetLastDeployment(ctx context.Context, id domain.AppI
 		One(s.db, ctx, domain.DeploymentFrom)
 }
 
+func (s *deploymentsStore) GetLastSuccessfulDeployment(ctx context.Context, id domain.AppID, env domain.Environment) (domain.Deployment, error) {
+	return builder.
+		Query[domain.Deployment](`
+		SELECT
+			app_id
+			,deployment_number
+			,config_appid
+			,config_appname
+			,config_environment
+			,config_target
+			,config_vars
+			,config_timeout
+			,state_status
+			,state_errcode
+			,state_services
+			,state_started_at
+			,state_finished_at
+			,source_discriminator
+			,source
+			,requested_at
+			,requested_by
+			,not_before
+			,approved_at
+			,approved_by
+			,freeze_overridden
+		FROM deployments
+		WHERE app_id = ? AND config_environment = ? AND state_status = ?
+		ORDER BY deployment_number DESC
+		LIMIT 1`, id, env, domain.DeploymentStatusSucceeded).
+		One(s.db, ctx, domain.DeploymentFrom)
+}
+
 func (s *deploymentsStore) GetNextDeploymentNumber(ctx context.Context, appID domain.AppID) (domain.DeploymentNumber, error) {
 	// FIXME: find a better way, on postgresql, I could have used a seq to increment the sequence to avoid any potential duplication
 	// of a job number but on sqlite, I could not find a way yet.
@@ -93,11 +136,46 @@ func (s *deploymentsStore) GetNextDeploymentNumber(ctx context.Context, appID do
 	return domain.DeploymentNumber(c + 1), nil
 }
 
+func (s *deploymentsStore) GetSupersedable(ctx context.Context, appID domain.AppID, env domain.Environment, before domain.DeploymentNumber) ([]domain.Deployment, error) {
+	return builder.
+		Query[domain.Deployment](`
+		SELECT
+			app_id
+			,deployment_number
+			,config_appid
+			,config_appname
+			,config_environment
+			,config_target
+			,config_vars
+			,config_timeout
+			,state_status
+			,state_errcode
+			,state_services
+			,state_started_at
+			,state_finished_at
+			,source_discriminator
+			,source
+			,requested_at
+			,requested_by
+			,not_before
+			,approved_at
+			,approved_by
+			,freeze_overridden
+		FROM deployments
+		WHERE app_id = ?
+			AND config_environment = ?
+			AND deployment_number < ?
+			AND state_status IN (?, ?)
+			AND not_before IS NULL
+		ORDER BY deployment_number`, appID, env, before, domain.DeploymentStatusPending, domain.DeploymentStatusAwaitingApproval).
+		All(s.db, ctx, domain.DeploymentFrom)
+}
+
 func (s *deploymentsStore) HasRunningOrPendingDeploymentsOnTarget(ctx context.Context, target domain.TargetID) (domain.HasRunningOrPendingDeploymentsOnTarget, error) {
 	r, err := builder.
 		Query[bool](`
-		SELECT EXISTS(SELECT 1 FROM deployments WHERE config_target = ? AND state_status IN (?, ?))`,
-		target, domain.DeploymentStatusRunning, domain.DeploymentStatusPending).
+		SELECT EXISTS(SELECT 1 FROM deployments WHERE config_target = ? AND state_status IN (?, ?, ?))`,
+		target, domain.DeploymentStatusRunning, domain.DeploymentStatusPending, domain.DeploymentStatusAwaitingApproval).
 		Extract(s.db, ctx)
 
 	return domain.HasRunningOrPendingDeploymentsOnTarget(r), err
@@ -115,7 +193,7 @@ func (s *deploymentsStore) HasDeploymentsOnAppTargetEnv(ctx context.Context, app
 				SELECT 1 FROM deployments
 				WHERE 
 					app_id = ? AND config_target = ? AND config_environment = ?
-					AND state_status IN (?, ?)
+					AND state_status IN (?, ?, ?)
 			) AS runningOrPending
 			,EXISTS(
 				SELECT 1 FROM deployments
@@ -123,7 +201,7 @@ func (s *deploymentsStore) HasDeploymentsOnAppTargetEnv(ctx context.Context, app
 					app_id = ? AND config_target = ? AND config_environment = ?
 					AND state_status = ? AND requested_at >= ? AND requested_at <= ?
 			) AS successful`,
-		app, target, env, domain.DeploymentStatusPending, domain.DeploymentStatusRunning,
+		app, target, env, domain.DeploymentStatusPending, domain.DeploymentStatusRunning, domain.DeploymentStatusAwaitingApproval,
 		app, target, env, domain.DeploymentStatusSucceeded, ti.From(), ti.To()).
 		One(s.db, ctx, deploymentsOnAppTargetEnvMapper)
 
@@ -144,14 +222,15 @@ func (s *deploymentsStore) FailDeployments(ctx context.Context, reason error, cr
 		S(
 			builder.MaybeValue(criterias.App, "AND app_id = ?"),
 			builder.MaybeValue(criterias.Target, "AND config_target = ?"),
-			builder.MaybeValue(criterias.Status, "AND state_status = ?"),
+			builder.Array("AND state_status IN", criterias.Status),
 			builder.MaybeValue(criterias.Environment, "AND config_environment = ?"),
+			exceptDeployments(criterias.Except),
 		).
 		Exec(s.db, ctx)
 }
 
 func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deployment) error {
-	return sqlite.WriteAndDispatch(s.db, c, deployments, func(ctx context.Context, e event.Event) error {
+	return sqldb.WriteAndDispatch(s.db, c, deployments, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.DeploymentCreated:
 			return builder.
@@ -163,6 +242,7 @@ func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deplo
 					"config_environment":   evt.Config.Environment(),
 					"config_target":        evt.Config.Target(),
 					"config_vars":          evt.Config.Vars(),
+					"config_timeout":       timeoutSeconds(evt.Config.Timeout()),
 					"state_status":         evt.State.Status(),
 					"state_errcode":        evt.State.ErrCode(),
 					"state_services":       evt.State.Services(),
@@ -172,6 +252,7 @@ func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deplo
 					"source":               evt.Source,
 					"requested_at":         evt.Requested.At(),
 					"requested_by":         evt.Requested.By(),
+					"not_before":           evt.NotBefore,
 				}).
 				Exec(s.db, ctx)
 		case domain.DeploymentStateChanged:
@@ -185,6 +266,21 @@ func (s *deploymentsStore) Write(c context.Context, deployments ...*domain.Deplo
 				}).
 				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
 				Exec(s.db, ctx)
+		case domain.DeploymentApproved:
+			return builder.
+				Update("deployments", builder.Values{
+					"approved_at": evt.Approved.At(),
+					"approved_by": evt.Approved.By(),
+				}).
+				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
+				Exec(s.db, ctx)
+		case domain.DeploymentFreezeOverridden:
+			return builder.
+				Update("deployments", builder.Values{
+					"freeze_overridden": true,
+				}).
+				F("WHERE app_id = ? AND deployment_number = ?", evt.ID.AppID(), evt.ID.DeploymentNumber()).
+				Exec(s.db, ctx)
 		default:
 			return nil
 		}
@@ -201,3 +297,21 @@ func deploymentsOnAppTargetEnvMapper(scanner storage.Scanner) (d deploymentsOnAp
 
 	return d, err
 }
+
+// Timeouts are stored as a number of seconds.
+func timeoutSeconds(timeout monad.Maybe[time.Duration]) (seconds monad.Maybe[int]) {
+	if d, isSet := timeout.TryGet(); isSet {
+		seconds.Set(int(d.Seconds()))
+	}
+
+	return seconds
+}
+
+// Excludes the given deployments from a query.
+func exceptDeployments(ids []domain.DeploymentID) builder.Statement {
+	return func(b builder.Builder) {
+		for _, id := range ids {
+			b.Apply("AND NOT (app_id = ? AND deployment_number = ?)", id.AppID(), id.DeploymentNumber())
+		}
+	}
+}
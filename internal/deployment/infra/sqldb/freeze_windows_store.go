@@ -0,0 +1,116 @@
+package sqldb
+
+import (
+	"context"
+
+	auth "github.com/YuukanOO/seelf/internal/auth/domain"
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+type (
+	FreezeWindowsStore interface {
+		domain.FreezeWindowsReader
+		domain.FreezeWindowsWriter
+	}
+
+	freezeWindowsStore struct {
+		db     *sqldb.Database
+		admins auth.AdminsReader
+	}
+)
+
+func NewFreezeWindowsStore(db *sqldb.Database, admins auth.AdminsReader) FreezeWindowsStore {
+	return &freezeWindowsStore{db, admins}
+}
+
+func (s *freezeWindowsStore) GetByID(ctx context.Context, id domain.FreezeWindowID) (domain.FreezeWindow, error) {
+	return builder.
+		Query[domain.FreezeWindow](`
+		SELECT
+			id
+			,name
+			,app_id
+			,environment
+			,expression
+			,duration
+			,starts_at
+			,ends_at
+			,created_at
+			,created_by
+		FROM freeze_windows
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.FreezeWindowFrom)
+}
+
+func (s *freezeWindowsStore) GetForAppEnv(ctx context.Context, app domain.AppID, env domain.Environment) ([]domain.FreezeWindow, error) {
+	return builder.
+		Query[domain.FreezeWindow](`
+		SELECT
+			id
+			,name
+			,app_id
+			,environment
+			,expression
+			,duration
+			,starts_at
+			,ends_at
+			,created_at
+			,created_by
+		FROM freeze_windows
+		WHERE (app_id IS NULL OR app_id = ?)
+			AND (environment IS NULL OR environment = ?)`, app, env).
+		All(s.db, ctx, domain.FreezeWindowFrom)
+}
+
+func (s *freezeWindowsStore) IsFreezeOverrideAllowed(ctx context.Context, id auth.UserID) (domain.FreezeOverrideAllowed, error) {
+	// Only administrators could override a freeze
+	allowed, err := s.admins.IsAdmin(ctx, id)
+
+	return domain.FreezeOverrideAllowed(allowed), err
+}
+
+func (s *freezeWindowsStore) Write(ctx context.Context, windows ...*domain.FreezeWindow) error {
+	return sqldb.WriteAndDispatch(s.db, ctx, windows, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.FreezeWindowCreated:
+			var (
+				expression monad.Maybe[string]
+				duration   monad.Maybe[int64]
+				startsAt   = monad.Value(evt.Period.From())
+				endsAt     = monad.Value(evt.Period.To())
+			)
+
+			if expr, isSet := evt.Period.Expression().TryGet(); isSet {
+				expression.Set(expr.String())
+				duration.Set(int64(evt.Period.Duration().Seconds()))
+				startsAt.Unset()
+				endsAt.Unset()
+			}
+
+			return builder.
+				Insert("freeze_windows", builder.Values{
+					"id":          evt.ID,
+					"name":        evt.Name,
+					"app_id":      evt.App,
+					"environment": evt.Environment,
+					"expression":  expression,
+					"duration":    duration,
+					"starts_at":   startsAt,
+					"ends_at":     endsAt,
+					"created_at":  evt.Created.At(),
+					"created_by":  evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.FreezeWindowDeleted:
+			return builder.
+				Command("DELETE FROM freeze_windows WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -1,29 +1,41 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
 
 	"github.com/YuukanOO/seelf/internal/deployment/app"
+	"github.com/YuukanOO/seelf/internal/deployment/app/deploy"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_deployments"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_app_detail"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_apps"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_deployment"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_freeze_window"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_freeze_windows"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_notification_channel"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_notification_channels"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_registries"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_registry"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_schedule"
+	"github.com/YuukanOO/seelf/internal/deployment/app/get_schedules"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_target"
 	"github.com/YuukanOO/seelf/internal/deployment/app/get_targets"
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/cron"
 	"github.com/YuukanOO/seelf/pkg/monad"
 	"github.com/YuukanOO/seelf/pkg/storage"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type gateway struct {
-	db *sqlite.Database
+	db *sqldb.Database
 }
 
-func NewGateway(db *sqlite.Database) *gateway {
+func NewGateway(db *sqldb.Database) *gateway {
 	return &gateway{db}
 }
 
@@ -61,14 +73,21 @@ func (s *gateway) GetAppByID(ctx context.Context, cmd get_app_detail.Query) (get
 				,apps.name
 				,apps.version_control_url
 				,apps.version_control_token
+				,apps.version_control_forge_kind
+				,apps.version_control_forge_token
 				,production_target.id
 				,production_target.name
 				,production_target.url
 				,apps.production_vars
+				,apps.production_timeout
+				,apps.production_requires_approval
 				,staging_target.id
 				,staging_target.name
 				,staging_target.url
 				,apps.staging_vars
+				,apps.staging_timeout
+				,apps.staging_requires_approval
+				,apps.latest_wins
 				,apps.cleanup_requested_at
 				,cusers.id
 				,cusers.email
@@ -115,7 +134,7 @@ func (s *gateway) GetAllDeploymentsByApp(ctx context.Context, cmd get_app_deploy
 }
 
 func (s *gateway) GetDeploymentByID(ctx context.Context, cmd get_deployment.Query) (get_deployment.Deployment, error) {
-	return builder.
+	d, err := builder.
 		Query[get_deployment.Deployment](`
 		SELECT
 			deployments.app_id
@@ -136,12 +155,72 @@ func (s *gateway) GetDeploymentByID(ctx context.Context, cmd get_deployment.Quer
 			,deployments.requested_at
 			,users.id
 			,users.email
+			,deployments.approved_at
+			,ausers.id
+			,ausers.email
+			,deployments.freeze_overridden
 			,'' -- only to use the same mapper as the latest deployments
 		FROM deployments
 		INNER JOIN users ON users.id = deployments.requested_by
+		LEFT JOIN users ausers ON ausers.id = deployments.approved_by
 		LEFT JOIN targets ON targets.id = deployments.config_target
 		WHERE deployments.app_id = ? AND deployments.deployment_number = ?`, cmd.AppID, cmd.DeploymentNumber).
 		One(s.db, ctx, deploymentDetailMapper(nil))
+
+	if err != nil || domain.DeploymentStatus(d.State.Status) != domain.DeploymentStatusPending {
+		return d, err
+	}
+
+	// Pending deployments awaiting an approval or already picked by a worker have no pending job
+	job := deploy.Command{AppID: cmd.AppID, DeploymentNumber: cmd.DeploymentNumber}
+	pending, err := builder.
+		Query[get_deployment.PendingJob](`
+		SELECT
+			(SELECT COUNT(p.id) FROM scheduled_jobs p
+				WHERE p.message_name = j.message_name AND p.retrieved = false AND p.done = false AND p.dead = false
+				AND (p.not_before < j.not_before OR (p.not_before = j.not_before AND p.queued_at <= j.queued_at)))
+			,j.not_before
+			,j.attempts
+			,j.errcode
+			,(SELECT COUNT(g.id) FROM scheduled_jobs g
+				WHERE g."group" = j."group" AND g.id <> j.id AND g.retrieved = false AND g.done = false AND g.dead = false
+				AND (g.not_before < j.not_before OR (g.not_before = j.not_before AND g.queued_at < j.queued_at)))
+			,r.id
+			,r.started_at
+			,j.concurrency_key IS NOT NULL AND (SELECT COUNT(c.id) FROM scheduled_jobs c
+				WHERE c.retrieved = true AND c.concurrency_key = j.concurrency_key) >= j.concurrency_limit
+		FROM scheduled_jobs j
+		LEFT JOIN scheduled_jobs r ON r."group" = j."group" AND r.retrieved = true
+		WHERE j.message_name = ? AND j.resource_id = ? AND j.retrieved = false AND j.done = false AND j.dead = false`,
+		job.Name_(), job.ResourceID()).
+		One(s.db, ctx, pendingJobMapper)
+
+	if err != nil {
+		if errors.Is(err, apperr.ErrNotFound) {
+			return d, nil
+		}
+
+		return d, err
+	}
+
+	durations, err := builder.
+		Query[time.Duration](`
+		SELECT
+			state_started_at
+			,state_finished_at
+		FROM deployments
+		WHERE app_id = ? AND state_status = ? AND state_started_at IS NOT NULL AND state_finished_at IS NOT NULL
+		ORDER BY state_finished_at DESC
+		LIMIT 10`, cmd.AppID, domain.DeploymentStatusSucceeded).
+		All(s.db, ctx, deploymentDurationMapper)
+
+	if err != nil {
+		return d, err
+	}
+
+	d.ResolveQueue(pending, durations, time.Now().UTC())
+
+	return d, nil
 }
 
 func (s *gateway) GetAllTargets(ctx context.Context, cmd get_targets.Query) ([]get_target.Target, error) {
@@ -156,6 +235,7 @@ func (s *gateway) GetAllTargets(ctx context.Context, cmd get_targets.Query) ([]g
 			,targets.state_status
 			,targets.state_errcode
 			,targets.state_last_ready_version
+			,targets.max_concurrent_deployments
 			,targets.cleanup_requested_at
 			,cusers.id
 			,cusers.email
@@ -183,6 +263,7 @@ func (s *gateway) GetTargetByID(ctx context.Context, cmd get_target.Query) (get_
 			,targets.state_status
 			,targets.state_errcode
 			,targets.state_last_ready_version
+			,targets.max_concurrent_deployments
 			,targets.cleanup_requested_at
 			,cusers.id
 			,cusers.email
@@ -231,6 +312,140 @@ func (s *gateway) GetRegistryByID(ctx context.Context, cmd get_registry.Query) (
 		One(s.db, ctx, registryMapper)
 }
 
+func (s *gateway) GetNotificationChannels(ctx context.Context, cmd get_notification_channels.Query) ([]get_notification_channel.NotificationChannel, error) {
+	return builder.
+		Query[get_notification_channel.NotificationChannel](`
+		SELECT
+			notification_channels.id
+			,notification_channels.name
+			,notification_channels.kind
+			,notification_channels.destination
+			,notification_channels.template
+			,notification_channels.rules
+			,notification_channels.created_at
+			,users.id
+			,users.email
+		FROM notification_channels
+		INNER JOIN users ON users.id = notification_channels.created_by`).
+		All(s.db, ctx, notificationChannelMapper)
+}
+
+func (s *gateway) GetNotificationChannelByID(ctx context.Context, cmd get_notification_channel.Query) (get_notification_channel.NotificationChannel, error) {
+	return builder.
+		Query[get_notification_channel.NotificationChannel](`
+		SELECT
+			notification_channels.id
+			,notification_channels.name
+			,notification_channels.kind
+			,notification_channels.destination
+			,notification_channels.template
+			,notification_channels.rules
+			,notification_channels.created_at
+			,users.id
+			,users.email
+		FROM notification_channels
+		INNER JOIN users ON users.id = notification_channels.created_by
+		WHERE notification_channels.id = ?`, cmd.ID).
+		One(s.db, ctx, notificationChannelMapper)
+}
+
+func (s *gateway) GetSchedules(ctx context.Context, cmd get_schedules.Query) ([]get_schedule.Schedule, error) {
+	return builder.
+		Select[get_schedule.Schedule](`
+			schedules.id
+			,apps.id
+			,apps.name
+			,schedules.environment
+			,schedules.expression
+			,schedules.next_run_at
+			,schedules.last_run_at
+			,schedules.created_at
+			,users.id
+			,users.email`).
+		F(`
+			FROM schedules
+			INNER JOIN apps ON apps.id = schedules.app_id
+			INNER JOIN users ON users.id = schedules.created_by
+			WHERE TRUE`).
+		S(builder.MaybeValue(cmd.AppID, "AND schedules.app_id = ?")).
+		F("ORDER BY schedules.next_run_at").
+		All(s.db, ctx, scheduleMapper)
+}
+
+func (s *gateway) GetScheduleByID(ctx context.Context, cmd get_schedule.Query) (get_schedule.Schedule, error) {
+	return builder.
+		Query[get_schedule.Schedule](`
+		SELECT
+			schedules.id
+			,apps.id
+			,apps.name
+			,schedules.environment
+			,schedules.expression
+			,schedules.next_run_at
+			,schedules.last_run_at
+			,schedules.created_at
+			,users.id
+			,users.email
+		FROM schedules
+		INNER JOIN apps ON apps.id = schedules.app_id
+		INNER JOIN users ON users.id = schedules.created_by
+		WHERE schedules.id = ?`, cmd.ID).
+		One(s.db, ctx, scheduleMapper)
+}
+
+func (s *gateway) GetFreezeWindows(ctx context.Context, cmd get_freeze_windows.Query) ([]get_freeze_window.FreezeWindow, error) {
+	return builder.
+		Select[get_freeze_window.FreezeWindow](`
+			freeze_windows.id
+			,freeze_windows.name
+			,apps.id
+			,apps.name
+			,freeze_windows.environment
+			,freeze_windows.expression
+			,freeze_windows.duration
+			,freeze_windows.starts_at
+			,freeze_windows.ends_at
+			,freeze_windows.created_at
+			,users.id
+			,users.email`).
+		F(`
+			FROM freeze_windows
+			LEFT JOIN apps ON apps.id = freeze_windows.app_id
+			INNER JOIN users ON users.id = freeze_windows.created_by
+			WHERE TRUE`).
+		S(builder.MaybeValue(cmd.AppID, "AND (freeze_windows.app_id IS NULL OR freeze_windows.app_id = ?)")).
+		F("ORDER BY freeze_windows.created_at").
+		All(s.db, ctx, freezeWindowMapper)
+}
+
+func (s *gateway) GetFreezeWindowByID(ctx context.Context, cmd get_freeze_window.Query) (get_freeze_window.FreezeWindow, error) {
+	return builder.
+		Query[get_freeze_window.FreezeWindow](`
+		SELECT
+			freeze_windows.id
+			,freeze_windows.name
+			,apps.id
+			,apps.name
+			,freeze_windows.environment
+			,freeze_windows.expression
+			,freeze_windows.duration
+			,freeze_windows.starts_at
+			,freeze_windows.ends_at
+			,freeze_windows.created_at
+			,users.id
+			,users.email
+		FROM freeze_windows
+		LEFT JOIN apps ON apps.id = freeze_windows.app_id
+		INNER JOIN users ON users.id = freeze_windows.created_by
+		WHERE freeze_windows.id = ?`, cmd.ID).
+		One(s.db, ctx, freezeWindowMapper)
+}
+
+// Only keep the latest deployment of each app environment.
+const latestDeploymentsFilter = `AND deployments.deployment_number = (
+	SELECT MAX(latest.deployment_number) FROM deployments latest
+	WHERE latest.app_id = deployments.app_id AND latest.config_environment = deployments.config_environment)`
+
 var getDeploymentDataloader = builder.NewDataloader(
 	func(a get_apps.App) string { return a.ID },
 	func(e builder.Executor, ctx context.Context, kr storage.KeyedResult[get_apps.App]) error {
@@ -253,12 +468,11 @@ var getDeploymentDataloader = builder.NewDataloader(
 				,deployments.requested_at
 				,users.id
 				,users.email
-				,MAX(requested_at) AS max_requested_at
 			FROM deployments
 			INNER JOIN users ON users.id = deployments.requested_by
 			LEFT JOIN targets ON targets.id = deployments.config_target`).
 			S(builder.Array("WHERE deployments.app_id IN", kr.Keys())).
-			F("GROUP BY deployments.app_id, deployments.config_environment").
+			F(latestDeploymentsFilter).
 			All(e, ctx, deploymentMapper(kr))
 
 		return err
@@ -288,12 +502,16 @@ var getDeploymentDetailDataloader = builder.NewDataloader(
 				,deployments.requested_at
 				,users.id
 				,users.email
-				,MAX(requested_at) AS max_requested_at
+				,deployments.approved_at
+				,ausers.id
+				,ausers.email
+				,deployments.freeze_overridden
 			FROM deployments
 			INNER JOIN users ON users.id = deployments.requested_by
+			LEFT JOIN users ausers ON ausers.id = deployments.approved_by
 			LEFT JOIN targets ON targets.id = deployments.config_target`).
 			S(builder.Array("WHERE deployments.app_id IN", kr.Keys())).
-			F("GROUP BY deployments.app_id, deployments.config_environment").
+			F(latestDeploymentsFilter).
 			All(e, ctx, deploymentDetailMapper(kr))
 
 		return err
@@ -338,6 +556,8 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 	var (
 		url                     monad.Maybe[string]
 		token                   monad.Maybe[storage.SecretString]
+		forgeKind               monad.Maybe[string]
+		forgeToken              monad.Maybe[storage.SecretString]
 		cleanupRequestedById    monad.Maybe[string]
 		cleanupRequestedByEmail monad.Maybe[string]
 	)
@@ -347,14 +567,21 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 		&a.Name,
 		&url,
 		&token,
+		&forgeKind,
+		&forgeToken,
 		&a.Production.Target.ID,
 		&a.Production.Target.Name,
 		&a.Production.Target.Url,
 		&a.Production.Vars,
+		&a.Production.Timeout,
+		&a.Production.RequiresApproval,
 		&a.Staging.Target.ID,
 		&a.Staging.Target.Name,
 		&a.Staging.Target.Url,
 		&a.Staging.Vars,
+		&a.Staging.Timeout,
+		&a.Staging.RequiresApproval,
+		&a.LatestWins,
 		&a.CleanupRequestedAt,
 		&cleanupRequestedById,
 		&cleanupRequestedByEmail,
@@ -364,10 +591,19 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 	)
 
 	if u, isSet := url.TryGet(); isSet {
-		a.VersionControl.Set(get_app_detail.VersionControl{
+		vcs := get_app_detail.VersionControl{
 			Url:   u,
 			Token: token,
-		})
+		}
+
+		if kind, isSet := forgeKind.TryGet(); isSet {
+			vcs.Forge.Set(get_app_detail.Forge{
+				Kind:  kind,
+				Token: forgeToken.Get(""),
+			})
+		}
+
+		a.VersionControl.Set(vcs)
 	}
 
 	if id, isSet := cleanupRequestedById.TryGet(); isSet {
@@ -383,9 +619,8 @@ func appDetailDataMapper(s storage.Scanner) (a get_app_detail.App, err error) {
 func deploymentMapper(kr storage.KeyedResult[get_apps.App]) storage.Mapper[get_app_deployments.Deployment] {
 	return func(scanner storage.Scanner) (d get_app_deployments.Deployment, err error) {
 		var (
-			maxRequestedAt string
-			sourceData     string
-			targetStatus   *uint8
+			sourceData   string
+			targetStatus *uint8
 		)
 
 		err = scanner.Scan(
@@ -405,7 +640,6 @@ func deploymentMapper(kr storage.KeyedResult[get_apps.App]) storage.Mapper[get_a
 			&d.RequestedAt,
 			&d.RequestedBy.ID,
 			&d.RequestedBy.Email,
-			&maxRequestedAt,
 		)
 
 		if err != nil {
@@ -438,9 +672,10 @@ func deploymentMapper(kr storage.KeyedResult[get_apps.App]) storage.Mapper[get_a
 func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.Mapper[get_deployment.Deployment] {
 	return func(scanner storage.Scanner) (d get_deployment.Deployment, err error) {
 		var (
-			maxRequestedAt string
-			sourceData     string
-			targetStatus   *uint8
+			sourceData      string
+			targetStatus    *uint8
+			approvedByID    monad.Maybe[string]
+			approvedByEmail monad.Maybe[string]
 		)
 
 		err = scanner.Scan(
@@ -462,13 +697,23 @@ func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.
 			&d.RequestedAt,
 			&d.RequestedBy.ID,
 			&d.RequestedBy.Email,
-			&maxRequestedAt,
+			&d.ApprovedAt,
+			&approvedByID,
+			&approvedByEmail,
+			&d.FreezeOverridden,
 		)
 
 		if err != nil {
 			return d, err
 		}
 
+		if id, isSet := approvedByID.TryGet(); isSet {
+			d.ApprovedBy.Set(app.UserSummary{
+				ID:    id,
+				Email: approvedByEmail.MustGet(),
+			})
+		}
+
 		// Can't scan directly into a monad.Maybe or it will fail with a conversion error between int64/uint8
 		if targetStatus != nil {
 			d.Target.Status.Set(*targetStatus)
@@ -494,11 +739,47 @@ func deploymentDetailMapper(kr storage.KeyedResult[get_app_detail.App]) storage.
 	}
 }
 
+func pendingJobMapper(scanner storage.Scanner) (j get_deployment.PendingJob, err error) {
+	var (
+		runningID        monad.Maybe[string]
+		runningStartedAt monad.Maybe[time.Time]
+	)
+
+	err = scanner.Scan(
+		&j.Position,
+		&j.NotBefore,
+		&j.Attempts,
+		&j.ErrCode,
+		&j.Ahead,
+		&runningID,
+		&runningStartedAt,
+		&j.ConcurrencyLimit,
+	)
+
+	// The running job may have been retrieved without being marked as started yet
+	if runningID.HasValue() {
+		j.RunningSince.Set(runningStartedAt.Get(time.Now().UTC()))
+	}
+
+	return j, err
+}
+
+func deploymentDurationMapper(scanner storage.Scanner) (time.Duration, error) {
+	var startedAt, finishedAt time.Time
+
+	if err := scanner.Scan(&startedAt, &finishedAt); err != nil {
+		return 0, err
+	}
+
+	return finishedAt.Sub(startedAt), nil
+}
+
 func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 	var (
 		providerData            string
 		cleanupRequestedById    monad.Maybe[string]
 		cleanupRequestedByEmail monad.Maybe[string]
+		maxDeployments          monad.Maybe[int64]
 	)
 
 	err = scanner.Scan(
@@ -510,6 +791,7 @@ func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 		&t.State.Status,
 		&t.State.ErrCode,
 		&t.State.LastReadyVersion,
+		&maxDeployments,
 		&t.CleanupRequestedAt,
 		&cleanupRequestedById,
 		&cleanupRequestedByEmail,
@@ -522,6 +804,10 @@ func targetMapper(scanner storage.Scanner) (t get_target.Target, err error) {
 		return t, err
 	}
 
+	if max, isSet := maxDeployments.TryGet(); isSet {
+		t.MaxConcurrentDeployments.Set(int(max))
+	}
+
 	if id, isSet := cleanupRequestedById.TryGet(); isSet {
 		t.CleanupRequestedBy.Set(app.UserSummary{
 			ID:    id,
@@ -564,3 +850,113 @@ func registryMapper(scanner storage.Scanner) (r get_registry.Registry, err error
 
 	return r, err
 }
+
+func notificationChannelMapper(scanner storage.Scanner) (c get_notification_channel.NotificationChannel, err error) {
+	err = scanner.Scan(
+		&c.ID,
+		&c.Name,
+		&c.Kind,
+		&c.Destination,
+		&c.Template,
+		&c.Rules,
+		&c.CreatedAt,
+		&c.CreatedBy.ID,
+		&c.CreatedBy.Email,
+	)
+
+	if err != nil {
+		return c, err
+	}
+
+	// Webhook urls usually embed a secret token so only the root part is exposed
+	if domain.NotificationChannelKind(c.Kind) != domain.NotificationChannelKindEmail {
+		if url, urlErr := domain.UrlFrom(c.Destination); urlErr == nil {
+			root := url.Root().WithoutUser().String()
+			c.Destination = root + strings.Repeat("*", max(len(c.Destination)-len(root), 0))
+		}
+	}
+
+	return c, err
+}
+
+func scheduleMapper(scanner storage.Scanner) (sc get_schedule.Schedule, err error) {
+	err = scanner.Scan(
+		&sc.ID,
+		&sc.App.ID,
+		&sc.App.Name,
+		&sc.Environment,
+		&sc.Expression,
+		&sc.NextRunAt,
+		&sc.LastRunAt,
+		&sc.CreatedAt,
+		&sc.CreatedBy.ID,
+		&sc.CreatedBy.Email,
+	)
+
+	if err != nil {
+		return sc, err
+	}
+
+	sc.UpcomingRuns = []time.Time{}
+
+	if expr, exprErr := cron.Parse(sc.Expression); exprErr == nil {
+		sc.UpcomingRuns = append(sc.UpcomingRuns, sc.NextRunAt)
+		sc.UpcomingRuns = append(sc.UpcomingRuns, expr.NextN(sc.NextRunAt, get_schedule.UpcomingRunsCount-1)...)
+	}
+
+	return sc, err
+}
+
+func freezeWindowMapper(scanner storage.Scanner) (w get_freeze_window.FreezeWindow, err error) {
+	var (
+		appID    monad.Maybe[string]
+		appName  monad.Maybe[string]
+		duration monad.Maybe[int64]
+	)
+
+	err = scanner.Scan(
+		&w.ID,
+		&w.Name,
+		&appID,
+		&appName,
+		&w.Environment,
+		&w.Expression,
+		&duration,
+		&w.StartsAt,
+		&w.EndsAt,
+		&w.CreatedAt,
+		&w.CreatedBy.ID,
+		&w.CreatedBy.Email,
+	)
+
+	if err != nil {
+		return w, err
+	}
+
+	if id, isSet := appID.TryGet(); isSet {
+		w.App.Set(get_freeze_window.AppSummary{
+			ID:   id,
+			Name: appName.MustGet(),
+		})
+	}
+
+	var (
+		period    domain.FreezePeriod
+		periodErr error
+	)
+
+	if raw, isSet := w.Expression.TryGet(); isSet {
+		seconds := duration.Get(0)
+		w.Duration.Set(int(seconds))
+
+		if expr, exprErr := cron.Parse(raw); exprErr == nil {
+			period, periodErr = domain.RecurringFreezePeriod(expr, time.Duration(seconds)*time.Second)
+		}
+	} else {
+		period, periodErr = domain.FixedFreezePeriod(w.StartsAt.Get(time.Time{}), w.EndsAt.Get(time.Time{}))
+	}
+
+	w.Active = periodErr == nil && period.Contains(time.Now())
+
+	return w, err
+}
@@ -0,0 +1,159 @@
+-- Starts where the SQLite migrations ended up, so it creates the whole schema at once.
+CREATE TABLE targets (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,url TEXT NULL
+    ,provider_kind TEXT NOT NULL
+    ,provider_fingerprint TEXT NOT NULL
+    ,provider TEXT NOT NULL
+    ,state_status INTEGER NOT NULL
+    ,state_version TIMESTAMP NOT NULL
+    ,state_errcode TEXT NULL
+    ,state_last_ready_version TIMESTAMP NULL
+    ,cleanup_requested_at TIMESTAMP NULL
+    ,cleanup_requested_by TEXT NULL
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,entrypoints TEXT NOT NULL DEFAULT '{}'
+    ,max_concurrent_deployments INTEGER NULL
+    ,CONSTRAINT pk_targets PRIMARY KEY(id)
+    ,CONSTRAINT fk_targets_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+    ,CONSTRAINT unique_targets_url UNIQUE(url) -- unique url among all targets
+    ,CONSTRAINT unique_targets_provider_fingerprint UNIQUE(provider_fingerprint) -- unique provider fingerprint
+);
+
+CREATE TABLE apps (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,version_control_url TEXT NULL
+    ,version_control_token TEXT NULL
+    ,version_control_forge_kind TEXT NULL
+    ,version_control_forge_token TEXT NULL
+    ,production_target TEXT NOT NULL
+    ,production_version TIMESTAMP NOT NULL
+    ,production_vars TEXT NULL
+    ,production_timeout INTEGER NULL
+    ,production_requires_approval BOOLEAN NOT NULL DEFAULT false
+    ,staging_target TEXT NOT NULL
+    ,staging_version TIMESTAMP NOT NULL
+    ,staging_vars TEXT NULL
+    ,staging_timeout INTEGER NULL
+    ,staging_requires_approval BOOLEAN NOT NULL DEFAULT false
+    ,latest_wins BOOLEAN NOT NULL DEFAULT false
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,cleanup_requested_at TIMESTAMP NULL
+    ,cleanup_requested_by TEXT NULL
+    ,CONSTRAINT pk_apps PRIMARY KEY(id)
+    ,CONSTRAINT unique_apps_name_production_target UNIQUE(name, production_target)
+    ,CONSTRAINT unique_apps_name_staging_target UNIQUE(name, staging_target)
+    ,CONSTRAINT fk_apps_production_target FOREIGN KEY(production_target) REFERENCES targets(id) ON DELETE RESTRICT
+    ,CONSTRAINT fk_apps_staging_target FOREIGN KEY(staging_target) REFERENCES targets(id) ON DELETE RESTRICT
+    ,CONSTRAINT fk_apps_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_apps_cleanup_requested_by FOREIGN KEY(cleanup_requested_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE deployments (
+    app_id TEXT NOT NULL
+    ,deployment_number INTEGER NOT NULL
+    ,config_appid TEXT NOT NULL
+    ,config_appname TEXT NOT NULL
+    ,config_environment TEXT NOT NULL
+    ,config_target TEXT NOT NULL -- No FK on config_target because we don't want to deal with a target deletion.
+    ,config_vars TEXT NULL
+    ,config_timeout INTEGER NULL
+    ,state_status INTEGER NOT NULL
+    ,state_errcode TEXT NULL
+    ,state_services TEXT NULL
+    ,state_started_at TIMESTAMP NULL
+    ,state_finished_at TIMESTAMP NULL
+    ,source_discriminator TEXT NOT NULL
+    ,source TEXT NOT NULL
+    ,requested_at TIMESTAMP NOT NULL
+    ,requested_by TEXT NOT NULL
+    ,not_before TIMESTAMP NULL
+    ,approved_at TIMESTAMP NULL
+    ,approved_by TEXT NULL
+    ,freeze_overridden BOOLEAN NOT NULL DEFAULT false
+    ,CONSTRAINT pk_deployments PRIMARY KEY(app_id, deployment_number)
+    ,CONSTRAINT fk_deployments_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_deployments_requested_by FOREIGN KEY(requested_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_deployments_target ON deployments(config_target);
+CREATE INDEX idx_deployments_state_status ON deployments(state_status);
+
+-- Remove pending jobs of a deployment when it fails.
+CREATE FUNCTION on_deployment_failed_cleanup_jobs() RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM scheduled_jobs
+    WHERE
+        resource_id = NEW.app_id || '-' || NEW.deployment_number
+        AND retrieved = false;
+
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER on_deployment_failed_cleanup_jobs AFTER UPDATE ON deployments
+    FOR EACH ROW
+    WHEN (OLD.state_status != NEW.state_status AND NEW.state_status = 2) -- Only when the deployment goes to the failed state
+    EXECUTE FUNCTION on_deployment_failed_cleanup_jobs();
+
+CREATE TABLE registries (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,url TEXT NOT NULL
+    ,credentials_username TEXT NULL
+    ,credentials_password TEXT NULL
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_registries PRIMARY KEY(id)
+    ,CONSTRAINT unique_registries_url UNIQUE(url)
+    ,CONSTRAINT fk_registries_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE notification_channels (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,kind TEXT NOT NULL
+    ,destination TEXT NOT NULL
+    ,template TEXT NULL
+    ,rules TEXT NOT NULL DEFAULT '[]'
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_notification_channels PRIMARY KEY(id)
+    ,CONSTRAINT fk_notification_channels_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE schedules (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,expression TEXT NOT NULL
+    ,next_run_at TIMESTAMP NOT NULL
+    ,last_run_at TIMESTAMP NULL
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_schedules PRIMARY KEY(id)
+    ,CONSTRAINT fk_schedules_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_schedules_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_schedules_next_run_at ON schedules(next_run_at);
+
+CREATE TABLE freeze_windows (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,app_id TEXT NULL
+    ,environment TEXT NULL
+    ,expression TEXT NULL
+    ,duration INTEGER NULL
+    ,starts_at TIMESTAMP NULL
+    ,ends_at TIMESTAMP NULL
+    ,created_at TIMESTAMP NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_freeze_windows PRIMARY KEY(id)
+    ,CONSTRAINT fk_freeze_windows_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_freeze_windows_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
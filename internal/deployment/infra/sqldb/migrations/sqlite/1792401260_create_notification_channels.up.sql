@@ -0,0 +1,12 @@
+CREATE TABLE notification_channels (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,kind TEXT NOT NULL
+    ,destination TEXT NOT NULL
+    ,template TEXT NULL
+    ,rules TEXT NOT NULL DEFAULT '[]'
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_notification_channels PRIMARY KEY(id)
+    ,CONSTRAINT fk_notification_channels_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+ALTER TABLE apps ADD version_control_forge_kind TEXT NULL;
+ALTER TABLE apps ADD version_control_forge_token TEXT NULL;
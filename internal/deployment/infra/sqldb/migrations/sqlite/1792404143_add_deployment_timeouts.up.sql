@@ -0,0 +1,3 @@
+ALTER TABLE apps ADD production_timeout INTEGER NULL;
+ALTER TABLE apps ADD staging_timeout INTEGER NULL;
+ALTER TABLE deployments ADD config_timeout INTEGER NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE deployments ADD not_before DATETIME NULL;
+
+CREATE TABLE schedules (
+    id TEXT NOT NULL
+    ,app_id TEXT NOT NULL
+    ,environment TEXT NOT NULL
+    ,expression TEXT NOT NULL
+    ,next_run_at DATETIME NOT NULL
+    ,last_run_at DATETIME NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_schedules PRIMARY KEY(id)
+    ,CONSTRAINT fk_schedules_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_schedules_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_schedules_next_run_at ON schedules(next_run_at);
@@ -0,0 +1 @@
+ALTER TABLE apps ADD latest_wins BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,4 @@
+ALTER TABLE apps ADD production_requires_approval BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE apps ADD staging_requires_approval BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE deployments ADD approved_at DATETIME NULL;
+ALTER TABLE deployments ADD approved_by TEXT NULL;
@@ -0,0 +1,17 @@
+ALTER TABLE deployments ADD freeze_overridden BOOLEAN NOT NULL DEFAULT false;
+
+CREATE TABLE freeze_windows (
+    id TEXT NOT NULL
+    ,name TEXT NOT NULL
+    ,app_id TEXT NULL
+    ,environment TEXT NULL
+    ,expression TEXT NULL
+    ,duration INTEGER NULL
+    ,starts_at DATETIME NULL
+    ,ends_at DATETIME NULL
+    ,created_at DATETIME NOT NULL
+    ,created_by TEXT NOT NULL
+    ,CONSTRAINT pk_freeze_windows PRIMARY KEY(id)
+    ,CONSTRAINT fk_freeze_windows_app_id FOREIGN KEY(app_id) REFERENCES apps(id) ON DELETE CASCADE
+    ,CONSTRAINT fk_freeze_windows_created_by FOREIGN KEY(created_by) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1 @@
+ALTER TABLE targets ADD max_concurrent_deployments INTEGER NULL;
@@ -0,0 +1,110 @@
+package sqldb
+
+import (
+	"context"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+type (
+	NotificationChannelsStore interface {
+		domain.NotificationChannelsReader
+		domain.NotificationChannelsWriter
+	}
+
+	notificationChannelsStore struct {
+		db *sqldb.Database
+	}
+)
+
+func NewNotificationChannelsStore(db *sqldb.Database) NotificationChannelsStore {
+	return &notificationChannelsStore{db}
+}
+
+func (s *notificationChannelsStore) GetByID(ctx context.Context, id domain.NotificationChannelID) (domain.NotificationChannel, error) {
+	return builder.
+		Query[domain.NotificationChannel](`
+		SELECT
+			id
+			,name
+			,kind
+			,destination
+			,template
+			,rules
+			,created_at
+			,created_by
+		FROM notification_channels
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.NotificationChannelFrom)
+}
+
+func (s *notificationChannelsStore) GetAll(ctx context.Context) ([]domain.NotificationChannel, error) {
+	return builder.
+		Query[domain.NotificationChannel](`
+		SELECT
+			id
+			,name
+			,kind
+			,destination
+			,template
+			,rules
+			,created_at
+			,created_by
+		FROM notification_channels`).
+		All(s.db, ctx, domain.NotificationChannelFrom)
+}
+
+func (s *notificationChannelsStore) Write(ctx context.Context, channels ...*domain.NotificationChannel) error {
+	return sqldb.WriteAndDispatch(s.db, ctx, channels, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.NotificationChannelCreated:
+			return builder.
+				Insert("notification_channels", builder.Values{
+					"id":          evt.ID,
+					"name":        evt.Name,
+					"kind":        evt.Kind,
+					"destination": evt.Destination,
+					"created_at":  evt.Created.At(),
+					"created_by":  evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.NotificationChannelRenamed:
+			return builder.
+				Update("notification_channels", builder.Values{
+					"name": evt.Name,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.NotificationChannelDestinationChanged:
+			return builder.
+				Update("notification_channels", builder.Values{
+					"destination": evt.Destination,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.NotificationChannelTemplateChanged:
+			return builder.
+				Update("notification_channels", builder.Values{
+					"template": evt.Template,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.NotificationChannelRulesChanged:
+			return builder.
+				Update("notification_channels", builder.Values{
+					"rules": evt.Rules,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.NotificationChannelDeleted:
+			return builder.
+				Command("DELETE FROM notification_channels WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
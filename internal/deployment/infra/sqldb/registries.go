@@ -1,12 +1,12 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type (
@@ -16,11 +16,11 @@ type (
 	}
 
 	registriesStore struct {
-		db *sqlite.Database
+		db *sqldb.Database
 	}
 )
 
-func NewRegistriesStore(db *sqlite.Database) RegistriesStore {
+func NewRegistriesStore(db *sqldb.Database) RegistriesStore {
 	return &registriesStore{db}
 }
 
@@ -66,7 +66,7 @@ func (s *registriesStore) GetAll(ctx context.Context) ([]domain.Registry, error)
 }
 
 func (s *registriesStore) Write(ctx context.Context, registries ...*domain.Registry) error {
-	return sqlite.WriteAndDispatch(s.db, ctx, registries, func(ctx context.Context, e event.Event) error {
+	return sqldb.WriteAndDispatch(s.db, ctx, registries, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.RegistryCreated:
 			return builder.
@@ -0,0 +1,102 @@
+package sqldb
+
+import (
+	"context"
+	"time"
+
+	"github.com/YuukanOO/seelf/internal/deployment/domain"
+	"github.com/YuukanOO/seelf/pkg/event"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+type (
+	SchedulesStore interface {
+		domain.SchedulesReader
+		domain.SchedulesWriter
+	}
+
+	schedulesStore struct {
+		db *sqldb.Database
+	}
+)
+
+func NewSchedulesStore(db *sqldb.Database) SchedulesStore {
+	return &schedulesStore{db}
+}
+
+func (s *schedulesStore) GetByID(ctx context.Context, id domain.ScheduleID) (domain.Schedule, error) {
+	return builder.
+		Query[domain.Schedule](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,expression
+			,next_run_at
+			,last_run_at
+			,created_at
+			,created_by
+		FROM schedules
+		WHERE id = ?`, id).
+		One(s.db, ctx, domain.ScheduleFrom)
+}
+
+func (s *schedulesStore) GetDue(ctx context.Context, at time.Time) ([]domain.Schedule, error) {
+	return builder.
+		Query[domain.Schedule](`
+		SELECT
+			id
+			,app_id
+			,environment
+			,expression
+			,next_run_at
+			,last_run_at
+			,created_at
+			,created_by
+		FROM schedules
+		WHERE next_run_at <= ?
+		ORDER BY next_run_at`, at.UTC()).
+		All(s.db, ctx, domain.ScheduleFrom)
+}
+
+func (s *schedulesStore) Write(ctx context.Context, schedules ...*domain.Schedule) error {
+	return sqldb.WriteAndDispatch(s.db, ctx, schedules, func(ctx context.Context, e event.Event) error {
+		switch evt := e.(type) {
+		case domain.ScheduleCreated:
+			return builder.
+				Insert("schedules", builder.Values{
+					"id":          evt.ID,
+					"app_id":      evt.App,
+					"environment": evt.Environment,
+					"expression":  evt.Expression.String(),
+					"next_run_at": evt.NextRunAt,
+					"created_at":  evt.Created.At(),
+					"created_by":  evt.Created.By(),
+				}).
+				Exec(s.db, ctx)
+		case domain.ScheduleExpressionChanged:
+			return builder.
+				Update("schedules", builder.Values{
+					"expression":  evt.Expression.String(),
+					"next_run_at": evt.NextRunAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.ScheduleTriggered:
+			return builder.
+				Update("schedules", builder.Values{
+					"last_run_at": evt.RanAt,
+					"next_run_at": evt.NextRunAt,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.ScheduleDeleted:
+			return builder.
+				Command("DELETE FROM schedules WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		default:
+			return nil
+		}
+	})
+}
@@ -1,12 +1,12 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
 
 	"github.com/YuukanOO/seelf/internal/deployment/domain"
 	"github.com/YuukanOO/seelf/pkg/event"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 )
 
 type (
@@ -16,11 +16,11 @@ type (
 	}
 
 	targetsStore struct {
-		db *sqlite.Database
+		db *sqldb.Database
 	}
 )
 
-func NewTargetsStore(db *sqlite.Database) TargetsStore {
+func NewTargetsStore(db *sqldb.Database) TargetsStore {
 	return &targetsStore{db}
 }
 
@@ -58,6 +58,7 @@ func (s *targetsStore) GetLocalTarget(ctx context.Context) (domain.Target, error
 			,state_errcode
 			,state_last_ready_version
 			,entrypoints
+			,max_concurrent_deployments
 			,cleanup_requested_at
 			,cleanup_requested_by
 			,created_at
@@ -82,6 +83,7 @@ func (s *targetsStore) GetByID(ctx context.Context, id domain.TargetID) (domain.
 			,state_errcode
 			,state_last_ready_version
 			,entrypoints
+			,max_concurrent_deployments
 			,cleanup_requested_at
 			,cleanup_requested_by
 			,created_at
@@ -92,7 +94,7 @@ func (s *targetsStore) GetByID(ctx context.Context, id domain.TargetID) (domain.
 }
 
 func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error {
-	return sqlite.WriteAndDispatch(s.db, c, targets, func(ctx context.Context, e event.Event) error {
+	return sqldb.WriteAndDispatch(s.db, c, targets, func(ctx context.Context, e event.Event) error {
 		switch evt := e.(type) {
 		case domain.TargetCreated:
 			return builder.
@@ -135,6 +137,20 @@ func (s *targetsStore) Write(c context.Context, targets ...*domain.Target) error
 				}).
 				F("WHERE id = ?", evt.ID).
 				Exec(s.db, ctx)
+		case domain.TargetMaxDeploymentsChanged:
+			return builder.
+				Update("targets", builder.Values{
+					"max_concurrent_deployments": evt.Max,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
+		case domain.TargetMaxDeploymentsRemoved:
+			return builder.
+				Update("targets", builder.Values{
+					"max_concurrent_deployments": nil,
+				}).
+				F("WHERE id = ?", evt.ID).
+				Exec(s.db, ctx)
 		case domain.TargetUrlRemoved:
 			return builder.
 				Update("targets", builder.Values{
@@ -1,12 +0,0 @@
-package sqlite
-
-import (
-	"embed"
-
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
-)
-
-//go:embed migrations/*.sql
-var migrations embed.FS
-
-var Migrations = sqlite.NewMigrationsModule("deployment", "migrations", migrations)
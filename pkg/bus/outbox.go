@@ -0,0 +1,309 @@
+package bus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+// Default retry policy of messages which could not be delivered to an asynchronous subscriber:
+// retry with an exponential backoff for about a day before giving up.
+var DefaultOutboxRetryPolicy = RetryPolicy{
+	MaxAttempts: 30,
+	Delay:       5 * time.Second,
+	MaxDelay:    time.Hour,
+}
+
+type (
+	// Handler of an asynchronous subscriber receiving the raw signal data as persisted in the outbox.
+	OutboxHandler func(context.Context, string) error
+
+	// Signal persisted in the outbox waiting to be delivered to an asynchronous subscriber.
+	OutboxMessage interface {
+		ID() string
+		Subscriber() string // Name of the subscriber this message should be delivered to
+		Name() string       // Name of the signal
+		Data() string       // Serialized signal
+		Attempts() int      // Number of failed deliveries so far
+	}
+
+	// Adapter used to store signals waiting to be delivered. Messages MUST be created in
+	// the transaction of the given context if any, this is what guarantees they are only
+	// delivered if the changes which have raised them have been committed.
+	OutboxStore interface {
+		Setup() error                                                       // Setup the store
+		Create(ctx context.Context, subscriber string, signal Signal) error // Persist a signal for the given subscriber
+		GetNextPendingMessages(context.Context) ([]OutboxMessage, error)    // Claim the next messages to deliver
+		Heartbeat(context.Context) error                                    // Renew the lease of messages claimed by this instance
+		Delivered(context.Context, OutboxMessage) error                     // Remove a message successfully delivered
+		Retry(context.Context, OutboxMessage, error, time.Duration) error   // Deliver the message again after the given delay
+		Fail(context.Context, OutboxMessage, error) error                   // Give up on delivering the given message
+		Release(context.Context, OutboxMessage) error                       // Give back a claimed message without attempting it
+		Purge(context.Context, time.Time) error                             // Remove messages given up before the given time
+	}
+
+	// Outbox on which asynchronous subscribers are registered, see OnAsync.
+	Outbox interface {
+		Subscribe(subscriber string, handler OutboxHandler)                  // Register the handler of the given subscriber
+		Enqueue(ctx context.Context, subscriber string, signal Signal) error // Persist the signal to deliver it later to the given subscriber
+	}
+
+	// Outbox relaying persisted messages to their subscribers once started.
+	RunnableOutbox interface {
+		Outbox
+		Start()
+		Stop()
+	}
+
+	defaultOutbox struct {
+		store        OutboxStore
+		logger       log.Logger
+		pollInterval time.Duration
+		retention    time.Duration
+		retry        RetryPolicy
+		subscribers  map[string]OutboxHandler
+		started      bool
+		done         []chan bool
+		exitGroup    sync.WaitGroup
+	}
+)
+
+// Builds a new outbox persisting signals with the given adapter and delivering them to
+// asynchronous subscribers at least once. Failed deliveries are retried with the given policy
+// and messages which have exhausted it are kept for the given retention, 0 to keep them forever.
+func NewOutbox(store OutboxStore, logger log.Logger, pollInterval, retention time.Duration, retry RetryPolicy) RunnableOutbox {
+	return &defaultOutbox{
+		store:        store,
+		logger:       logger,
+		pollInterval: pollInterval,
+		retention:    retention,
+		retry:        retry,
+		subscribers:  make(map[string]OutboxHandler),
+	}
+}
+
+// Register an asynchronous handler for the given signal. Contrary to On, the handler is not
+// called when the signal is dispatched: the signal is written to the outbox, in the same transaction
+// as the changes which have raised it, and delivered later, at least once, by the outbox relay.
+// A failing handler does not rollback those changes, the delivery is retried instead.
+//
+// The subscriber name is persisted alongside pending messages so it must be unique and stable
+// and the signal must be (un)marshallable to JSON. Handlers should be idempotent.
+func OnAsync[TSignal Signal](bus Bus, outbox Outbox, subscriber string, handler SignalHandler[TSignal]) {
+	outbox.Subscribe(subscriber, func(ctx context.Context, data string) error {
+		signal, err := storage.UnmarshalJSON[TSignal](data)
+
+		if err != nil {
+			return err
+		}
+
+		return handler(ctx, signal)
+	})
+
+	On(bus, func(ctx context.Context, signal TSignal) error {
+		return outbox.Enqueue(ctx, subscriber, signal)
+	})
+}
+
+func (o *defaultOutbox) Subscribe(subscriber string, handler OutboxHandler) {
+	if _, exists := o.subscribers[subscriber]; exists {
+		panic("an outbox subscriber is already registered for " + subscriber) // Panic since this should never happen outside of a dev environment
+	}
+
+	o.subscribers[subscriber] = handler
+}
+
+func (o *defaultOutbox) Enqueue(ctx context.Context, subscriber string, signal Signal) error {
+	return o.store.Create(ctx, subscriber, signal)
+}
+
+func (o *defaultOutbox) Start() {
+	if o.started {
+		return
+	}
+
+	o.started = true
+
+	o.startRelay()
+	o.startHeartbeat()
+}
+
+func (o *defaultOutbox) Stop() {
+	if !o.started {
+		return
+	}
+
+	o.logger.Info("waiting for current outbox deliveries to finish")
+
+	for _, done := range o.done {
+		done <- true
+	}
+
+	o.exitGroup.Wait()
+}
+
+// Tiny helper to run a function in a goroutine and keep track of done channels.
+func (o *defaultOutbox) run(fn func(<-chan bool)) {
+	done := make(chan bool, 1)
+	o.done = append(o.done, done)
+
+	o.exitGroup.Add(1)
+	go func(d <-chan bool) {
+		defer o.exitGroup.Done()
+		fn(d)
+	}(done)
+}
+
+func (o *defaultOutbox) startRelay() {
+	o.run(func(done <-chan bool) {
+		var (
+			delay     time.Duration
+			lastRun   time.Time = time.Now()
+			lastPurge time.Time
+		)
+
+		for {
+			delay = o.pollInterval - time.Since(lastRun)
+
+			select {
+			case <-done:
+				return
+			case <-time.After(delay):
+			}
+
+			lastRun = time.Now()
+
+			if o.retention > 0 && lastRun.Sub(lastPurge) >= purgeInterval {
+				lastPurge = lastRun
+
+				if err := o.store.Purge(context.Background(), lastRun.Add(-o.retention)); err != nil {
+					o.logger.Errorw("error while purging outbox messages",
+						"error", err)
+				}
+			}
+
+			messages, err := o.store.GetNextPendingMessages(context.Background())
+
+			if err != nil {
+				o.logger.Errorw("error while retrieving outbox messages",
+					"error", err)
+				continue
+			}
+
+			// Messages are delivered in order to each subscriber so once a delivery has failed,
+			// the next messages of the same subscriber must wait for it to be retried first.
+			retrying := make(map[string]bool)
+
+			for _, msg := range messages {
+				if retrying[msg.Subscriber()] {
+					o.release(context.Background(), msg)
+					continue
+				}
+
+				if !o.deliver(context.Background(), msg) {
+					retrying[msg.Subscriber()] = true
+				}
+			}
+		}
+	})
+}
+
+// Renew leases of claimed messages so other instances sharing the same store do not
+// deliver them again while they are waiting for their turn.
+func (o *defaultOutbox) startHeartbeat() {
+	o.run(func(done <-chan bool) {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				if err := o.store.Heartbeat(context.Background()); err != nil {
+					o.logger.Errorw("error while renewing outbox messages lease",
+						"error", err)
+				}
+			}
+		}
+	})
+}
+
+// Deliver the given message to its subscriber, returns false if it will be retried later.
+func (o *defaultOutbox) deliver(ctx context.Context, msg OutboxMessage) bool {
+	handler, exists := o.subscribers[msg.Subscriber()]
+
+	// The subscriber may have been removed, there is no point in retrying
+	if !exists {
+		o.fail(ctx, msg, ErrNoHandlerRegistered)
+		return true
+	}
+
+	err := handler(ctx, msg.Data())
+
+	if err == nil {
+		if err = o.store.Delivered(ctx, msg); err != nil {
+			o.logger.Errorw("error while marking outbox message as delivered",
+				"message", msg.ID(),
+				"subscriber", msg.Subscriber(),
+				"error", err)
+		}
+		return true
+	}
+
+	attempts := msg.Attempts() + 1
+
+	if o.retry.IsExhausted(attempts) {
+		o.fail(ctx, msg, err)
+		return true
+	}
+
+	delay := o.retry.DelayFor(attempts)
+
+	o.logger.Warnw("error while delivering outbox message, it will be retried later",
+		"message", msg.ID(),
+		"subscriber", msg.Subscriber(),
+		"name", msg.Name(),
+		"attempts", attempts,
+		"delay", delay,
+		"error", err)
+
+	if err = o.store.Retry(ctx, msg, err, delay); err != nil {
+		o.logger.Errorw("error while retrying outbox message",
+			"message", msg.ID(),
+			"subscriber", msg.Subscriber(),
+			"error", err)
+	}
+
+	return false
+}
+
+// Give back a message claimed in the same batch as an earlier one of the same subscriber
+// which will be retried, it will be claimed again once the earlier one has been delivered.
+func (o *defaultOutbox) release(ctx context.Context, msg OutboxMessage) {
+	if err := o.store.Release(ctx, msg); err != nil {
+		o.logger.Errorw("error while releasing outbox message",
+			"message", msg.ID(),
+			"subscriber", msg.Subscriber(),
+			"error", err)
+	}
+}
+
+func (o *defaultOutbox) fail(ctx context.Context, msg OutboxMessage, reason error) {
+	o.logger.Errorw("could not deliver outbox message, giving up",
+		"message", msg.ID(),
+		"subscriber", msg.Subscriber(),
+		"name", msg.Name(),
+		"attempts", msg.Attempts()+1,
+		"error", reason)
+
+	if err := o.store.Fail(ctx, msg, reason); err != nil {
+		o.logger.Errorw("error while failing outbox message",
+			"message", msg.ID(),
+			"subscriber", msg.Subscriber(),
+			"error", err)
+	}
+}
@@ -0,0 +1,264 @@
+package bus_test
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+func TestOutbox(t *testing.T) {
+	logger := must.Panic(log.NewLogger())
+
+	arrange := func(policy bus.RetryPolicy) (bus.Bus, bus.RunnableOutbox, *outboxAdapter) {
+		adapter := &outboxAdapter{}
+		return memory.NewBus(), bus.NewOutbox(adapter, logger, 0, 0, policy), adapter
+	}
+
+	t.Run("should persist signals instead of calling asynchronous handlers right away", func(t *testing.T) {
+		b, outbox, adapter := arrange(bus.DefaultOutboxRetryPolicy)
+		called := false
+		bus.OnAsync(b, outbox, "subscriber", func(context.Context, asyncSignal) error {
+			called = true
+			return nil
+		})
+
+		assert.Nil(t, b.Notify(context.Background(), asyncSignal{Value: "one"}))
+
+		assert.False(t, called)
+		assert.HasLength(t, 1, adapter.messages)
+		assert.Equal(t, "subscriber", adapter.messages[0].subscriber)
+		assert.Equal(t, asyncSignal{}.Name_(), adapter.messages[0].name)
+		assert.Equal(t, `{"value":"one"}`, adapter.messages[0].data)
+	})
+
+	t.Run("should deliver persisted signals to their subscriber", func(t *testing.T) {
+		b, outbox, adapter := arrange(bus.DefaultOutboxRetryPolicy)
+		var (
+			mu       sync.Mutex
+			received []asyncSignal
+		)
+		bus.OnAsync(b, outbox, "subscriber", func(_ context.Context, s asyncSignal) error {
+			mu.Lock()
+			defer mu.Unlock()
+			received = append(received, s)
+			return nil
+		})
+
+		assert.Nil(t, b.Notify(context.Background(), asyncSignal{Value: "one"}, asyncSignal{Value: "two"}))
+
+		outbox.Start()
+		adapter.wait()
+		outbox.Stop()
+
+		assert.DeepEqual(t, []asyncSignal{{Value: "one"}, {Value: "two"}}, received)
+		assert.HasLength(t, 2, adapter.delivered)
+	})
+
+	t.Run("should retry failed deliveries and give up once the policy is exhausted", func(t *testing.T) {
+		b, outbox, adapter := arrange(bus.RetryPolicy{MaxAttempts: 2, Delay: 10 * time.Second, MaxDelay: 10 * time.Second})
+		deliveryErr := errors.New("some error")
+		failing := func(context.Context, asyncSignal) error {
+			return deliveryErr
+		}
+		bus.OnAsync(b, outbox, "subscriber", failing)
+		bus.OnAsync(b, outbox, "other", failing)
+
+		assert.Nil(t, outbox.Enqueue(context.Background(), "subscriber", asyncSignal{Value: "one"}))
+		adapter.messages[0].attempts = 0
+		assert.Nil(t, outbox.Enqueue(context.Background(), "other", asyncSignal{Value: "two"}))
+		adapter.messages[1].attempts = 1
+
+		outbox.Start()
+		adapter.wait()
+		outbox.Stop()
+
+		assert.HasLength(t, 1, adapter.retried)
+		assert.Equal(t, "0", adapter.retried[0].ID())
+		assert.ErrorIs(t, deliveryErr, adapter.retried[0].err)
+		assert.True(t, adapter.retried[0].delay >= 9*time.Second && adapter.retried[0].delay <= 11*time.Second)
+
+		assert.HasLength(t, 1, adapter.failed)
+		assert.Equal(t, "1", adapter.failed[0].ID())
+		assert.ErrorIs(t, deliveryErr, adapter.failed[0].err)
+	})
+
+	t.Run("should hold back next messages of a subscriber until a failed one has been retried", func(t *testing.T) {
+		b, outbox, adapter := arrange(bus.DefaultOutboxRetryPolicy)
+		deliveryErr := errors.New("some error")
+		var received []asyncSignal
+		bus.OnAsync(b, outbox, "failing", func(context.Context, asyncSignal) error {
+			return deliveryErr
+		})
+		bus.OnAsync(b, outbox, "working", func(_ context.Context, s asyncSignal) error {
+			received = append(received, s)
+			return nil
+		})
+
+		assert.Nil(t, outbox.Enqueue(context.Background(), "failing", asyncSignal{Value: "one"}))
+		assert.Nil(t, outbox.Enqueue(context.Background(), "working", asyncSignal{Value: "two"}))
+		assert.Nil(t, outbox.Enqueue(context.Background(), "failing", asyncSignal{Value: "three"}))
+
+		outbox.Start()
+		adapter.wait()
+		outbox.Stop()
+
+		assert.HasLength(t, 1, adapter.retried)
+		assert.Equal(t, "0", adapter.retried[0].ID())
+		assert.HasLength(t, 1, adapter.released)
+		assert.Equal(t, "2", adapter.released[0].ID())
+		assert.DeepEqual(t, []asyncSignal{{Value: "two"}}, received)
+	})
+
+	t.Run("should give up messages of unknown subscribers", func(t *testing.T) {
+		_, outbox, adapter := arrange(bus.DefaultOutboxRetryPolicy)
+
+		assert.Nil(t, outbox.Enqueue(context.Background(), "removed", asyncSignal{Value: "one"}))
+
+		outbox.Start()
+		adapter.wait()
+		outbox.Stop()
+
+		assert.HasLength(t, 1, adapter.failed)
+		assert.ErrorIs(t, bus.ErrNoHandlerRegistered, adapter.failed[0].err)
+	})
+
+	t.Run("should panic if a subscriber is registered twice", func(t *testing.T) {
+		b, outbox, _ := arrange(bus.DefaultOutboxRetryPolicy)
+		handler := func(context.Context, asyncSignal) error { return nil }
+
+		bus.OnAsync(b, outbox, "subscriber", handler)
+
+		defer func() {
+			assert.NotZero(t, recover())
+		}()
+
+		bus.OnAsync(b, outbox, "subscriber", handler)
+	})
+}
+
+var (
+	_ bus.OutboxMessage = (*outboxMessage)(nil)
+	_ bus.OutboxStore   = (*outboxAdapter)(nil)
+	_ bus.Signal        = (*asyncSignal)(nil)
+)
+
+type (
+	asyncSignal struct {
+		bus.Notification
+
+		Value string `json:"value"`
+	}
+
+	outboxMessage struct {
+		id         int
+		subscriber string
+		name       string
+		data       string
+		attempts   int
+		err        error
+		delay      time.Duration
+	}
+
+	outboxAdapter struct {
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		messages  []*outboxMessage
+		pending   []*outboxMessage
+		delivered []*outboxMessage
+		retried   []*outboxMessage
+		failed    []*outboxMessage
+		released  []*outboxMessage
+	}
+)
+
+func (asyncSignal) Name_() string { return "asyncSignal" }
+
+func (m *outboxMessage) ID() string         { return strconv.Itoa(m.id) }
+func (m *outboxMessage) Subscriber() string { return m.subscriber }
+func (m *outboxMessage) Name() string       { return m.name }
+func (m *outboxMessage) Data() string       { return m.data }
+func (m *outboxMessage) Attempts() int      { return m.attempts }
+
+func (a *outboxAdapter) Setup() error { return nil }
+
+func (a *outboxAdapter) Create(_ context.Context, subscriber string, signal bus.Signal) error {
+	data, err := storage.ValueJSON(signal)
+
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.wg.Add(1)
+	msg := &outboxMessage{id: len(a.messages), subscriber: subscriber, name: signal.Name_(), data: data.(string)}
+	a.messages = append(a.messages, msg)
+	a.pending = append(a.pending, msg)
+
+	return nil
+}
+
+func (a *outboxAdapter) wait() {
+	a.wg.Wait()
+}
+
+func (a *outboxAdapter) GetNextPendingMessages(context.Context) ([]bus.OutboxMessage, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	m := make([]bus.OutboxMessage, len(a.pending))
+
+	for i, msg := range a.pending {
+		m[i] = msg
+	}
+
+	a.pending = nil
+
+	return m, nil
+}
+
+func (a *outboxAdapter) Heartbeat(context.Context) error { return nil }
+
+func (a *outboxAdapter) Delivered(_ context.Context, msg bus.OutboxMessage) error {
+	defer a.wg.Done()
+	a.delivered = append(a.delivered, msg.(*outboxMessage))
+	return nil
+}
+
+func (a *outboxAdapter) Retry(_ context.Context, msg bus.OutboxMessage, deliveryErr error, delay time.Duration) error {
+	defer a.wg.Done()
+	m := msg.(*outboxMessage)
+	m.err = deliveryErr
+	m.delay = delay
+
+	a.retried = append(a.retried, m)
+	return nil
+}
+
+func (a *outboxAdapter) Fail(_ context.Context, msg bus.OutboxMessage, deliveryErr error) error {
+	defer a.wg.Done()
+	m := msg.(*outboxMessage)
+	m.err = deliveryErr
+
+	a.failed = append(a.failed, m)
+	return nil
+}
+
+func (a *outboxAdapter) Release(_ context.Context, msg bus.OutboxMessage) error {
+	defer a.wg.Done()
+	a.released = append(a.released, msg.(*outboxMessage))
+	return nil
+}
+
+func (a *outboxAdapter) Purge(context.Context, time.Time) error { return nil }
@@ -0,0 +1,229 @@
+// Package relay forwards signals dispatched on a bus to external subscribers such as
+// server-sent events clients.
+//
+// Events are written to a shared store once the changes which have raised them have been
+// committed and every instance polls this store to publish them to its own subscribers, so a
+// subscriber receives events raised on any instance.
+package relay
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/storage"
+)
+
+const (
+	subscriberBufferSize = 64
+	eventsRetention      = 10 * time.Minute // Events only need to be kept until every instance has polled them
+	purgeInterval        = time.Minute
+)
+
+type (
+	// Event relayed to subscribers. Data should be serializable since it will
+	// be persisted and probably sent over the wire.
+	Event struct {
+		Name string
+		Data any
+	}
+
+	// Filter used by a subscriber to only receive events it is allowed to see.
+	Filter func(Event) bool
+
+	// Adapter used to share events between instances. Events read back from the store
+	// have their Data as a json.RawMessage.
+	Store interface {
+		Setup() error                                            // Setup the store
+		Append(context.Context, Event) error                     // Persist an event to relay
+		Cursor(context.Context) (int64, error)                   // Position of the latest persisted event
+		GetAfter(context.Context, int64) ([]Event, int64, error) // Retrieve events persisted after the given position and the new position
+		Purge(context.Context, time.Time) error                  // Remove events persisted before the given time
+	}
+
+	// Fan out relayed events to every subscribers. Subscribers which could not keep
+	// up will miss events instead of blocking the others.
+	Relay struct {
+		store        Store
+		logger       log.Logger
+		pollInterval time.Duration
+		mu           sync.RWMutex
+		subscribers  map[chan Event]Filter
+		done         chan struct{}
+		exitGroup    sync.WaitGroup
+	}
+
+	// Signal persisted in the outbox for an event to relay.
+	forwarded struct {
+		bus.Notification
+		Event
+	}
+)
+
+func (forwarded) Name_() string { return "relay.forwarded" }
+
+// Builds a new relay sharing events through the given store, polled at the given interval
+// once started.
+func New(store Store, logger log.Logger, pollInterval time.Duration) *Relay {
+	return &Relay{
+		store:        store,
+		logger:       logger,
+		pollInterval: pollInterval,
+		subscribers:  make(map[chan Event]Filter),
+	}
+}
+
+// Forward every signal of the given type to the relay using the given name and
+// mapper to build the event data. The event is written to the outbox, in the same
+// transaction as the changes which have raised the signal, so subscribers are never
+// notified about changes which have been rolled back.
+func Forward[TSignal bus.Signal](b bus.Bus, outbox bus.Outbox, r *Relay, name string, mapper func(TSignal) any) {
+	subscriber := "relay." + name
+
+	// The signal is mapped right away since only the event data is meant to be serializable
+	outbox.Subscribe(subscriber, func(ctx context.Context, data string) error {
+		evt, err := storage.UnmarshalJSON[struct {
+			Name string
+			Data json.RawMessage
+		}](data)
+
+		if err != nil {
+			return err
+		}
+
+		return r.store.Append(ctx, Event{
+			Name: evt.Name,
+			Data: evt.Data,
+		})
+	})
+
+	bus.On(b, func(ctx context.Context, signal TSignal) error {
+		return outbox.Enqueue(ctx, subscriber, forwarded{Event: Event{
+			Name: name,
+			Data: mapper(signal),
+		}})
+	})
+}
+
+// Start polling the store to publish events to subscribers of this instance.
+func (r *Relay) Start() {
+	if r.done != nil {
+		return
+	}
+
+	r.done = make(chan struct{})
+	r.exitGroup.Add(1)
+
+	go func(done <-chan struct{}) {
+		defer r.exitGroup.Done()
+		r.poll(done)
+	}(r.done)
+}
+
+// Stop polling the store.
+func (r *Relay) Stop() {
+	if r.done == nil {
+		return
+	}
+
+	close(r.done)
+	r.done = nil
+	r.exitGroup.Wait()
+}
+
+// Subscribe to relayed events accepted by the given filter, nil to receive all of them.
+// The returned function must be called to unsubscribe when done.
+func (r *Relay) Subscribe(filter Filter) (<-chan Event, func()) {
+	subscriber := make(chan Event, subscriberBufferSize)
+
+	r.mu.Lock()
+	r.subscribers[subscriber] = filter
+	r.mu.Unlock()
+
+	var once sync.Once
+
+	return subscriber, func() {
+		once.Do(func() {
+			r.mu.Lock()
+			delete(r.subscribers, subscriber)
+			r.mu.Unlock()
+		})
+	}
+}
+
+func (r *Relay) poll(done <-chan struct{}) {
+	var (
+		cursor    int64
+		hasCursor bool
+		lastPurge time.Time
+		err       error
+	)
+
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Only events persisted from now on should be published, start from the latest one
+		if !hasCursor {
+			if cursor, err = r.store.Cursor(context.Background()); err != nil {
+				r.logger.Errorw("error while retrieving relayed events position",
+					"error", err)
+			} else {
+				hasCursor = true
+			}
+		}
+
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+		}
+
+		if !hasCursor {
+			continue
+		}
+
+		if now := time.Now(); now.Sub(lastPurge) >= purgeInterval {
+			lastPurge = now
+
+			if err = r.store.Purge(context.Background(), now.Add(-eventsRetention)); err != nil {
+				r.logger.Errorw("error while purging relayed events",
+					"error", err)
+			}
+		}
+
+		events, next, err := r.store.GetAfter(context.Background(), cursor)
+
+		if err != nil {
+			r.logger.Errorw("error while retrieving relayed events",
+				"error", err)
+			continue
+		}
+
+		cursor = next
+
+		for _, evt := range events {
+			r.publish(evt)
+		}
+	}
+}
+
+// Publish an event to every current subscribers accepting it.
+func (r *Relay) publish(evt Event) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for subscriber, filter := range r.subscribers {
+		if filter != nil && !filter(evt) {
+			continue
+		}
+
+		select {
+		case subscriber <- evt:
+		default: // Subscriber is too slow, drop the event
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package relay_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+const pollInterval = 10 * time.Millisecond
+
+type somethingHappened struct {
+	bus.Notification
+
+	ID string
+}
+
+func (somethingHappened) Name_() string { return "something_happened" }
+
+func Test_Relay(t *testing.T) {
+
+	arrange := func(tb testing.TB, s *store) *relay.Relay {
+		r := relay.New(s, must.Panic(log.NewLogger()), pollInterval)
+		r.Start()
+		tb.Cleanup(r.Stop)
+
+		// Wait for the relay to retrieve its starting position
+		time.Sleep(2 * pollInterval)
+
+		return r
+	}
+
+	t.Run("should publish persisted events to every subscribers", func(t *testing.T) {
+		s := &store{}
+		r := arrange(t, s)
+		other := arrange(t, s) // Another instance sharing the same store
+		first, unsubscribeFirst := r.Subscribe(nil)
+		defer unsubscribeFirst()
+		second, unsubscribeSecond := other.Subscribe(nil)
+		defer unsubscribeSecond()
+
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "an_event", Data: "some data"}))
+
+		assert.DeepEqual(t, relay.Event{Name: "an_event", Data: json.RawMessage(`"some data"`)}, receive(t, first))
+		assert.DeepEqual(t, relay.Event{Name: "an_event", Data: json.RawMessage(`"some data"`)}, receive(t, second))
+	})
+
+	t.Run("should not publish events persisted before it has started", func(t *testing.T) {
+		s := &store{}
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "old_event"}))
+		r := arrange(t, s)
+		events, unsubscribe := r.Subscribe(nil)
+		defer unsubscribe()
+
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "new_event"}))
+
+		assert.Equal(t, "new_event", receive(t, events).Name)
+	})
+
+	t.Run("should only publish events accepted by the subscriber filter", func(t *testing.T) {
+		s := &store{}
+		r := arrange(t, s)
+		events, unsubscribe := r.Subscribe(func(evt relay.Event) bool { return evt.Name == "allowed" })
+		defer unsubscribe()
+
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "forbidden"}))
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "allowed"}))
+
+		assert.Equal(t, "allowed", receive(t, events).Name)
+		assert.Equal(t, 0, len(events))
+	})
+
+	t.Run("should not publish events to unsubscribed subscribers", func(t *testing.T) {
+		s := &store{}
+		r := arrange(t, s)
+		events, unsubscribe := r.Subscribe(nil)
+
+		unsubscribe()
+		unsubscribe()
+		assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "an_event"}))
+		time.Sleep(2 * pollInterval)
+
+		assert.Equal(t, 0, len(events))
+	})
+
+	t.Run("should drop events if a subscriber could not keep up", func(t *testing.T) {
+		s := &store{}
+		r := arrange(t, s)
+		events, unsubscribe := r.Subscribe(nil)
+		defer unsubscribe()
+
+		for i := 0; i < 100; i++ {
+			assert.Nil(t, s.Append(context.Background(), relay.Event{Name: "an_event"}))
+		}
+
+		time.Sleep(2 * pollInterval)
+
+		assert.Equal(t, 64, len(events))
+	})
+
+	t.Run("should forward signals dispatched on the bus once delivered by the outbox", func(t *testing.T) {
+		b := memory.NewBus()
+		s := &store{}
+		o := &outbox{}
+		r := arrange(t, s)
+		relay.Forward(b, o, r, "something", func(s somethingHappened) any { return s.ID })
+		events, unsubscribe := r.Subscribe(nil)
+		defer unsubscribe()
+
+		assert.Nil(t, b.Notify(context.Background(), somethingHappened{ID: "1"}))
+		time.Sleep(2 * pollInterval)
+
+		assert.Equal(t, 0, len(events))
+
+		assert.Nil(t, o.deliver(context.Background()))
+
+		assert.DeepEqual(t, relay.Event{Name: "something", Data: json.RawMessage(`"1"`)}, receive(t, events))
+	})
+}
+
+func receive(t testing.TB, events <-chan relay.Event) relay.Event {
+	t.Helper()
+
+	select {
+	case evt := <-events:
+		return evt
+	case <-time.After(50 * pollInterval):
+		t.Fatal("should have received an event")
+		return relay.Event{}
+	}
+}
+
+type (
+	// In memory store mimicking the sql one.
+	store struct {
+		mu     sync.Mutex
+		events []relay.Event
+	}
+
+	// Outbox keeping enqueued signals until deliver is called.
+	outbox struct {
+		handlers map[string]bus.OutboxHandler
+		pending  []enqueued
+	}
+
+	enqueued struct {
+		subscriber string
+		data       string
+	}
+)
+
+func (s *store) Setup() error { return nil }
+
+func (s *store) Append(_ context.Context, evt relay.Event) error {
+	data, err := json.Marshal(evt.Data)
+
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.events = append(s.events, relay.Event{Name: evt.Name, Data: json.RawMessage(data)})
+
+	return nil
+}
+
+func (s *store) Cursor(context.Context) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.events)), nil
+}
+
+func (s *store) GetAfter(_ context.Context, cursor int64) ([]relay.Event, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.events[cursor:], int64(len(s.events)), nil
+}
+
+func (s *store) Purge(context.Context, time.Time) error { return nil }
+
+func (o *outbox) Subscribe(subscriber string, handler bus.OutboxHandler) {
+	if o.handlers == nil {
+		o.handlers = make(map[string]bus.OutboxHandler)
+	}
+
+	o.handlers[subscriber] = handler
+}
+
+func (o *outbox) Enqueue(_ context.Context, subscriber string, signal bus.Signal) error {
+	data, err := json.Marshal(signal)
+
+	if err != nil {
+		return err
+	}
+
+	o.pending = append(o.pending, enqueued{subscriber, string(data)})
+
+	return nil
+}
+
+func (o *outbox) deliver(ctx context.Context) error {
+	for _, msg := range o.pending {
+		if err := o.handlers[msg.subscriber](ctx, msg.data); err != nil {
+			return err
+		}
+	}
+
+	o.pending = nil
+
+	return nil
+}
@@ -0,0 +1,53 @@
+package bus
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+const retryJitterRatio = 0.1
+
+// Default retry policy used when none is given when queuing a job: retry it every
+// 15 seconds until it succeeds.
+var DefaultRetryPolicy = RetryPolicy{
+	Delay:    15 * time.Second,
+	MaxDelay: 15 * time.Second,
+}
+
+// Determine how a failed job should be retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Number of attempts before the job is moved to the dead-letter queue, 0 means unlimited
+	Delay       time.Duration // Delay before the first retry, doubled on each attempt
+	MaxDelay    time.Duration // Upper bound of the delay between two attempts
+}
+
+// Returns true if a job which has failed the given number of times should not be
+// retried anymore.
+func (p RetryPolicy) IsExhausted(attempts int) bool {
+	return p.MaxAttempts > 0 && attempts >= p.MaxAttempts
+}
+
+// Computes the delay to wait before the next retry of a job which has failed the given
+// number of times. A jitter is applied to avoid every failed jobs to be retried at the same time.
+func (p RetryPolicy) DelayFor(attempts int) time.Duration {
+	delay := p.Delay
+
+	for i := 1; i < attempts && delay < p.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	delay = min(delay, max(p.MaxDelay, p.Delay))
+
+	if jitter := int64(float64(delay) * retryJitterRatio); jitter > 0 {
+		delay += time.Duration(rand.Int64N(2*jitter+1) - jitter)
+	}
+
+	return delay
+}
+
+// Sets the retry policy of the job being queued. If not set, DefaultRetryPolicy will be used.
+func WithRetry(policy RetryPolicy) JobOptions {
+	return func(o *CreateOptions) {
+		o.Retry = policy
+	}
+}
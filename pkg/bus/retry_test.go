@@ -0,0 +1,52 @@
+package bus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+)
+
+func Test_RetryPolicy(t *testing.T) {
+	t.Run("should never be exhausted if no max attempts has been set", func(t *testing.T) {
+		assert.False(t, bus.DefaultRetryPolicy.IsExhausted(1000))
+	})
+
+	t.Run("should be exhausted when the max attempts has been reached", func(t *testing.T) {
+		policy := bus.RetryPolicy{MaxAttempts: 3}
+
+		assert.False(t, policy.IsExhausted(2))
+		assert.True(t, policy.IsExhausted(3))
+	})
+
+	t.Run("should double the delay on each attempt up to the max delay with some jitter", func(t *testing.T) {
+		policy := bus.RetryPolicy{Delay: 10 * time.Second, MaxDelay: time.Minute}
+
+		tests := []struct {
+			attempts int
+			expected time.Duration
+		}{
+			{1, 10 * time.Second},
+			{2, 20 * time.Second},
+			{3, 40 * time.Second},
+			{4, time.Minute},
+			{10, time.Minute},
+		}
+
+		for _, tt := range tests {
+			delay := policy.DelayFor(tt.attempts)
+			jitter := tt.expected / 10
+
+			assert.True(t, delay >= tt.expected-jitter && delay <= tt.expected+jitter)
+		}
+	})
+
+	t.Run("should use the delay if the max delay is lower", func(t *testing.T) {
+		policy := bus.RetryPolicy{Delay: 10 * time.Second}
+
+		delay := policy.DelayFor(5)
+
+		assert.True(t, delay >= 9*time.Second && delay <= 11*time.Second)
+	})
+}
@@ -2,6 +2,7 @@ package bus
 
 import (
 	"context"
+	"strconv"
 	"sync"
 	"time"
 
@@ -17,8 +18,45 @@ const (
 	JobPolicyMerge                                         // If another job for the same resource and the same message name exists and is pending, replace it's payload
 )
 
+const (
+	JobStatePending  JobState = "pending"  // Waiting to be processed for the first time
+	JobStateRetrying JobState = "retrying" // Has failed at least once and is waiting to be retried
+	JobStateRunning  JobState = "running"  // Currently being processed
+	JobStateDone     JobState = "done"     // Has been processed successfully
+	JobStateDead     JobState = "dead"     // Has exhausted its attempts and sits in the dead-letter queue
+)
+
+// Finished jobs older than the retention period are purged at most once per this interval.
+const purgeInterval = time.Hour
+
+// Interval at which leases of claimed jobs are renewed. Stores must use a lease long enough
+// to survive a few missed heartbeats.
+const heartbeatInterval = 5 * time.Second
+
+const (
+	JobChangeQueued    JobChange = "queued"    // A job has been queued
+	JobChangeDone      JobChange = "done"      // A job has been processed successfully
+	JobChangeRetried   JobChange = "retried"   // A job has failed and will be retried later
+	JobChangeDead      JobChange = "dead"      // A job has exhausted its attempts and has been moved to the dead-letter queue
+	JobChangeDeleted   JobChange = "deleted"   // A job has been deleted by a user
+	JobChangeRequeued  JobChange = "requeued"  // A dead job has been requeued by a user
+	JobChangeCancelled JobChange = "cancelled" // Jobs for a message have been cancelled
+)
+
 type (
 	JobPolicy uint8
+	JobChange string
+	JobState  string
+
+	// Signal raised when the jobs queue has changed. Job id or message name may be
+	// empty when not known by the emitter.
+	JobQueueChanged struct {
+		Notification
+
+		JobID   string
+		Message string
+		Change  JobChange
+	}
 
 	// Represents a schedulable request, one that can be queued for later dispatching.
 	Schedulable interface {
@@ -30,12 +68,29 @@ type (
 	Scheduler interface {
 		// Queue a request to be dispatched asynchronously at a later time.
 		Queue(context.Context, Schedulable, ...JobOptions) error
+		// Cancel jobs for the same message name and resource id. Pending ones are removed from the
+		// queue. The context given to the handlers of running ones is cancelled right away if they
+		// run on this instance, or at the next heartbeat of the instance owning them otherwise.
+		Cancel(context.Context, Schedulable) error
+		// Retrieve messages with the given name of jobs currently claimed by an instance, whichever
+		// it is, so callers can tell which resources are still being processed.
+		Claimed(context.Context, string) ([]Request, error)
 	}
 
 	// Job option passed down to adapter.
 	CreateOptions struct {
-		Group  monad.Maybe[string]
-		Policy JobPolicy
+		Group       monad.Maybe[string]
+		Policy      JobPolicy
+		Retry       RetryPolicy
+		NotBefore   monad.Maybe[time.Time]        // Dispatch the job only once this time has been reached, now if not set
+		Concurrency monad.Maybe[ConcurrencyLimit] // Limit the number of jobs sharing a key processed at the same time
+	}
+
+	// Maximum number of jobs sharing the same key which could be processed at the same time,
+	// whatever their group is.
+	ConcurrencyLimit struct {
+		Key string
+		Max int
 	}
 
 	JobOptions func(*CreateOptions)
@@ -52,10 +107,25 @@ type (
 		ID() string
 		Message() Request
 		Policy() JobPolicy
+		Attempts() int // Number of failed attempts so far
+		RetryPolicy() RetryPolicy
+	}
+
+	// Placeholder message of a claimed job whose payload could not be decoded, for example
+	// because it has been queued by a newer release. Such a job could never succeed so the
+	// scheduler moves it to the dead-letter queue right away with the decoding error.
+	UndecodableRequest struct {
+		Command[UnitType]
+
+		Name string
+		Err  error
 	}
 
 	GetJobsFilters struct {
-		Page monad.Maybe[int] `form:"page"`
+		Page       monad.Maybe[int]      `form:"page"`
+		Message    monad.Maybe[string]   `form:"message"`
+		ResourceID monad.Maybe[string]   `form:"resource_id"`
+		State      monad.Maybe[JobState] `form:"state"`
 	}
 
 	// Adapter used to store scheduled jobs. Could be anything from a database to a file or
@@ -63,16 +133,25 @@ type (
 	ScheduledJobsStore interface {
 		Setup() error                                                                        // Setup the store
 		Create(context.Context, Schedulable, CreateOptions) error                            // Create a new scheduled job
-		Delete(context.Context, string) error                                                // Try to delete a job from the store
+		Delete(context.Context, string) error                                                // Try to delete a cancellable or dead job from the store
+		Requeue(context.Context, string) error                                               // Requeue a dead job, resetting its attempts
 		GetAllJobs(context.Context, GetJobsFilters) (storage.Paginated[ScheduledJob], error) // Retrieve all jobs from the store
-		GetNextPendingJobs(context.Context) ([]ScheduledJob, error)                          // Get the next pending jobs to be dispatched
-		Retry(context.Context, ScheduledJob, error) error                                    // Retry the given job with the given reason
-		Done(context.Context, ScheduledJob) error                                            // Mark the given job as done
+		GetJobByID(context.Context, string) (ScheduledJob, error)                            // Retrieve a job with its decoded message
+		GetNextPendingJobs(context.Context) ([]ScheduledJob, error)                          // Claim the next pending jobs to be dispatched
+		GetClaimedMessages(context.Context, string) ([]Request, error)                       // Retrieve messages with the given name of jobs claimed by any instance
+		RequestCancel(context.Context, Schedulable) error                                    // Remove pending jobs for the same message name and resource id and flag running ones as cancelled
+		Heartbeat(context.Context) ([]string, error)                                         // Renew the lease of jobs claimed by this instance and returns those flagged as cancelled
+		Started(context.Context, ScheduledJob, string) error                                 // Mark the given job as being processed by the given worker group
+		Retry(context.Context, ScheduledJob, error, time.Duration) error                     // Retry the given job with the given reason after the given delay
+		Fail(context.Context, ScheduledJob, error) error                                     // Move the given job to the dead-letter queue
+		Done(context.Context, ScheduledJob) error                                            // Mark the given job as done, keeping it in the history
+		Purge(context.Context, time.Time) error                                              // Remove done and dead jobs finished before the given time
 	}
 
 	defaultScheduler struct {
 		bus                    Dispatcher
 		pollInterval           time.Duration
+		retention              time.Duration
 		logger                 log.Logger
 		store                  ScheduledJobsStore
 		started                bool
@@ -80,31 +159,39 @@ type (
 		exitGroup              sync.WaitGroup
 		groups                 []*workerGroup
 		messageNameToWorkerIdx map[string]int
+		runningMu              sync.Mutex
+		running                map[string]map[string]context.CancelFunc // Cancel functions of running jobs by message key and job id
 	}
 
 	// Represents a worker group configuration used by a scheduler to spawn the appropriate
 	// workers.
 	WorkerGroup struct {
+		Name     string   // Name of the group recorded on processed jobs
 		Size     int      // Number of workers to start
 		Messages []string // List of message names to handle, mandatory
 	}
 
 	workerGroup struct {
+		name string
 		jobs chan ScheduledJob
 		size int
 	}
 )
 
+func (r UndecodableRequest) Name_() string { return r.Name }
+
 // Builds up a new scheduler used to queue messages for later dispatching using the
-// provided adapter.
-func NewScheduler(adapter ScheduledJobsStore, log log.Logger, bus Dispatcher, pollInterval time.Duration, groups ...WorkerGroup) RunnableScheduler {
+// provided adapter. Done and dead jobs are kept for the given retention, 0 to keep them forever.
+func NewScheduler(adapter ScheduledJobsStore, log log.Logger, bus Dispatcher, pollInterval, retention time.Duration, groups ...WorkerGroup) RunnableScheduler {
 	s := &defaultScheduler{
 		bus:                    bus,
 		pollInterval:           pollInterval,
+		retention:              retention,
 		logger:                 log,
 		store:                  adapter,
 		groups:                 make([]*workerGroup, len(groups)),
 		messageNameToWorkerIdx: make(map[string]int),
+		running:                make(map[string]map[string]context.CancelFunc),
 	}
 
 	for i, g := range groups {
@@ -113,7 +200,12 @@ func NewScheduler(adapter ScheduledJobsStore, log log.Logger, bus Dispatcher, po
 			g.Size = 1
 		}
 
+		if g.Name == "" {
+			g.Name = strconv.Itoa(i)
+		}
+
 		s.groups[i] = &workerGroup{
+			name: g.Name,
 			jobs: make(chan ScheduledJob),
 			size: g.Size,
 		}
@@ -126,6 +218,8 @@ func NewScheduler(adapter ScheduledJobsStore, log log.Logger, bus Dispatcher, po
 	return s
 }
 
+func (JobQueueChanged) Name_() string { return "bus.event.job_queue_changed" }
+
 func (s *defaultScheduler) Queue(
 	ctx context.Context,
 	msg Schedulable,
@@ -137,7 +231,42 @@ func (s *defaultScheduler) Queue(
 		opt(&opts)
 	}
 
-	return s.store.Create(ctx, msg, opts)
+	if opts.Retry == (RetryPolicy{}) {
+		opts.Retry = DefaultRetryPolicy
+	}
+
+	if err := s.store.Create(ctx, msg, opts); err != nil {
+		return err
+	}
+
+	return s.bus.Notify(ctx, JobQueueChanged{
+		Message: msg.Name_(),
+		Change:  JobChangeQueued,
+	})
+}
+
+func (s *defaultScheduler) Cancel(ctx context.Context, msg Schedulable) error {
+	// Persist the request so the instance owning the jobs, which may not be this one, will
+	// cancel them on its next heartbeat.
+	if err := s.store.RequestCancel(ctx, msg); err != nil {
+		return err
+	}
+
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	for _, cancel := range s.running[runningKey(msg)] {
+		cancel()
+	}
+
+	return s.bus.Notify(ctx, JobQueueChanged{
+		Message: msg.Name_(),
+		Change:  JobChangeCancelled,
+	})
+}
+
+func (s *defaultScheduler) Claimed(ctx context.Context, name string) ([]Request, error) {
+	return s.store.GetClaimedMessages(ctx, name)
 }
 
 func (s *defaultScheduler) Start() {
@@ -149,6 +278,7 @@ func (s *defaultScheduler) Start() {
 
 	s.startGroupRunners()
 	s.startPolling()
+	s.startHeartbeat()
 }
 
 func (s *defaultScheduler) Stop() {
@@ -180,8 +310,9 @@ func (s *defaultScheduler) run(fn func(<-chan bool)) {
 func (s *defaultScheduler) startPolling() {
 	s.run(func(done <-chan bool) {
 		var (
-			delay   time.Duration
-			lastRun time.Time = time.Now()
+			delay     time.Duration
+			lastRun   time.Time = time.Now()
+			lastPurge time.Time
 		)
 
 		for {
@@ -195,6 +326,15 @@ func (s *defaultScheduler) startPolling() {
 
 			lastRun = time.Now()
 
+			if s.retention > 0 && lastRun.Sub(lastPurge) >= purgeInterval {
+				lastPurge = lastRun
+
+				if err := s.store.Purge(context.Background(), lastRun.Add(-s.retention)); err != nil {
+					s.logger.Errorw("error while purging finished jobs",
+						"error", err)
+				}
+			}
+
 			jobs, err := s.store.GetNextPendingJobs(context.Background())
 
 			if err != nil {
@@ -204,6 +344,11 @@ func (s *defaultScheduler) startPolling() {
 			}
 
 			for _, job := range jobs {
+				if undecodable, isUndecodable := job.Message().(UndecodableRequest); isUndecodable {
+					s.deadLetter(context.Background(), job, undecodable.Err)
+					continue
+				}
+
 				idx, handled := s.messageNameToWorkerIdx[job.Message().Name_()]
 
 				if !handled {
@@ -217,6 +362,82 @@ func (s *defaultScheduler) startPolling() {
 	})
 }
 
+// Renew leases of claimed jobs, running or waiting for a free worker, so other instances
+// sharing the same store do not consider them abandoned. Running jobs whose cancellation has
+// been requested, maybe by another instance, are cancelled at the same time.
+func (s *defaultScheduler) startHeartbeat() {
+	s.run(func(done <-chan bool) {
+		ticker := time.NewTicker(heartbeatInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				cancelled, err := s.store.Heartbeat(context.Background())
+
+				if err != nil {
+					s.logger.Errorw("error while renewing jobs lease",
+						"error", err)
+					continue
+				}
+
+				s.cancelRunning(cancelled)
+			}
+		}
+	})
+}
+
+// Builds a cancellable context for the given job and keep track of it so it can be
+// cancelled with Cancel. The returned function must be called once the job has been processed.
+func (s *defaultScheduler) track(ctx context.Context, job ScheduledJob) (context.Context, func()) {
+	msg, isSchedulable := job.Message().(Schedulable)
+
+	if !isSchedulable {
+		return ctx, func() {}
+	}
+
+	key := runningKey(msg)
+	jobCtx, cancel := context.WithCancel(ctx)
+
+	s.runningMu.Lock()
+	if s.running[key] == nil {
+		s.running[key] = make(map[string]context.CancelFunc)
+	}
+	s.running[key][job.ID()] = cancel
+	s.runningMu.Unlock()
+
+	return jobCtx, func() {
+		s.runningMu.Lock()
+		delete(s.running[key], job.ID())
+		if len(s.running[key]) == 0 {
+			delete(s.running, key)
+		}
+		s.runningMu.Unlock()
+
+		cancel()
+	}
+}
+
+// Cancel the context of running jobs with the given ids, if any.
+func (s *defaultScheduler) cancelRunning(ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+
+	for _, jobs := range s.running {
+		for _, id := range ids {
+			if cancel, found := jobs[id]; found {
+				cancel()
+			}
+		}
+	}
+}
+
 func (s *defaultScheduler) handleJobReturn(ctx context.Context, job ScheduledJob, err error) {
 	if err == nil {
 		if err = s.store.Done(ctx, job); err != nil {
@@ -224,20 +445,70 @@ func (s *defaultScheduler) handleJobReturn(ctx context.Context, job ScheduledJob
 				"job", job.ID(),
 				"name", job.Message().Name_(),
 				"error", err)
+			return
 		}
+
+		s.notifyJobChanged(ctx, job, JobChangeDone)
+		return
+	}
+
+	attempts := job.Attempts() + 1
+	policy := job.RetryPolicy()
+
+	if policy.IsExhausted(attempts) {
+		s.logger.Errorw("job has exhausted its attempts, moving it to the dead-letter queue",
+			"job", job.ID(),
+			"name", job.Message().Name_(),
+			"attempts", attempts,
+			"error", err)
+
+		s.deadLetter(ctx, job, err)
 		return
 	}
 
+	delay := policy.DelayFor(attempts)
+
 	s.logger.Warnw("error while processing job, it will be retried later",
 		"job", job.ID(),
 		"name", job.Message().Name_(),
+		"attempts", attempts,
+		"delay", delay,
 		"error", err)
 
-	if err = s.store.Retry(ctx, job, err); err != nil {
+	if err = s.store.Retry(ctx, job, err, delay); err != nil {
 		s.logger.Errorw("error while retrying job",
 			"job", job.ID(),
 			"name", job.Message().Name_(),
 			"error", err)
+		return
+	}
+
+	s.notifyJobChanged(ctx, job, JobChangeRetried)
+}
+
+// Move the given job to the dead-letter queue with the given reason.
+func (s *defaultScheduler) deadLetter(ctx context.Context, job ScheduledJob, reason error) {
+	if err := s.store.Fail(ctx, job, reason); err != nil {
+		s.logger.Errorw("error while moving job to the dead-letter queue",
+			"job", job.ID(),
+			"name", job.Message().Name_(),
+			"error", err)
+		return
+	}
+
+	s.notifyJobChanged(ctx, job, JobChangeDead)
+}
+
+func (s *defaultScheduler) notifyJobChanged(ctx context.Context, job ScheduledJob, change JobChange) {
+	if err := s.bus.Notify(ctx, JobQueueChanged{
+		JobID:   job.ID(),
+		Message: job.Message().Name_(),
+		Change:  change,
+	}); err != nil {
+		s.logger.Errorw("error while notifying job change",
+			"job", job.ID(),
+			"name", job.Message().Name_(),
+			"error", err)
 	}
 }
 
@@ -252,7 +523,17 @@ func (s *defaultScheduler) startGroupRunners() {
 						return
 					case job := <-group.jobs:
 						ctx := context.Background()
-						_, err := s.bus.Send(ctx, job.Message())
+
+						if err := s.store.Started(ctx, job, group.name); err != nil {
+							s.logger.Errorw("error while marking job as started",
+								"job", job.ID(),
+								"name", job.Message().Name_(),
+								"error", err)
+						}
+
+						jobCtx, done := s.track(ctx, job)
+						_, err := s.bus.Send(jobCtx, job.Message())
+						done()
 
 						s.handleJobReturn(ctx, job, err)
 					}
@@ -277,3 +558,25 @@ func WithPolicy(policy JobPolicy) JobOptions {
 		o.Policy = policy
 	}
 }
+
+// Delay the first dispatch of the job until the given time.
+func WithNotBefore(t time.Time) JobOptions {
+	return func(o *CreateOptions) {
+		o.NotBefore.Set(t)
+	}
+}
+
+// Limit the number of jobs sharing the given key which could be processed at the same time.
+// Contrary to groups, up to max jobs of this key may run concurrently.
+func WithConcurrencyLimit(key string, max int) JobOptions {
+	return func(o *CreateOptions) {
+		o.Concurrency.Set(ConcurrencyLimit{
+			Key: key,
+			Max: max,
+		})
+	}
+}
+
+func runningKey(msg Schedulable) string {
+	return msg.Name_() + ":" + msg.ResourceID()
+}
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/YuukanOO/seelf/pkg/assert"
 	"github.com/YuukanOO/seelf/pkg/bus"
@@ -27,7 +28,7 @@ func TestScheduler(t *testing.T) {
 
 	t.Run("should queue and handle the job return appropriately", func(t *testing.T) {
 		adapter := &adapter{}
-		scheduler := bus.NewScheduler(adapter, logger, b, 0, bus.WorkerGroup{
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0, bus.WorkerGroup{
 			Size:     4,
 			Messages: []string{returnCommand{}.Name_()},
 		})
@@ -70,6 +71,169 @@ func TestScheduler(t *testing.T) {
 
 		assert.Equal(t, 3, adapter.retried[2].id)
 		assert.ErrorIs(t, bus.ErrNoHandlerRegistered, adapter.retried[2].err)
+		assert.True(t, adapter.retried[2].delay >= 13*time.Second && adapter.retried[2].delay <= 17*time.Second)
+	})
+
+	t.Run("should record the worker group processing a job", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0,
+			bus.WorkerGroup{
+				Name:     "named",
+				Size:     1,
+				Messages: []string{returnCommand{}.Name_()},
+			},
+			bus.WorkerGroup{
+				Size:     1,
+				Messages: []string{blockingCommand{}.Name_()},
+			},
+		)
+
+		assert.Nil(t, scheduler.Queue(context.Background(), returnCommand{}))
+
+		scheduler.Start()
+		adapter.wait()
+		scheduler.Stop()
+
+		assert.HasLength(t, 1, adapter.done)
+		assert.Equal(t, "named", adapter.done[0].workerGroup)
+	})
+
+	t.Run("should purge finished jobs older than the retention", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, time.Hour, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		assert.Nil(t, scheduler.Queue(context.Background(), returnCommand{}))
+
+		start := time.Now()
+		scheduler.Start()
+		adapter.wait()
+		scheduler.Stop()
+
+		assert.HasLength(t, 1, adapter.purged, "should purge only once per purge interval")
+		assert.True(t, !adapter.purged[0].Before(start.Add(-time.Hour)) && adapter.purged[0].Before(time.Now().Add(-time.Hour)))
+	})
+
+	t.Run("should move jobs which have exhausted their attempts to the dead-letter queue", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		innerErr := errors.New("some error")
+
+		assert.Nil(t, scheduler.Queue(context.Background(), returnCommand{err: innerErr}, bus.WithRetry(bus.RetryPolicy{
+			MaxAttempts: 1,
+			Delay:       time.Second,
+		})))
+
+		scheduler.Start()
+		adapter.wait()
+		scheduler.Stop()
+
+		assert.HasLength(t, 0, adapter.retried)
+		assert.HasLength(t, 1, adapter.failed)
+		assert.ErrorIs(t, innerErr, adapter.failed[0].err)
+	})
+
+	t.Run("should move jobs whose message could not be decoded to the dead-letter queue", func(t *testing.T) {
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		assert.Nil(t, scheduler.Queue(context.Background(), returnCommand{}))
+		adapter.wg.Add(1)
+		adapter.jobs = append(adapter.jobs, &job{
+			id:    len(adapter.jobs),
+			msg:   bus.UndecodableRequest{Name: returnCommand{}.Name_(), Err: storage.ErrUnsupportedVersion},
+			retry: bus.DefaultRetryPolicy,
+		})
+
+		scheduler.Start()
+		adapter.wait()
+		scheduler.Stop()
+
+		assert.HasLength(t, 1, adapter.done)
+		assert.HasLength(t, 0, adapter.retried)
+		assert.HasLength(t, 1, adapter.failed)
+		assert.Equal(t, 1, adapter.failed[0].id)
+		assert.ErrorIs(t, storage.ErrUnsupportedVersion, adapter.failed[0].err)
+	})
+
+	t.Run("should be able to cancel running jobs", func(t *testing.T) {
+		started := make(chan bool)
+		bus.Register(b, func(ctx context.Context, cmd blockingCommand) (bus.UnitType, error) {
+			started <- true
+			<-ctx.Done()
+			return bus.Unit, ctx.Err()
+		})
+
+		adapter := &adapter{}
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{blockingCommand{}.Name_()},
+		})
+
+		assert.Nil(t, scheduler.Queue(context.Background(), blockingCommand{id: "1"}))
+
+		scheduler.Start()
+		<-started
+
+		assert.Nil(t, scheduler.Cancel(context.Background(), blockingCommand{id: "2"}))
+		assert.Nil(t, scheduler.Cancel(context.Background(), blockingCommand{id: "1"}))
+
+		adapter.wait()
+		scheduler.Stop()
+
+		assert.HasLength(t, 1, adapter.retried)
+		assert.ErrorIs(t, context.Canceled, adapter.retried[0].err)
+		assert.DeepEqual(t, []bus.Schedulable{blockingCommand{id: "2"}, blockingCommand{id: "1"}}, adapter.cancelRequests,
+			"should persist cancellation requests for jobs running on other instances")
+	})
+
+	t.Run("should notify queue changes", func(t *testing.T) {
+		var (
+			mu      sync.Mutex
+			changes []bus.JobQueueChanged
+			adapter = &adapter{}
+		)
+
+		bus.On(b, func(_ context.Context, evt bus.JobQueueChanged) error {
+			mu.Lock()
+			defer mu.Unlock()
+			changes = append(changes, evt)
+			return nil
+		})
+
+		scheduler := bus.NewScheduler(adapter, logger, b, 0, 0, bus.WorkerGroup{
+			Size:     1,
+			Messages: []string{returnCommand{}.Name_()},
+		})
+
+		assert.Nil(t, scheduler.Queue(context.Background(), returnCommand{}))
+
+		scheduler.Start()
+		adapter.wait()
+		scheduler.Stop()
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		assert.HasLength(t, 2, changes)
+		assert.Equal(t, bus.JobQueueChanged{
+			Message: returnCommand{}.Name_(),
+			Change:  bus.JobChangeQueued,
+		}, changes[0])
+		assert.Equal(t, bus.JobQueueChanged{
+			JobID:   "0",
+			Message: returnCommand{}.Name_(),
+			Change:  bus.JobChangeDone,
+		}, changes[1])
 	})
 }
 
@@ -86,6 +250,10 @@ type (
 		policy        bus.JobPolicy
 		err           error
 		preserveOrder bool
+		workerGroup   string
+		attempts      int
+		retry         bus.RetryPolicy
+		delay         time.Duration
 	}
 
 	adapter struct {
@@ -93,6 +261,10 @@ type (
 		jobs    []*job
 		done    []*job
 		retried []*job
+		failed  []*job
+		purged  []time.Time
+		// Messages whose cancellation has been requested
+		cancelRequests []bus.Schedulable
 	}
 
 	returnCommand struct {
@@ -100,14 +272,25 @@ type (
 
 		err error
 	}
+
+	blockingCommand struct {
+		bus.Command[bus.UnitType]
+
+		id string
+	}
 )
 
 func (r returnCommand) Name_() string      { return "returnCommand" }
 func (r returnCommand) ResourceID() string { return "" }
 
-func (j *job) ID() string            { return strconv.Itoa(j.id) }
-func (j *job) Message() bus.Request  { return j.msg }
-func (j *job) Policy() bus.JobPolicy { return j.policy }
+func (c blockingCommand) Name_() string      { return "blockingCommand" }
+func (c blockingCommand) ResourceID() string { return c.id }
+
+func (j *job) ID() string                   { return strconv.Itoa(j.id) }
+func (j *job) Message() bus.Request         { return j.msg }
+func (j *job) Policy() bus.JobPolicy        { return j.policy }
+func (j *job) Attempts() int                { return j.attempts }
+func (j *job) RetryPolicy() bus.RetryPolicy { return j.retry }
 
 func (a *adapter) Setup() error { return nil }
 
@@ -115,13 +298,18 @@ func (a *adapter) GetAllJobs(context.Context, bus.GetJobsFilters) (storage.Pagin
 	return storage.Paginated[bus.ScheduledJob]{}, nil
 }
 
+func (a *adapter) GetJobByID(context.Context, string) (bus.ScheduledJob, error) {
+	return nil, nil
+}
+
 func (a *adapter) Create(_ context.Context, msg bus.Schedulable, opts bus.CreateOptions) error {
 	a.wg.Add(1)
-	a.jobs = append(a.jobs, &job{id: len(a.jobs), msg: msg, policy: opts.Policy})
+	a.jobs = append(a.jobs, &job{id: len(a.jobs), msg: msg, policy: opts.Policy, retry: opts.Retry})
 	return nil
 }
 
-func (a *adapter) Delete(context.Context, string) error { return nil }
+func (a *adapter) Delete(context.Context, string) error  { return nil }
+func (a *adapter) Requeue(context.Context, string) error { return nil }
 
 func (a *adapter) wait() {
 	a.wg.Wait()
@@ -139,10 +327,32 @@ func (a *adapter) GetNextPendingJobs(context.Context) ([]bus.ScheduledJob, error
 	return j, nil
 }
 
-func (a *adapter) Retry(_ context.Context, j bus.ScheduledJob, jobErr error) error {
+func (a *adapter) GetClaimedMessages(context.Context, string) ([]bus.Request, error) {
+	return nil, nil
+}
+
+func (a *adapter) RequestCancel(_ context.Context, msg bus.Schedulable) error {
+	a.cancelRequests = append(a.cancelRequests, msg)
+	return nil
+}
+
+func (a *adapter) Heartbeat(context.Context) ([]string, error) { return nil, nil }
+
+func (a *adapter) Started(_ context.Context, j bus.ScheduledJob, workerGroup string) error {
+	j.(*job).workerGroup = workerGroup
+	return nil
+}
+
+func (a *adapter) Purge(_ context.Context, before time.Time) error {
+	a.purged = append(a.purged, before)
+	return nil
+}
+
+func (a *adapter) Retry(_ context.Context, j bus.ScheduledJob, jobErr error, delay time.Duration) error {
 	defer a.wg.Done()
 	jo := j.(*job)
 	jo.err = jobErr
+	jo.delay = delay
 	jo.preserveOrder = flag.IsSet(j.Policy(), bus.JobPolicyRetryPreserveOrder)
 
 	a.retried = append(a.retried, jo)
@@ -150,6 +360,15 @@ func (a *adapter) Retry(_ context.Context, j bus.ScheduledJob, jobErr error) err
 
 }
 
+func (a *adapter) Fail(_ context.Context, j bus.ScheduledJob, jobErr error) error {
+	defer a.wg.Done()
+	jo := j.(*job)
+	jo.err = jobErr
+
+	a.failed = append(a.failed, jo)
+	return nil
+}
+
 func (a *adapter) Done(_ context.Context, j bus.ScheduledJob) error {
 	defer a.wg.Done()
 	a.done = append(a.done, j.(*job))
@@ -0,0 +1,156 @@
+package sqldb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type (
+	// Prometheus collector exposing the state of the scheduled jobs queue, read from
+	// the database each time metrics are gathered.
+	metricsCollector struct {
+		db       *sqldb.Database
+		jobs     *prometheus.Desc
+		retrying *prometheus.Desc
+	}
+
+	// Store decorator recording how many attempts jobs took once they have been processed.
+	attemptsObserver struct {
+		bus.ScheduledJobsStore
+		attempts *prometheus.HistogramVec
+		retries  *prometheus.CounterVec
+	}
+
+	queueStats struct {
+		messageName string
+		pending     int
+		running     int
+		retrying    int
+		dead        int
+	}
+)
+
+// Builds a new collector exposing the scheduled jobs queue depth and retries per message name.
+func NewMetricsCollector(db *sqldb.Database) prometheus.Collector {
+	return &metricsCollector{
+		db: db,
+		jobs: prometheus.NewDesc(
+			"seelf_scheduler_jobs",
+			"Number of scheduled jobs per message name and state (pending, running or dead).",
+			[]string{"message", "state"}, nil,
+		),
+		retrying: prometheus.NewDesc(
+			"seelf_scheduler_jobs_retrying",
+			"Number of scheduled jobs which have failed at least once and are waiting to be retried.",
+			[]string{"message"}, nil,
+		),
+	}
+}
+
+// Wraps the given store to expose the number of attempts jobs took to be processed and
+// the number of retries per message name. They are observed once the store has persisted
+// the outcome so a job is only counted by the instance which has processed it.
+func ObserveAttempts(registerer prometheus.Registerer, store bus.ScheduledJobsStore) (bus.ScheduledJobsStore, error) {
+	o := &attemptsObserver{
+		ScheduledJobsStore: store,
+		attempts: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "seelf_scheduler_job_attempts",
+			Help:    "Number of attempts taken by processed jobs per message name and result (done or dead).",
+			Buckets: []float64{1, 2, 3, 5, 10, 20},
+		}, []string{"message", "result"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "seelf_scheduler_job_retries_total",
+			Help: "Number of times jobs have failed and have been scheduled for a retry per message name.",
+		}, []string{"message"}),
+	}
+
+	return o, errors.Join(
+		registerer.Register(o.attempts),
+		registerer.Register(o.retries),
+	)
+}
+
+func (o *attemptsObserver) Retry(ctx context.Context, j bus.ScheduledJob, jobErr error, delay time.Duration) error {
+	if err := o.ScheduledJobsStore.Retry(ctx, j, jobErr, delay); err != nil {
+		return err
+	}
+
+	o.retries.WithLabelValues(j.Message().Name_()).Inc()
+
+	return nil
+}
+
+func (o *attemptsObserver) Fail(ctx context.Context, j bus.ScheduledJob, jobErr error) error {
+	if err := o.ScheduledJobsStore.Fail(ctx, j, jobErr); err != nil {
+		return err
+	}
+
+	o.observe(j, "dead")
+
+	return nil
+}
+
+func (o *attemptsObserver) Done(ctx context.Context, j bus.ScheduledJob) error {
+	if err := o.ScheduledJobsStore.Done(ctx, j); err != nil {
+		return err
+	}
+
+	o.observe(j, "done")
+
+	return nil
+}
+
+// Attempts of the job only account for previous failures, the last one must be added.
+func (o *attemptsObserver) observe(j bus.ScheduledJob, result string) {
+	o.attempts.WithLabelValues(j.Message().Name_(), result).Observe(float64(j.Attempts() + 1))
+}
+
+func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.jobs
+	ch <- c.retrying
+}
+
+func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
+	stats, err := builder.
+		Query[queueStats](`
+			SELECT
+				message_name
+				,SUM(CASE WHEN retrieved = false AND dead = false AND done = false THEN 1 ELSE 0 END)
+				,SUM(CASE WHEN retrieved = true THEN 1 ELSE 0 END)
+				,SUM(CASE WHEN errcode IS NOT NULL AND retrieved = false AND dead = false AND done = false THEN 1 ELSE 0 END)
+				,SUM(CASE WHEN dead = true THEN 1 ELSE 0 END)
+			FROM scheduled_jobs
+			GROUP BY message_name`).
+		All(c.db, context.Background(), queueStatsMapper)
+
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.jobs, err)
+		return
+	}
+
+	for _, s := range stats {
+		ch <- prometheus.MustNewConstMetric(c.jobs, prometheus.GaugeValue, float64(s.pending), s.messageName, "pending")
+		ch <- prometheus.MustNewConstMetric(c.jobs, prometheus.GaugeValue, float64(s.running), s.messageName, "running")
+		ch <- prometheus.MustNewConstMetric(c.jobs, prometheus.GaugeValue, float64(s.dead), s.messageName, "dead")
+		ch <- prometheus.MustNewConstMetric(c.retrying, prometheus.GaugeValue, float64(s.retrying), s.messageName)
+	}
+}
+
+func queueStatsMapper(scanner storage.Scanner) (s queueStats, err error) {
+	err = scanner.Scan(
+		&s.messageName,
+		&s.pending,
+		&s.running,
+		&s.retrying,
+		&s.dead,
+	)
+
+	return s, err
+}
@@ -0,0 +1,126 @@
+package sqldb_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	bussqldb "github.com/YuukanOO/seelf/pkg/bus/sqldb"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+func Test_ObserveAttempts(t *testing.T) {
+
+	arrange := func(tb testing.TB, storeErr error) (bus.ScheduledJobsStore, *prometheus.Registry) {
+		registry := prometheus.NewRegistry()
+		store, err := bussqldb.ObserveAttempts(registry, &outcomeStore{err: storeErr})
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		return store, registry
+	}
+
+	t.Run("should observe the attempts of processed jobs", func(t *testing.T) {
+		store, registry := arrange(t, nil)
+
+		assert.Nil(t, store.Done(context.Background(), &attemptedJob{attempts: 0}))
+		assert.Nil(t, store.Done(context.Background(), &attemptedJob{attempts: 2}))
+		assert.Nil(t, store.Fail(context.Background(), &attemptedJob{attempts: 4}, errors.New("some error")))
+
+		assert.DeepEqual(t, map[string]float64{
+			"done": 4, // 1 + 3 attempts
+			"dead": 5,
+		}, attemptsSum(t, registry))
+	})
+
+	t.Run("should count retries", func(t *testing.T) {
+		store, registry := arrange(t, nil)
+
+		assert.Nil(t, store.Retry(context.Background(), &attemptedJob{}, errors.New("some error"), time.Second))
+		assert.Nil(t, store.Retry(context.Background(), &attemptedJob{}, errors.New("some error"), time.Second))
+
+		assert.Equal(t, 2, retriesCount(t, registry))
+	})
+
+	t.Run("should not observe anything if the store has failed", func(t *testing.T) {
+		storeErr := errors.New("store error")
+		store, registry := arrange(t, storeErr)
+
+		assert.ErrorIs(t, storeErr, store.Done(context.Background(), &attemptedJob{}))
+		assert.ErrorIs(t, storeErr, store.Retry(context.Background(), &attemptedJob{}, errors.New("some error"), time.Second))
+
+		assert.DeepEqual(t, map[string]float64{}, attemptsSum(t, registry))
+		assert.Equal(t, 0, retriesCount(t, registry))
+	})
+}
+
+func attemptsSum(t testing.TB, registry *prometheus.Registry) map[string]float64 {
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+
+	result := make(map[string]float64)
+
+	for _, family := range families {
+		if family.GetName() != "seelf_scheduler_job_attempts" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" {
+					result[label.GetValue()] += metric.GetHistogram().GetSampleSum()
+				}
+			}
+		}
+	}
+
+	return result
+}
+
+func retriesCount(t testing.TB, registry *prometheus.Registry) int {
+	families, err := registry.Gather()
+	assert.Nil(t, err)
+
+	var result int
+
+	for _, family := range families {
+		if family.GetName() != "seelf_scheduler_job_retries_total" {
+			continue
+		}
+
+		for _, metric := range family.GetMetric() {
+			result += int(metric.GetCounter().GetValue())
+		}
+	}
+
+	return result
+}
+
+type (
+	outcomeStore struct {
+		bus.ScheduledJobsStore
+		err error
+	}
+
+	attemptedJob struct {
+		attempts int
+	}
+)
+
+func (s *outcomeStore) Retry(context.Context, bus.ScheduledJob, error, time.Duration) error {
+	return s.err
+}
+
+func (s *outcomeStore) Fail(context.Context, bus.ScheduledJob, error) error { return s.err }
+func (s *outcomeStore) Done(context.Context, bus.ScheduledJob) error        { return s.err }
+
+func (j *attemptedJob) ID() string                   { return "job" }
+func (j *attemptedJob) Message() bus.Request         { return upgradedCommand{} }
+func (j *attemptedJob) Policy() bus.JobPolicy        { return 0 }
+func (j *attemptedJob) Attempts() int                { return j.attempts }
+func (j *attemptedJob) RetryPolicy() bus.RetryPolicy { return bus.RetryPolicy{} }
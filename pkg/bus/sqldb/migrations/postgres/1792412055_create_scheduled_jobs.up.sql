@@ -0,0 +1,28 @@
+CREATE TABLE scheduled_jobs (
+    id TEXT NOT NULL
+    ,resource_id TEXT NOT NULL
+    ,"group" TEXT NOT NULL
+    ,message_name TEXT NOT NULL
+    ,message_data TEXT NOT NULL
+    ,queued_at TIMESTAMP NOT NULL
+    ,not_before TIMESTAMP NOT NULL
+    ,policy INTEGER NOT NULL
+    ,errcode TEXT NULL
+    ,retrieved BOOLEAN NOT NULL DEFAULT false
+    ,attempts INTEGER NOT NULL DEFAULT 0
+    ,max_attempts INTEGER NOT NULL DEFAULT 0
+    ,retry_delay INTEGER NOT NULL DEFAULT 15
+    ,retry_max_delay INTEGER NOT NULL DEFAULT 15
+    ,dead BOOLEAN NOT NULL DEFAULT false
+    ,done BOOLEAN NOT NULL DEFAULT false
+    ,worker_group TEXT NULL
+    ,started_at TIMESTAMP NULL
+    ,finished_at TIMESTAMP NULL
+    ,concurrency_key TEXT NULL
+    ,concurrency_limit INTEGER NULL
+    ,CONSTRAINT pk_scheduled_jobs PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_scheduled_jobs_resource_id ON scheduled_jobs(resource_id);
+CREATE INDEX idx_scheduled_jobs_group ON scheduled_jobs("group");
+CREATE INDEX idx_scheduled_jobs_finished_at ON scheduled_jobs(finished_at);
@@ -0,0 +1,17 @@
+CREATE TABLE outbox_messages (
+    id TEXT NOT NULL
+    ,subscriber TEXT NOT NULL
+    ,message_name TEXT NOT NULL
+    ,message_data TEXT NOT NULL
+    ,queued_at TIMESTAMP NOT NULL
+    ,not_before TIMESTAMP NOT NULL
+    ,errcode TEXT NULL
+    ,attempts INTEGER NOT NULL DEFAULT 0
+    ,dead BOOLEAN NOT NULL DEFAULT false
+    ,finished_at TIMESTAMP NULL
+    ,lease_owner TEXT NULL
+    ,lease_expires_at TIMESTAMP NULL
+    ,CONSTRAINT pk_outbox_messages PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_outbox_messages_not_before ON outbox_messages(not_before);
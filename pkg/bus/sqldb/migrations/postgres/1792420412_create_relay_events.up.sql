@@ -0,0 +1,9 @@
+CREATE TABLE relay_events (
+    id BIGINT GENERATED ALWAYS AS IDENTITY
+    ,name TEXT NOT NULL
+    ,data TEXT NOT NULL
+    ,published_at TIMESTAMP NOT NULL
+    ,CONSTRAINT pk_relay_events PRIMARY KEY(id)
+);
+
+CREATE INDEX idx_relay_events_published_at ON relay_events(published_at);
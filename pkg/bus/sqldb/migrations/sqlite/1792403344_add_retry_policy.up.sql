@@ -0,0 +1,5 @@
+ALTER TABLE scheduled_jobs ADD attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduled_jobs ADD max_attempts INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE scheduled_jobs ADD retry_delay INTEGER NOT NULL DEFAULT 15;
+ALTER TABLE scheduled_jobs ADD retry_max_delay INTEGER NOT NULL DEFAULT 15;
+ALTER TABLE scheduled_jobs ADD dead BOOLEAN NOT NULL DEFAULT false;
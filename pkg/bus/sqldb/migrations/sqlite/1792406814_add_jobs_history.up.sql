@@ -0,0 +1,9 @@
+ALTER TABLE scheduled_jobs ADD done BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE scheduled_jobs ADD worker_group TEXT NULL;
+ALTER TABLE scheduled_jobs ADD started_at DATETIME NULL;
+ALTER TABLE scheduled_jobs ADD finished_at DATETIME NULL;
+
+-- Dead jobs have no known end date, use the last time they were planned so they can be purged.
+UPDATE scheduled_jobs SET finished_at = not_before WHERE dead = true;
+
+CREATE INDEX idx_scheduled_jobs_finished_at ON scheduled_jobs(finished_at);
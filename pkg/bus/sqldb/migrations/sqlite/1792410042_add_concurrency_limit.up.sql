@@ -0,0 +1,2 @@
+ALTER TABLE scheduled_jobs ADD concurrency_key TEXT NULL;
+ALTER TABLE scheduled_jobs ADD concurrency_limit INTEGER NULL;
@@ -0,0 +1,5 @@
+ALTER TABLE scheduled_jobs ADD lease_owner TEXT NULL;
+ALTER TABLE scheduled_jobs ADD lease_expires_at DATETIME NULL;
+
+-- Jobs retrieved before leases existed are released since no one owns them anymore.
+UPDATE scheduled_jobs SET retrieved = false WHERE retrieved = true;
@@ -0,0 +1,2 @@
+-- Jobs queued before messages were versioned are in the first version.
+ALTER TABLE scheduled_jobs ADD message_version INTEGER NOT NULL DEFAULT 0;
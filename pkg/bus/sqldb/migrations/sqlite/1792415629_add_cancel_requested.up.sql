@@ -0,0 +1,3 @@
+-- Set when the cancellation of a running job has been requested so the instance owning it
+-- could cancel it on its next heartbeat.
+ALTER TABLE scheduled_jobs ADD cancel_requested BOOLEAN NOT NULL DEFAULT false;
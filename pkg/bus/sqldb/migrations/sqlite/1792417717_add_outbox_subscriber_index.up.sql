@@ -0,0 +1,2 @@
+-- Messages are delivered in order to each subscriber.
+CREATE INDEX idx_outbox_messages_subscriber ON outbox_messages(subscriber, queued_at);
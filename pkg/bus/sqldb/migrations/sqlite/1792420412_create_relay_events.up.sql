@@ -0,0 +1,8 @@
+CREATE TABLE relay_events (
+    id INTEGER PRIMARY KEY AUTOINCREMENT
+    ,name TEXT NOT NULL
+    ,data TEXT NOT NULL
+    ,published_at DATETIME NOT NULL
+);
+
+CREATE INDEX idx_relay_events_published_at ON relay_events(published_at);
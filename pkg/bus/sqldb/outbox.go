@@ -0,0 +1,247 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+// Maximum number of messages claimed at once by an instance.
+const outboxBatchSize = 50
+
+type (
+	outboxMessage struct {
+		id         string
+		subscriber string
+		name       string
+		data       string
+		attempts   int
+		queuedAt   time.Time
+	}
+
+	outboxStore struct {
+		db    *sqldb.Database
+		owner string
+		lease time.Duration
+	}
+)
+
+func (m *outboxMessage) ID() string         { return m.id }
+func (m *outboxMessage) Subscriber() string { return m.subscriber }
+func (m *outboxMessage) Name() string       { return m.name }
+func (m *outboxMessage) Data() string       { return m.data }
+func (m *outboxMessage) Attempts() int      { return m.attempts }
+
+// Builds a new adapter persisting outbox messages in the given database, in the transaction
+// of the context if any. Like jobs, messages are claimed on behalf of the given owner for the
+// given lease duration, which must be renewed with Heartbeat.
+func NewOutboxStore(db *sqldb.Database, owner string, lease time.Duration) bus.OutboxStore {
+	return &outboxStore{db, owner, lease}
+}
+
+// Setup the outbox adapter, migrate the database and release messages claimed by a previous
+// run of this same instance.
+// You MUST call this method at the application startup.
+func (s *outboxStore) Setup() error {
+	if err := s.db.Migrate(Migrations); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(context.Background(), `
+		UPDATE outbox_messages
+		SET lease_expires_at = NULL
+		WHERE lease_owner = ?`, s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Create(ctx context.Context, subscriber string, signal bus.Signal) error {
+	now := time.Now().UTC()
+	data, err := storage.ValueJSON(signal)
+
+	if err != nil {
+		return err
+	}
+
+	return builder.
+		Insert("outbox_messages", builder.Values{
+			"id":           id.New[string](),
+			"subscriber":   subscriber,
+			"message_name": signal.Name_(),
+			"message_data": data,
+			"queued_at":    now,
+			"not_before":   now,
+		}).
+		Exec(s.db, ctx)
+}
+
+func (s *outboxStore) GetNextPendingMessages(ctx context.Context) (messages []bus.OutboxMessage, finalErr error) {
+	now := time.Now().UTC()
+	lock := ""
+
+	// SQLite locks the whole database for writes so there is no need for it there.
+	if s.db.Dialect() == postgres.Dialect {
+		lock = "FOR UPDATE SKIP LOCKED"
+
+		var (
+			tx      *sql.Tx
+			created bool
+		)
+
+		ctx, tx, created = s.db.WithTransaction(ctx)
+
+		defer func() {
+			if !created {
+				return
+			}
+
+			if finalErr != nil {
+				if err := tx.Rollback(); err != nil {
+					finalErr = err
+				}
+			} else {
+				finalErr = tx.Commit()
+			}
+		}()
+
+		// Pollers are serialized per subscriber, otherwise two of them could each claim a part of
+		// the messages of a subscriber and deliver them concurrently. The claim runs in its own
+		// statement to see messages claimed by the previous lock holder.
+		if _, finalErr = s.db.ExecContext(ctx, `
+			SELECT pg_advisory_xact_lock(hashtext('outbox:' || subscriber))
+			FROM (
+				SELECT DISTINCT subscriber
+				FROM outbox_messages
+				WHERE dead = false AND not_before <= ?
+				ORDER BY subscriber
+			) s`, now); finalErr != nil {
+			return
+		}
+	}
+
+	// A message with an expired lease has been claimed by an instance which has probably died.
+	// Messages are delivered in order to each subscriber so one is held back while an earlier
+	// message of the same subscriber is waiting to be retried or being delivered elsewhere.
+	messages, finalErr = builder.
+		Query[bus.OutboxMessage](`
+			UPDATE outbox_messages
+			SET
+				lease_owner = ?
+				,lease_expires_at = ?
+			WHERE id IN (
+				SELECT id
+				FROM outbox_messages m
+				WHERE m.dead = false AND m.not_before <= ? AND (m.lease_expires_at IS NULL OR m.lease_expires_at < ?)
+					AND NOT EXISTS (
+						SELECT 1
+						FROM outbox_messages e
+						WHERE e.subscriber = m.subscriber AND e.dead = false AND e.queued_at < m.queued_at
+							AND (e.not_before > ? OR e.lease_expires_at >= ?)
+					)
+				ORDER BY m.queued_at
+				LIMIT ?
+				`+lock+`
+			)
+			RETURNING id, subscriber, message_name, message_data, attempts, queued_at`,
+		s.owner, now.Add(s.lease), now, now, now, now, outboxBatchSize).
+		All(s.db, ctx, outboxMessageMapper)
+
+	if finalErr != nil {
+		return nil, finalErr
+	}
+
+	// Rows returned by an UPDATE are not ordered
+	slices.SortFunc(messages, func(a, b bus.OutboxMessage) int {
+		return a.(*outboxMessage).queuedAt.Compare(b.(*outboxMessage).queuedAt)
+	})
+
+	return messages, nil
+}
+
+func (s *outboxStore) Heartbeat(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox_messages
+		SET lease_expires_at = ?
+		WHERE `+ownedMessageCondition, time.Now().UTC().Add(s.lease), s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Delivered(ctx context.Context, msg bus.OutboxMessage) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM outbox_messages
+		WHERE id = ? AND `+ownedMessageCondition, msg.ID(), s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Retry(ctx context.Context, msg bus.OutboxMessage, deliveryErr error, delay time.Duration) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox_messages
+		SET
+			errcode = ?
+			,attempts = attempts + 1
+			,not_before = ?
+			,lease_expires_at = NULL
+		WHERE id = ? AND `+ownedMessageCondition, deliveryErr.Error(), time.Now().UTC().Add(delay), msg.ID(), s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Fail(ctx context.Context, msg bus.OutboxMessage, deliveryErr error) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox_messages
+		SET
+			errcode = ?
+			,attempts = attempts + 1
+			,dead = true
+			,finished_at = ?
+			,lease_expires_at = NULL
+		WHERE id = ? AND `+ownedMessageCondition, deliveryErr.Error(), time.Now().UTC(), msg.ID(), s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Release(ctx context.Context, msg bus.OutboxMessage) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE outbox_messages
+		SET lease_expires_at = NULL
+		WHERE id = ? AND `+ownedMessageCondition, msg.ID(), s.owner)
+
+	return err
+}
+
+func (s *outboxStore) Purge(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM outbox_messages
+		WHERE dead = true AND finished_at < ?`, before.UTC())
+
+	return err
+}
+
+// Condition used when ending a delivery to make sure the lease has not been lost in the meantime,
+// in which case the message will be delivered again.
+const ownedMessageCondition = "lease_expires_at IS NOT NULL AND lease_owner = ?"
+
+func outboxMessageMapper(scanner storage.Scanner) (bus.OutboxMessage, error) {
+	var m outboxMessage
+
+	err := scanner.Scan(
+		&m.id,
+		&m.subscriber,
+		&m.name,
+		&m.data,
+		&m.attempts,
+		&m.queuedAt,
+	)
+
+	return &m, err
+}
@@ -0,0 +1,205 @@
+package sqldb_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	bussqldb "github.com/YuukanOO/seelf/pkg/bus/sqldb"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+func Test_OutboxStore(t *testing.T) {
+	arrange := func(tb testing.TB) (*sqldb.Database, bus.OutboxStore) {
+		dialect, dsn := sqlite.Dialect, "file:"+filepath.Join(tb.TempDir(), "seelf.db")
+
+		if url := os.Getenv("SEELF_TEST_DATABASE_URL"); url != "" {
+			dialect, dsn = postgres.Dialect, postgres.PrepareTestSchema(tb, url)
+		}
+
+		db, err := sqldb.Open(dialect, dsn, must.Panic(log.NewLogger()), memory.NewBus())
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		tb.Cleanup(func() { db.Close() })
+
+		store := bussqldb.NewOutboxStore(db, "one", time.Minute)
+
+		if err = store.Setup(); err != nil {
+			tb.Fatal(err)
+		}
+
+		return db, store
+	}
+
+	t.Run("should only persist messages if the surrounding transaction is committed", func(t *testing.T) {
+		db, store := arrange(t)
+
+		ctx, tx, _ := db.WithTransaction(context.Background())
+		assert.Nil(t, store.Create(ctx, "subscriber", signal{Value: "rollbacked"}))
+		assert.Nil(t, tx.Rollback())
+
+		ctx, tx, _ = db.WithTransaction(context.Background())
+		assert.Nil(t, store.Create(ctx, "subscriber", signal{Value: "committed"}))
+		assert.Nil(t, tx.Commit())
+
+		messages, err := store.GetNextPendingMessages(context.Background())
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, messages)
+		assert.Equal(t, "subscriber", messages[0].Subscriber())
+		assert.Equal(t, signal{}.Name_(), messages[0].Name())
+		assert.Equal(t, `{"value":"committed"}`, messages[0].Data())
+		assert.Equal(t, 0, messages[0].Attempts())
+	})
+
+	t.Run("should not claim messages already claimed until their lease expires", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewOutboxStore(db, "two", time.Minute)
+		expired := bussqldb.NewOutboxStore(db, "three", -time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{}))
+
+		messages, err := expired.GetNextPendingMessages(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, messages)
+
+		messages, err = store.GetNextPendingMessages(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, messages)
+
+		messages, err = other.GetNextPendingMessages(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, messages)
+	})
+
+	t.Run("should remove delivered messages", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewOutboxStore(db, "two", -time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{}))
+
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, messages)
+
+		// Not owned by this instance so it should be left untouched
+		assert.Nil(t, other.Delivered(context.Background(), messages[0]))
+		assert.Nil(t, store.Delivered(context.Background(), messages[0]))
+
+		messages, _ = other.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 0, messages)
+	})
+
+	t.Run("should deliver retried messages again once the delay has passed", func(t *testing.T) {
+		_, store := arrange(t)
+		deliveryErr := errors.New("some error")
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{}))
+
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.Nil(t, store.Retry(context.Background(), messages[0], deliveryErr, time.Hour))
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 0, messages)
+
+		assert.Nil(t, store.Create(context.Background(), "other", signal{}))
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+		assert.Nil(t, store.Retry(context.Background(), messages[0], deliveryErr, 0))
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, messages)
+		assert.Equal(t, 1, messages[0].Attempts())
+	})
+
+	t.Run("should hold back messages of a subscriber while an earlier one is waiting to be retried or delivered", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewOutboxStore(db, "two", time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{Value: "first"}))
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, messages)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{Value: "second"}))
+		assert.Nil(t, store.Create(context.Background(), "other", signal{Value: "other"}))
+
+		pending, _ := other.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, pending)
+		assert.Equal(t, "other", pending[0].Subscriber())
+
+		assert.Nil(t, store.Retry(context.Background(), messages[0], errors.New("some error"), time.Hour))
+
+		pending, _ = other.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 0, pending)
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 0, messages)
+	})
+
+	t.Run("should claim messages of a subscriber in order once an earlier one can be retried", func(t *testing.T) {
+		_, store := arrange(t)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{Value: "first"}))
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{Value: "second"}))
+		assert.Nil(t, store.Retry(context.Background(), messages[0], errors.New("some error"), 0))
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+
+		assert.HasLength(t, 2, messages)
+		assert.Equal(t, `{"value":"first"}`, messages[0].Data())
+		assert.Equal(t, `{"value":"second"}`, messages[1].Data())
+	})
+
+	t.Run("should not deliver failed messages anymore and purge them", func(t *testing.T) {
+		db, store := arrange(t)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{}))
+
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.Nil(t, store.Fail(context.Background(), messages[0], errors.New("some error")))
+
+		messages, _ = store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 0, messages)
+
+		assert.Nil(t, store.Purge(context.Background(), time.Now().Add(time.Minute)))
+
+		var count int
+		assert.Nil(t, db.QueryRowContext(context.Background(), "SELECT COUNT(*) FROM outbox_messages").Scan(&count))
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("should release messages claimed by a previous run of the same instance on setup", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewOutboxStore(db, "two", time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), "subscriber", signal{}))
+		messages, _ := store.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, messages)
+
+		assert.Nil(t, bussqldb.NewOutboxStore(db, "one", time.Minute).Setup())
+
+		messages, _ = other.GetNextPendingMessages(context.Background())
+		assert.HasLength(t, 1, messages)
+	})
+}
+
+type signal struct {
+	bus.Notification
+
+	Value string `json:"value"`
+}
+
+func (signal) Name_() string { return "signal" }
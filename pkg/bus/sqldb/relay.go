@@ -0,0 +1,136 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+// Maximum number of events retrieved at once by an instance.
+const relayBatchSize = 100
+
+type relayStore struct {
+	db *sqldb.Database
+}
+
+// Builds a new adapter sharing relayed events between instances through the given database.
+func NewRelayStore(db *sqldb.Database) relay.Store {
+	return &relayStore{db}
+}
+
+// Setup the relay adapter and migrate the database.
+// You MUST call this method at the application startup.
+func (s *relayStore) Setup() error {
+	return s.db.Migrate(Migrations)
+}
+
+func (s *relayStore) Append(ctx context.Context, evt relay.Event) (finalErr error) {
+	data, err := storage.ValueJSON(evt.Data)
+
+	if err != nil {
+		return err
+	}
+
+	// Instances read events after the last position they have seen so appends are serialized,
+	// otherwise an event could be committed after one with a greater identifier and be missed.
+	if s.db.Dialect() == postgres.Dialect {
+		var (
+			tx      *sql.Tx
+			created bool
+		)
+
+		ctx, tx, created = s.db.WithTransaction(ctx)
+
+		defer func() {
+			if !created {
+				return
+			}
+
+			if finalErr != nil {
+				if err := tx.Rollback(); err != nil {
+					finalErr = err
+				}
+			} else {
+				finalErr = tx.Commit()
+			}
+		}()
+
+		if _, finalErr = s.db.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('relay_events'))`); finalErr != nil {
+			return
+		}
+	}
+
+	return builder.
+		Insert("relay_events", builder.Values{
+			"name":         evt.Name,
+			"data":         data,
+			"published_at": time.Now().UTC(),
+		}).
+		Exec(s.db, ctx)
+}
+
+func (s *relayStore) Cursor(ctx context.Context) (cursor int64, err error) {
+	err = s.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(id), 0) FROM relay_events`).Scan(&cursor)
+	return cursor, err
+}
+
+func (s *relayStore) GetAfter(ctx context.Context, cursor int64) ([]relay.Event, int64, error) {
+	events, err := builder.
+		Query[relayEvent](`
+			SELECT id, name, data
+			FROM relay_events
+			WHERE id > ?
+			ORDER BY id
+			LIMIT ?`, cursor, relayBatchSize).
+		All(s.db, ctx, relayEventMapper)
+
+	if err != nil {
+		return nil, cursor, err
+	}
+
+	result := make([]relay.Event, len(events))
+
+	for i, evt := range events {
+		result[i] = evt.Event
+		cursor = evt.id
+	}
+
+	return result, cursor, nil
+}
+
+func (s *relayStore) Purge(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM relay_events
+		WHERE published_at < ?`, before.UTC())
+
+	return err
+}
+
+type relayEvent struct {
+	relay.Event
+	id int64
+}
+
+func relayEventMapper(scanner storage.Scanner) (relayEvent, error) {
+	var (
+		evt  relayEvent
+		data string
+	)
+
+	err := scanner.Scan(
+		&evt.id,
+		&evt.Name,
+		&data,
+	)
+
+	evt.Data = json.RawMessage(data)
+
+	return evt, err
+}
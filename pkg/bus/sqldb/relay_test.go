@@ -0,0 +1,84 @@
+package sqldb_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/bus/relay"
+	bussqldb "github.com/YuukanOO/seelf/pkg/bus/sqldb"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+func Test_RelayStore(t *testing.T) {
+	arrange := func(tb testing.TB) relay.Store {
+		dialect, dsn := sqlite.Dialect, "file:"+filepath.Join(tb.TempDir(), "seelf.db")
+
+		if url := os.Getenv("SEELF_TEST_DATABASE_URL"); url != "" {
+			dialect, dsn = postgres.Dialect, postgres.PrepareTestSchema(tb, url)
+		}
+
+		db, err := sqldb.Open(dialect, dsn, must.Panic(log.NewLogger()), memory.NewBus())
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		tb.Cleanup(func() { db.Close() })
+
+		store := bussqldb.NewRelayStore(db)
+
+		if err = store.Setup(); err != nil {
+			tb.Fatal(err)
+		}
+
+		return store
+	}
+
+	t.Run("should retrieve events appended after the given position", func(t *testing.T) {
+		store := arrange(t)
+
+		assert.Nil(t, store.Append(context.Background(), relay.Event{Name: "first", Data: "one"}))
+
+		cursor, err := store.Cursor(context.Background())
+		assert.Nil(t, err)
+
+		assert.Nil(t, store.Append(context.Background(), relay.Event{Name: "second", Data: map[string]string{"id": "two"}}))
+		assert.Nil(t, store.Append(context.Background(), relay.Event{Name: "third", Data: nil}))
+
+		events, next, err := store.GetAfter(context.Background(), cursor)
+
+		assert.Nil(t, err)
+		assert.DeepEqual(t, []relay.Event{
+			{Name: "second", Data: json.RawMessage(`{"id":"two"}`)},
+			{Name: "third", Data: json.RawMessage(`null`)},
+		}, events)
+
+		events, last, err := store.GetAfter(context.Background(), next)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, events)
+		assert.Equal(t, next, last)
+	})
+
+	t.Run("should purge events published before the given time", func(t *testing.T) {
+		store := arrange(t)
+
+		assert.Nil(t, store.Append(context.Background(), relay.Event{Name: "an_event"}))
+		assert.Nil(t, store.Purge(context.Background(), time.Now().Add(time.Minute)))
+
+		events, _, err := store.GetAfter(context.Background(), 0)
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, events)
+	})
+}
@@ -0,0 +1,697 @@
+package sqldb
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/flag"
+	"github.com/YuukanOO/seelf/pkg/id"
+	"github.com/YuukanOO/seelf/pkg/monad"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+)
+
+var (
+	//go:embed migrations/*/*.sql
+	migrations embed.FS
+
+	Migrations = sqldb.NewMigrationsModule("scheduler", "migrations", migrations)
+)
+
+type (
+	job struct {
+		id       string
+		msg      bus.Request
+		policy   bus.JobPolicy
+		attempts int
+		retry    bus.RetryPolicy
+	}
+
+	jobQuery struct {
+		JobID          string                 `json:"id"`
+		ResourceID     string                 `json:"resource_id"`
+		Group          string                 `json:"group"`
+		MessageName    string                 `json:"message_name"`
+		MessageData    string                 `json:"message_data"`
+		MessageVersion int                    `json:"message_version"`   // Version of the message when it has been queued
+		Payload        bus.Request            `json:"payload,omitempty"` // Decoded message, only set when retrieving a single job
+		QueuedAt       time.Time              `json:"queued_at"`
+		NotBefore      time.Time              `json:"not_before"`
+		ErrorCode      monad.Maybe[string]    `json:"error_code"`
+		JobPolicy      bus.JobPolicy          `json:"policy"`
+		Retrieved      bool                   `json:"retrieved"`
+		JobAttempts    int                    `json:"attempts"`
+		MaxAttempts    int                    `json:"max_attempts"`
+		Dead           bool                   `json:"dead"`
+		State          bus.JobState           `json:"state"`
+		WorkerGroup    monad.Maybe[string]    `json:"worker_group"`
+		Instance       monad.Maybe[string]    `json:"instance"` // Instance which has claimed the job last
+		StartedAt      monad.Maybe[time.Time] `json:"started_at"`
+		FinishedAt     monad.Maybe[time.Time] `json:"finished_at"`
+	}
+
+	store struct {
+		db    *sqldb.Database
+		owner string
+		lease time.Duration
+	}
+)
+
+func (j *job) ID() string                   { return j.id }
+func (j *job) Message() bus.Request         { return j.msg }
+func (j *job) Policy() bus.JobPolicy        { return j.policy }
+func (j *job) Attempts() int                { return j.attempts }
+func (j *job) RetryPolicy() bus.RetryPolicy { return j.retry }
+
+func (j *jobQuery) ID() string            { return j.JobID }
+func (j *jobQuery) Message() bus.Request  { return j.Payload } // Only set when retrieved with GetJobByID
+func (j *jobQuery) Policy() bus.JobPolicy { return j.JobPolicy }
+func (j *jobQuery) Attempts() int         { return j.JobAttempts }
+func (j *jobQuery) RetryPolicy() bus.RetryPolicy {
+	return bus.RetryPolicy{MaxAttempts: j.MaxAttempts}
+}
+
+// Builds a new adapter persisting jobs in the given database.
+// For it to work, commands must be (de)serializable using the bus.Marshallable mapper. Messages
+// are stored alongside their current version so upcasters registered on it can migrate them if
+// their shape changes before they are processed.
+//
+// Jobs are claimed on behalf of the given owner, which identifies this instance, for the
+// given lease duration. The lease must be renewed with Heartbeat or the job will be released
+// and picked up again, by this instance or another one sharing the same database.
+func NewScheduledJobsStore(db *sqldb.Database, owner string, lease time.Duration) bus.ScheduledJobsStore {
+	return &store{db, owner, lease}
+}
+
+// Setup the scheduler adapter, migrate the database and release jobs claimed by a previous
+// run of this same instance so they will be picked up next time GetNextPendingJobs is called.
+// Jobs claimed by other instances are left untouched, they will be released when their lease expires.
+// You MUST call this method at the application startup.
+func (s *store) Setup() error {
+	if err := s.db.Migrate(Migrations); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(context.Background(), `
+		UPDATE scheduled_jobs
+		SET
+			retrieved = false
+			,lease_expires_at = NULL
+		WHERE retrieved = true AND lease_owner = ?`, s.owner)
+
+	return err
+}
+
+func (s *store) Create(
+	ctx context.Context,
+	msg bus.Schedulable,
+	options bus.CreateOptions,
+) error {
+	jobId := id.New[string]()
+	now := time.Now().UTC()
+	msgValue, err := storage.ValueJSON(msg)
+
+	if err != nil {
+		return err
+	}
+
+	var (
+		msgName          = msg.Name_()
+		msgVersion       = bus.Marshallable.Version(msgName)
+		resourceId       = msg.ResourceID()
+		concurrencyKey   monad.Maybe[string]
+		concurrencyLimit monad.Maybe[int]
+	)
+
+	if concurrency, isSet := options.Concurrency.TryGet(); isSet {
+		concurrencyKey.Set(concurrency.Key)
+		concurrencyLimit.Set(concurrency.Max)
+	}
+
+	// Could not use the ON CONFLICT here :'(
+	if flag.IsSet(options.Policy, bus.JobPolicyMerge) {
+		result, err := s.db.ExecContext(ctx, `
+			UPDATE scheduled_jobs
+			SET
+				message_data = ?
+				,message_version = ?
+			WHERE id = (
+				SELECT id
+				FROM scheduled_jobs
+				WHERE resource_id = ? AND message_name = ? AND retrieved = false AND done = false AND dead = false
+				ORDER BY queued_at
+				LIMIT 1
+			)`, msgValue, msgVersion, resourceId, msgName)
+
+		if err != nil {
+			return err
+		}
+
+		if affected, _ := result.RowsAffected(); affected > 0 {
+			return nil
+		}
+	}
+
+	return builder.
+		Insert("scheduled_jobs", builder.Values{
+			"id":                jobId,
+			"resource_id":       resourceId,
+			`"group"`:           options.Group.Get(jobId), // Default to the job id if no group set
+			"message_name":      msgName,
+			"message_data":      msgValue,
+			"message_version":   msgVersion,
+			"queued_at":         now,
+			"not_before":        options.NotBefore.Get(now).UTC(),
+			"policy":            options.Policy,
+			"retrieved":         false,
+			"max_attempts":      options.Retry.MaxAttempts,
+			"retry_delay":       int(options.Retry.Delay.Seconds()),
+			"retry_max_delay":   int(options.Retry.MaxDelay.Seconds()),
+			"concurrency_key":   concurrencyKey,
+			"concurrency_limit": concurrencyLimit,
+		}).
+		Exec(s.db, ctx)
+}
+
+func (s *store) Delete(ctx context.Context, id string) error {
+	r, err := s.db.ExecContext(ctx, `
+		DELETE FROM scheduled_jobs
+		WHERE id = ? AND ((policy & ?) != 0 OR dead = true OR done = true)`,
+		id, bus.JobPolicyCancellable)
+
+	return affectedOrNotFound(r, err)
+}
+
+func (s *store) Requeue(ctx context.Context, id string) error {
+	r, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			dead = false
+			,attempts = 0
+			,not_before = ?
+			,finished_at = NULL
+		WHERE id = ? AND dead = true`, time.Now().UTC(), id)
+
+	return affectedOrNotFound(r, err)
+}
+
+func (s *store) GetAllJobs(ctx context.Context, filters bus.GetJobsFilters) (storage.Paginated[bus.ScheduledJob], error) {
+	return builder.
+		Select[bus.ScheduledJob](jobQuerySelect).
+		F("FROM scheduled_jobs WHERE TRUE").
+		S(
+			builder.MaybeValue(filters.Message, "AND message_name = ?"),
+			builder.MaybeValue(filters.ResourceID, "AND resource_id = ?"),
+			builder.MaybeValue(filters.State, "AND "+jobStateExpression+" = ?"),
+		).
+		F("ORDER BY queued_at").
+		Paginate(s.db, ctx, jobQueryMapper, filters.Page.Get(1), 10)
+}
+
+func (s *store) GetJobByID(ctx context.Context, id string) (bus.ScheduledJob, error) {
+	return builder.
+		Query[bus.ScheduledJob]("SELECT "+jobQuerySelect+" FROM scheduled_jobs WHERE id = ?", id).
+		One(s.db, ctx, func(scanner storage.Scanner) (bus.ScheduledJob, error) {
+			j, err := jobQueryMapper(scanner)
+
+			if err != nil {
+				return j, err
+			}
+
+			// Message may not be known anymore, the raw data is still returned in this case
+			q := j.(*jobQuery)
+			q.Payload, _ = bus.Marshallable.FromVersion(q.MessageName, q.MessageVersion, q.MessageData)
+
+			return q, nil
+		})
+}
+
+func (s *store) GetNextPendingJobs(ctx context.Context) (jobs []bus.ScheduledJob, finalErr error) {
+	now := time.Now().UTC()
+	expiresAt := now.Add(s.lease)
+
+	// Release jobs whose owner has not renewed the lease in time, it has probably died.
+	if _, finalErr = s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			retrieved = false
+			,lease_expires_at = NULL
+		WHERE retrieved = true AND lease_expires_at < ?`, now); finalErr != nil {
+		return
+	}
+
+	if s.db.Dialect() != postgres.Dialect {
+		return builder.
+			Query[bus.ScheduledJob](sqliteNextPendingJobsQuery, s.owner, expiresAt, now, bus.JobPolicyWaitForOthersResourceID).
+			All(s.db, ctx, jobMapper)
+	}
+
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	// Row locks do not prevent two pollers from counting the same running jobs of a concurrency
+	// key and exceeding its limit together, so pollers are serialized per pending key. Keys are
+	// locked in order to avoid deadlocks and the claim runs in its own statement to see jobs
+	// claimed by the previous lock holder.
+	if _, finalErr = s.db.ExecContext(ctx, `
+		SELECT pg_advisory_xact_lock(hashtext(concurrency_key))
+		FROM (
+			SELECT DISTINCT concurrency_key
+			FROM scheduled_jobs
+			WHERE concurrency_key IS NOT NULL AND retrieved = false AND dead = false AND done = false AND not_before <= ?
+			ORDER BY concurrency_key
+		) k`, now); finalErr != nil {
+		return
+	}
+
+	return builder.
+		Query[bus.ScheduledJob](postgresNextPendingJobsQuery, now, bus.JobPolicyWaitForOthersResourceID, s.owner, expiresAt).
+		All(s.db, ctx, jobMapper)
+}
+
+func (s *store) GetClaimedMessages(ctx context.Context, name string) ([]bus.Request, error) {
+	rows, err := builder.
+		Query[claimedMessage](`
+			SELECT message_data, message_version
+			FROM scheduled_jobs
+			WHERE message_name = ? AND retrieved = true`, name).
+		All(s.db, ctx, claimedMessageMapper)
+
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]bus.Request, 0, len(rows))
+
+	for _, row := range rows {
+		// Undecodable messages will be moved to the dead-letter queue by their owner
+		if msg, err := bus.Marshallable.FromVersion(name, row.version, row.data); err == nil {
+			messages = append(messages, msg)
+		}
+	}
+
+	return messages, nil
+}
+
+func (s *store) RequestCancel(ctx context.Context, msg bus.Schedulable) error {
+	// Jobs not claimed yet could be removed right away, they will never be processed
+	if _, err := s.db.ExecContext(ctx, `
+		DELETE FROM scheduled_jobs
+		WHERE resource_id = ? AND message_name = ? AND retrieved = false AND done = false AND dead = false`,
+		msg.ResourceID(), msg.Name_()); err != nil {
+		return err
+	}
+
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET cancel_requested = true
+		WHERE resource_id = ? AND message_name = ? AND retrieved = true`, msg.ResourceID(), msg.Name_())
+
+	return err
+}
+
+func (s *store) Heartbeat(ctx context.Context) ([]string, error) {
+	if _, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET lease_expires_at = ?
+		WHERE `+ownedJobCondition, time.Now().UTC().Add(s.lease), s.owner); err != nil {
+		return nil, err
+	}
+
+	// The flag is not reset here since a claimed job may still be waiting for a free worker,
+	// it will be the next time the job is claimed.
+	return builder.
+		Query[string](`
+			SELECT id
+			FROM scheduled_jobs
+			WHERE cancel_requested = true AND `+ownedJobCondition, s.owner).
+		ExtractAll(s.db, ctx)
+}
+
+func (s *store) Started(ctx context.Context, j bus.ScheduledJob, workerGroup string) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			worker_group = ?
+			,started_at = ?
+		WHERE id = ?`, workerGroup, time.Now().UTC(), j.ID())
+
+	return err
+}
+
+func (s *store) Retry(ctx context.Context, j bus.ScheduledJob, jobErr error, delay time.Duration) (finalErr error) {
+	seconds := max(int(delay.Seconds()), 1)
+	now := time.Now().UTC()
+
+	// If we don't need to preserve the order of related tasks, we simply update the job to queue it again
+	// in the future.
+	if !flag.IsSet(j.Policy(), bus.JobPolicyRetryPreserveOrder) {
+		_, err := s.db.ExecContext(ctx, `
+			UPDATE scheduled_jobs
+			SET
+				errcode = ?
+				,attempts = attempts + 1
+				,not_before = ?
+				,retrieved = false
+				,lease_expires_at = NULL
+			WHERE id = ? AND `+ownedJobCondition, jobErr.Error(), now.Add(time.Duration(seconds)*time.Second), j.ID(), s.owner,
+		)
+
+		return err
+	}
+
+	// If instead, we want all jobs sharing the same group to be updated all at once,
+	// we should make sure to set all of them in the future by a specific amount to preserve
+	// the job order. Jobs already planned later than that (see bus.WithNotBefore) are left untouched.
+	ctx, tx, created := s.db.WithTransaction(ctx)
+
+	defer func() {
+		if !created {
+			return
+		}
+
+		if finalErr != nil {
+			if err := tx.Rollback(); err != nil {
+				finalErr = err
+			}
+		} else {
+			finalErr = tx.Commit()
+		}
+	}()
+
+	related, finalErr := builder.
+		Query[relatedJob](`
+			SELECT id, not_before
+			FROM scheduled_jobs
+			WHERE "group" = (SELECT "group" FROM scheduled_jobs WHERE id = ?) AND dead = false AND done = false
+			ORDER BY not_before`, j.ID()).
+		All(s.db, ctx, relatedJobMapper)
+
+	if finalErr != nil {
+		return
+	}
+
+	for i, r := range related {
+		notBefore := now.Add(time.Duration(seconds+i) * time.Second)
+
+		if r.notBefore.After(notBefore) {
+			notBefore = r.notBefore
+		}
+
+		if r.id != j.ID() {
+			_, finalErr = s.db.ExecContext(ctx, `
+				UPDATE scheduled_jobs
+				SET
+					not_before = ?
+					,retrieved = false
+				WHERE id = ?`, notBefore, r.id)
+		} else {
+			_, finalErr = s.db.ExecContext(ctx, `
+				UPDATE scheduled_jobs
+				SET
+					errcode = ?
+					,attempts = attempts + 1
+					,not_before = ?
+					,retrieved = false
+					,lease_expires_at = NULL
+				WHERE id = ? AND `+ownedJobCondition, jobErr.Error(), notBefore, r.id, s.owner)
+		}
+
+		if finalErr != nil {
+			return
+		}
+	}
+
+	return
+}
+
+func (s *store) Fail(ctx context.Context, j bus.ScheduledJob, jobErr error) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			errcode = ?
+			,attempts = attempts + 1
+			,dead = true
+			,retrieved = false
+			,lease_expires_at = NULL
+			,finished_at = ?
+		WHERE id = ? AND `+ownedJobCondition, jobErr.Error(), time.Now().UTC(), j.ID(), s.owner)
+
+	return err
+}
+
+func (s *store) Done(ctx context.Context, j bus.ScheduledJob) error {
+	_, err := s.db.ExecContext(ctx, `
+		UPDATE scheduled_jobs
+		SET
+			done = true
+			,retrieved = false
+			,lease_expires_at = NULL
+			,finished_at = ?
+		WHERE id = ? AND `+ownedJobCondition, time.Now().UTC(), j.ID(), s.owner)
+
+	return err
+}
+
+func (s *store) Purge(ctx context.Context, before time.Time) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM scheduled_jobs
+		WHERE (done = true OR dead = true) AND finished_at < ?`, before.UTC())
+
+	return err
+}
+
+// Condition used when ending a job to make sure its lease has not been lost in the meantime,
+// in which case the job has been released and will be processed again.
+const ownedJobCondition = "retrieved = true AND lease_owner = ?"
+
+// SQL expression computing the bus.JobState of a job.
+const jobStateExpression = `(CASE
+	WHEN done = true THEN 'done'
+	WHEN dead = true THEN 'dead'
+	WHEN retrieved = true THEN 'running'
+	WHEN attempts > 0 THEN 'retrying'
+	ELSE 'pending'
+END)`
+
+// Retrieve the next pending jobs on SQLite. The whole database is locked by the write
+// transaction so we can't retrieve the same job twice.
+// Candidates sharing a concurrency key are ranked so that, added to the jobs of this key
+// already running, no more than the limit are retrieved at once.
+const sqliteNextPendingJobsQuery = `
+	UPDATE scheduled_jobs
+	SET
+		retrieved = true
+		,cancel_requested = false
+		,lease_owner = ?
+		,lease_expires_at = ?
+	WHERE id IN (SELECT id FROM (
+		SELECT
+			id
+			,concurrency_key
+			,concurrency_limit
+			,ROW_NUMBER() OVER (PARTITION BY concurrency_key ORDER BY not_before) AS concurrency_rank
+		FROM (
+			SELECT id, concurrency_key, concurrency_limit, MIN(not_before) AS not_before FROM scheduled_jobs sj
+			WHERE
+				sj.retrieved = false
+				AND sj.dead = false
+				AND sj.done = false
+				AND sj.not_before <= ?
+				AND sj."group" NOT IN (SELECT DISTINCT "group" FROM scheduled_jobs WHERE retrieved = true)
+				AND (sj.policy & ? = 0 OR (SELECT COUNT(resource_id) FROM scheduled_jobs WHERE resource_id = sj.resource_id AND dead = false AND done = false) <= 1)
+				GROUP BY sj."group"
+		)
+	) c
+	WHERE c.concurrency_key IS NULL
+		OR c.concurrency_rank + (SELECT COUNT(id) FROM scheduled_jobs WHERE retrieved = true AND concurrency_key = c.concurrency_key) <= c.concurrency_limit
+	)
+	RETURNING id, message_name, message_data, message_version, policy, attempts, max_attempts, retry_delay, retry_max_delay`
+
+// Same as the SQLite one but rows are claimed with FOR UPDATE SKIP LOCKED so concurrent
+// pollers never retrieve the same job. The first job of each group is elected before locking
+// so a group whose head is being claimed elsewhere is skipped instead of handing out its next job.
+// The retrieved flag is checked again on the locked rows since they may have been claimed
+// and committed while we were waiting for our snapshot.
+const postgresNextPendingJobsQuery = `
+	WITH candidates AS (
+		SELECT DISTINCT ON (sj."group") sj.id, sj.concurrency_key, sj.concurrency_limit, sj.not_before
+		FROM scheduled_jobs sj
+		WHERE
+			sj.retrieved = false
+			AND sj.dead = false
+			AND sj.done = false
+			AND sj.not_before <= ?
+			AND NOT EXISTS (SELECT 1 FROM scheduled_jobs r WHERE r."group" = sj."group" AND r.retrieved = true)
+			AND (sj.policy & ? = 0 OR (SELECT COUNT(resource_id) FROM scheduled_jobs WHERE resource_id = sj.resource_id AND dead = false AND done = false) <= 1)
+		ORDER BY sj."group", sj.not_before, sj.id
+	), claimable AS (
+		SELECT c.id, c.concurrency_key, c.concurrency_limit, c.not_before
+		FROM candidates c
+		INNER JOIN scheduled_jobs sj ON sj.id = c.id
+		WHERE sj.retrieved = false AND sj.dead = false AND sj.done = false
+		FOR UPDATE OF sj SKIP LOCKED
+	), ranked AS (
+		SELECT
+			id
+			,concurrency_key
+			,concurrency_limit
+			,ROW_NUMBER() OVER (PARTITION BY concurrency_key ORDER BY not_before) AS concurrency_rank
+		FROM claimable
+	)
+	UPDATE scheduled_jobs
+	SET
+		retrieved = true
+		,cancel_requested = false
+		,lease_owner = ?
+		,lease_expires_at = ?
+	FROM ranked c
+	WHERE scheduled_jobs.id = c.id
+		AND (c.concurrency_key IS NULL
+			OR c.concurrency_rank + (SELECT COUNT(id) FROM scheduled_jobs WHERE retrieved = true AND concurrency_key = c.concurrency_key) <= c.concurrency_limit)
+	RETURNING scheduled_jobs.id, message_name, message_data, message_version, policy, attempts, max_attempts, retry_delay, retry_max_delay`
+
+const jobQuerySelect = `
+	id
+	,resource_id
+	,"group"
+	,message_name
+	,message_data
+	,message_version
+	,queued_at
+	,not_before
+	,errcode
+	,policy
+	,retrieved
+	,attempts
+	,max_attempts
+	,dead
+	,` + jobStateExpression + `
+	,worker_group
+	,lease_owner
+	,started_at
+	,finished_at
+`
+
+func jobMapper(scanner storage.Scanner) (bus.ScheduledJob, error) {
+	var (
+		j          job
+		msgName    string
+		msgData    string
+		msgVersion int
+	)
+
+	var retryDelay, retryMaxDelay int
+
+	err := scanner.Scan(
+		&j.id,
+		&msgName,
+		&msgData,
+		&msgVersion,
+		&j.policy,
+		&j.attempts,
+		&j.retry.MaxAttempts,
+		&retryDelay,
+		&retryMaxDelay,
+	)
+
+	if err != nil {
+		return &j, err
+	}
+
+	j.retry.Delay = time.Duration(retryDelay) * time.Second
+	j.retry.MaxDelay = time.Duration(retryMaxDelay) * time.Second
+
+	// A single undecodable row must not prevent other claimed jobs from being processed, so
+	// the scheduler receives a placeholder and moves it to the dead-letter queue.
+	if j.msg, err = bus.Marshallable.FromVersion(msgName, msgVersion, msgData); err != nil {
+		j.msg = bus.UndecodableRequest{Name: msgName, Err: err}
+	}
+
+	return &j, nil
+}
+
+func jobQueryMapper(scanner storage.Scanner) (bus.ScheduledJob, error) {
+	var j jobQuery
+
+	err := scanner.Scan(
+		&j.JobID,
+		&j.ResourceID,
+		&j.Group,
+		&j.MessageName,
+		&j.MessageData,
+		&j.MessageVersion,
+		&j.QueuedAt,
+		&j.NotBefore,
+		&j.ErrorCode,
+		&j.JobPolicy,
+		&j.Retrieved,
+		&j.JobAttempts,
+		&j.MaxAttempts,
+		&j.Dead,
+		&j.State,
+		&j.WorkerGroup,
+		&j.Instance,
+		&j.StartedAt,
+		&j.FinishedAt,
+	)
+
+	return &j, err
+}
+
+type relatedJob struct {
+	id        string
+	notBefore time.Time
+}
+
+func relatedJobMapper(scanner storage.Scanner) (r relatedJob, err error) {
+	err = scanner.Scan(&r.id, &r.notBefore)
+	return r, err
+}
+
+type claimedMessage struct {
+	data    string
+	version int
+}
+
+func claimedMessageMapper(scanner storage.Scanner) (m claimedMessage, err error) {
+	err = scanner.Scan(&m.data, &m.version)
+	return m, err
+}
+
+func affectedOrNotFound(r sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+
+	affected, err := r.RowsAffected()
+
+	if err != nil {
+		return err
+	}
+
+	if affected == 0 {
+		return apperr.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,276 @@
+package sqldb_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	bussqldb "github.com/YuukanOO/seelf/pkg/bus/sqldb"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+func Test_ScheduledJobsStore(t *testing.T) {
+	arrange := func(tb testing.TB) (*sqldb.Database, bus.ScheduledJobsStore) {
+		dialect, dsn := sqlite.Dialect, "file:"+filepath.Join(tb.TempDir(), "seelf.db")
+
+		if url := os.Getenv("SEELF_TEST_DATABASE_URL"); url != "" {
+			dialect, dsn = postgres.Dialect, postgres.PrepareTestSchema(tb, url)
+		}
+
+		db, err := sqldb.Open(dialect, dsn, must.Panic(log.NewLogger()), memory.NewBus())
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		tb.Cleanup(func() { db.Close() })
+
+		store := bussqldb.NewScheduledJobsStore(db, "one", time.Minute)
+
+		if err = store.Setup(); err != nil {
+			tb.Fatal(err)
+		}
+
+		return db, store
+	}
+
+	// Simulates a job queued by a previous release with the given version and raw payload.
+	queuedByPreviousRelease := func(tb testing.TB, db *sqldb.Database, id string, version int, data string) {
+		now := time.Now().UTC()
+
+		if err := builder.
+			Insert("scheduled_jobs", builder.Values{
+				"id":              id,
+				"resource_id":     id,
+				`"group"`:         id,
+				"message_name":    upgradedCommand{}.Name_(),
+				"message_data":    data,
+				"message_version": version,
+				"queued_at":       now,
+				"not_before":      now,
+				"policy":          0,
+			}).
+			Exec(db, context.Background()); err != nil {
+			tb.Fatal(err)
+		}
+	}
+
+	t.Run("should store messages with their current version", func(t *testing.T) {
+		_, store := arrange(t)
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "current", Priority: 5}, bus.CreateOptions{}))
+
+		jobs, err := store.GetAllJobs(context.Background(), bus.GetJobsFilters{})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, jobs.Data)
+
+		job, err := store.GetJobByID(context.Background(), jobs.Data[0].ID())
+
+		assert.Nil(t, err)
+		assert.Equal(t, upgradedCommand{Title: "current", Priority: 5}, job.Message().(upgradedCommand))
+	})
+
+	t.Run("should merge a job into the pending one but not into a dead one", func(t *testing.T) {
+		_, store := arrange(t)
+		merge := bus.CreateOptions{Policy: bus.JobPolicyMerge}
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "dead"}, merge))
+
+		jobs, err := store.GetNextPendingJobs(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, jobs)
+		assert.Nil(t, store.Fail(context.Background(), jobs[0], errors.New("some error")))
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "first"}, merge))
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "second"}, merge))
+
+		jobs, err = store.GetNextPendingJobs(context.Background())
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, jobs)
+		assert.Equal(t, upgradedCommand{Title: "second"}, jobs[0].Message().(upgradedCommand))
+	})
+
+	t.Run("should report running jobs whose cancellation has been requested to their owner", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewScheduledJobsStore(db, "two", time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "running"}, bus.CreateOptions{}))
+
+		jobs, err := store.GetNextPendingJobs(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, jobs)
+
+		cancelled, err := store.Heartbeat(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, cancelled)
+
+		assert.Nil(t, other.RequestCancel(context.Background(), upgradedCommand{}))
+
+		cancelled, err = other.Heartbeat(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, cancelled)
+
+		cancelled, err = store.Heartbeat(context.Background())
+		assert.Nil(t, err)
+		assert.DeepEqual(t, []string{jobs[0].ID()}, cancelled)
+	})
+
+	t.Run("should remove pending jobs when cancelled", func(t *testing.T) {
+		_, store := arrange(t)
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "pending"}, bus.CreateOptions{}))
+		assert.Nil(t, store.RequestCancel(context.Background(), upgradedCommand{}))
+
+		jobs, err := store.GetNextPendingJobs(context.Background())
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, jobs)
+	})
+
+	t.Run("should retrieve messages of jobs claimed by any instance", func(t *testing.T) {
+		db, store := arrange(t)
+		other := bussqldb.NewScheduledJobsStore(db, "two", time.Minute)
+
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "claimed"}, bus.CreateOptions{}))
+
+		messages, err := other.GetClaimedMessages(context.Background(), upgradedCommand{}.Name_())
+		assert.Nil(t, err)
+		assert.HasLength(t, 0, messages)
+
+		_, err = store.GetNextPendingJobs(context.Background())
+		assert.Nil(t, err)
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "pending"}, bus.CreateOptions{}))
+
+		messages, err = other.GetClaimedMessages(context.Background(), upgradedCommand{}.Name_())
+		assert.Nil(t, err)
+		assert.DeepEqual(t, []bus.Request{upgradedCommand{Title: "claimed"}}, messages)
+	})
+
+	t.Run("should upcast messages queued by a previous release when claiming them", func(t *testing.T) {
+		db, store := arrange(t)
+
+		queuedByPreviousRelease(t, db, "first", 0, `{"name":"first"}`)
+		queuedByPreviousRelease(t, db, "second", 1, `{"title":"second"}`)
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "current", Priority: 5}, bus.CreateOptions{}))
+
+		jobs, err := store.GetNextPendingJobs(context.Background())
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 3, jobs)
+
+		messages := make(map[string]upgradedCommand, len(jobs))
+
+		for _, job := range jobs {
+			msg := job.Message().(upgradedCommand)
+			messages[msg.Title] = msg
+		}
+
+		assert.Equal(t, upgradedCommand{Title: "first", Priority: 1}, messages["first"])
+		assert.Equal(t, upgradedCommand{Title: "second", Priority: 1}, messages["second"])
+		assert.Equal(t, upgradedCommand{Title: "current", Priority: 5}, messages["current"])
+	})
+
+	t.Run("should still claim valid jobs next to one queued by a newer release", func(t *testing.T) {
+		db, store := arrange(t)
+
+		queuedByPreviousRelease(t, db, "first", 0, `{"name":"first"}`)
+		queuedByPreviousRelease(t, db, "newer", 3, `{"label":"newer"}`)
+		assert.Nil(t, store.Create(context.Background(), upgradedCommand{Title: "current", Priority: 5}, bus.CreateOptions{}))
+
+		jobs, err := store.GetNextPendingJobs(context.Background())
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 3, jobs)
+
+		var (
+			undecodable []bus.UndecodableRequest
+			titles      []string
+		)
+
+		for _, job := range jobs {
+			switch msg := job.Message().(type) {
+			case bus.UndecodableRequest:
+				undecodable = append(undecodable, msg)
+			case upgradedCommand:
+				titles = append(titles, msg.Title)
+			}
+		}
+
+		slices.Sort(titles)
+
+		assert.DeepEqual(t, []string{"current", "first"}, titles)
+		assert.HasLength(t, 1, undecodable)
+		assert.Equal(t, upgradedCommand{}.Name_(), undecodable[0].Name)
+		assert.ErrorIs(t, storage.ErrUnsupportedVersion, undecodable[0].Err)
+	})
+
+	t.Run("should upcast the payload of a job queued by a previous release when retrieving it", func(t *testing.T) {
+		db, store := arrange(t)
+
+		queuedByPreviousRelease(t, db, "first", 0, `{"name":"first"}`)
+
+		job, err := store.GetJobByID(context.Background(), "first")
+
+		assert.Nil(t, err)
+		assert.Equal(t, upgradedCommand{Title: "first", Priority: 1}, job.Message().(upgradedCommand))
+	})
+
+	t.Run("should keep the raw payload of a job queued by a newer release", func(t *testing.T) {
+		db, store := arrange(t)
+
+		queuedByPreviousRelease(t, db, "newer", 3, `{"label":"newer"}`)
+
+		job, err := store.GetJobByID(context.Background(), "newer")
+
+		assert.Nil(t, err)
+		assert.Zero(t, job.Message())
+
+		jobs, err := store.GetAllJobs(context.Background(), bus.GetJobsFilters{})
+
+		assert.Nil(t, err)
+		assert.HasLength(t, 1, jobs.Data)
+	})
+}
+
+// Command whose payload has changed twice: the name field has been renamed to title and
+// a priority field has been added afterwards.
+type upgradedCommand struct {
+	bus.Command[bus.UnitType]
+
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+}
+
+func (upgradedCommand) Name_() string      { return "upgradedCommand" }
+func (upgradedCommand) ResourceID() string { return "" }
+
+func init() {
+	bus.Marshallable.Register(upgradedCommand{}, func(s string) (bus.Request, error) {
+		return storage.UnmarshalJSON[upgradedCommand](s)
+	})
+
+	bus.Marshallable.Upcast(upgradedCommand{}, 0, storage.UpcastJSON(func(data map[string]any) error {
+		data["title"] = data["name"]
+		delete(data, "name")
+		return nil
+	}))
+
+	bus.Marshallable.Upcast(upgradedCommand{}, 1, storage.UpcastJSON(func(data map[string]any) error {
+		data["priority"] = 1
+		return nil
+	}))
+}
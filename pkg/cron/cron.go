@@ -0,0 +1,244 @@
+// Package cron parses standard cron expressions (minute, hour, day of month, month
+// and day of week) and computes their next occurrences.
+package cron
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/apperr"
+)
+
+var ErrInvalidExpression = apperr.New("invalid_cron_expression")
+
+// Occurrences are searched up to this number of years after the reference time. It
+// prevents looping forever on expressions which could never match such as "0 0 30 2 *".
+const maxSearchYears = 5
+
+var macros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var (
+	minutes     = bounds{min: 0, max: 59}
+	hours       = bounds{min: 0, max: 23}
+	daysOfMonth = bounds{min: 1, max: 31}
+	months      = bounds{min: 1, max: 12, names: []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}}
+	daysOfWeek  = bounds{min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}} // 7 is also sunday
+)
+
+type (
+	// Parsed cron expression. Each field is stored as a bitset of allowed values.
+	Expression struct {
+		raw        string
+		minute     uint64
+		hour       uint64
+		dayOfMonth uint64
+		month      uint64
+		dayOfWeek  uint64
+		anyDay     bool // Day of month field is a wildcard
+		anyWeekday bool // Day of week field is a wildcard
+	}
+
+	bounds struct {
+		min, max int
+		names    []string // Optional names, the first one maps to min
+	}
+)
+
+// Parses the given cron expression. It accepts the standard five fields syntax with
+// lists, ranges, steps and month or weekday names, and the usual @daily like macros.
+func Parse(value string) (Expression, error) {
+	raw := strings.TrimSpace(value)
+	expr := raw
+
+	if macro, isMacro := macros[strings.ToLower(expr)]; isMacro {
+		expr = macro
+	}
+
+	fields := strings.Fields(expr)
+
+	if len(fields) != 5 {
+		return Expression{}, ErrInvalidExpression
+	}
+
+	var (
+		e   = Expression{raw: raw}
+		err error
+	)
+
+	if e.minute, err = parseField(fields[0], minutes); err != nil {
+		return Expression{}, err
+	}
+
+	if e.hour, err = parseField(fields[1], hours); err != nil {
+		return Expression{}, err
+	}
+
+	if e.dayOfMonth, err = parseField(fields[2], daysOfMonth); err != nil {
+		return Expression{}, err
+	}
+
+	if e.month, err = parseField(fields[3], months); err != nil {
+		return Expression{}, err
+	}
+
+	if e.dayOfWeek, err = parseField(fields[4], daysOfWeek); err != nil {
+		return Expression{}, err
+	}
+
+	// Sunday can be represented by both 0 and 7
+	if e.dayOfWeek&(1<<7) != 0 {
+		e.dayOfWeek |= 1
+	}
+
+	e.anyDay = strings.HasPrefix(fields[2], "*")
+	e.anyWeekday = strings.HasPrefix(fields[4], "*")
+
+	return e, nil
+}
+
+// Returns the first occurrence strictly after the given time, in the location of the
+// given time. The zero time is returned if there is none in the next few years.
+func (e Expression) Next(after time.Time) time.Time {
+	loc := after.Location()
+	t := time.Date(after.Year(), after.Month(), after.Day(), after.Hour(), after.Minute(), 0, 0, loc).Add(time.Minute)
+	limit := after.Year() + maxSearchYears
+
+	for t.Year() <= limit {
+		if !has(e.month, int(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !e.matchesDay(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, loc)
+			continue
+		}
+
+		if !has(e.hour, t.Hour()) {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, loc)
+			continue
+		}
+
+		if !has(e.minute, t.Minute()) {
+			t = t.Add(time.Minute)
+			continue
+		}
+
+		return t
+	}
+
+	return time.Time{}
+}
+
+// Returns at most n next occurrences after the given time.
+func (e Expression) NextN(after time.Time, n int) []time.Time {
+	result := make([]time.Time, 0, n)
+
+	for len(result) < n {
+		after = e.Next(after)
+
+		if after.IsZero() {
+			break
+		}
+
+		result = append(result, after)
+	}
+
+	return result
+}
+
+func (e Expression) String() string { return e.raw }
+
+// When both day fields are restricted, a time matches if any of them matches as
+// specified by the standard cron behavior.
+func (e Expression) matchesDay(t time.Time) bool {
+	dayOfMonth := has(e.dayOfMonth, t.Day())
+	dayOfWeek := has(e.dayOfWeek, int(t.Weekday()))
+
+	if e.anyDay || e.anyWeekday {
+		return dayOfMonth && dayOfWeek
+	}
+
+	return dayOfMonth || dayOfWeek
+}
+
+func parseField(field string, b bounds) (uint64, error) {
+	var bits uint64
+
+	for _, part := range strings.Split(field, ",") {
+		var (
+			rangePart, stepPart, hasStep = strings.Cut(part, "/")
+			start, end                   int
+			step                         = 1
+			err                          error
+		)
+
+		if hasStep {
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return 0, ErrInvalidExpression
+			}
+		}
+
+		if rangePart == "*" {
+			start, end = b.min, b.max
+
+			// Sunday is already represented by 0
+			if b.max == daysOfWeek.max {
+				end--
+			}
+		} else {
+			low, high, isRange := strings.Cut(rangePart, "-")
+
+			if start, err = b.value(low); err != nil {
+				return 0, err
+			}
+
+			end = start
+
+			if isRange {
+				if end, err = b.value(high); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				end = b.max // "5/15" means every 15 starting at 5
+			}
+
+			if end < start {
+				return 0, ErrInvalidExpression
+			}
+		}
+
+		for i := start; i <= end; i += step {
+			bits |= 1 << i
+		}
+	}
+
+	return bits, nil
+}
+
+func (b bounds) value(raw string) (int, error) {
+	for i, name := range b.names {
+		if strings.EqualFold(raw, name) {
+			return b.min + i, nil
+		}
+	}
+
+	v, err := strconv.Atoi(raw)
+
+	if err != nil || v < b.min || v > b.max {
+		return 0, ErrInvalidExpression
+	}
+
+	return v, nil
+}
+
+func has(bits uint64, value int) bool { return bits&(1<<value) != 0 }
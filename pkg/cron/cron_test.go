@@ -0,0 +1,93 @@
+package cron_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/cron"
+)
+
+func Test_Parse(t *testing.T) {
+	t.Run("should reject invalid expressions", func(t *testing.T) {
+		tests := []string{
+			"",
+			"* * * *",
+			"* * * * * *",
+			"60 * * * *",
+			"* 24 * * *",
+			"* * 0 * *",
+			"* * * 13 *",
+			"* * * * 8",
+			"*/0 * * * *",
+			"10-5 * * * *",
+			"a * * * *",
+			"@sometimes",
+		}
+
+		for _, expr := range tests {
+			t.Run(expr, func(t *testing.T) {
+				_, err := cron.Parse(expr)
+
+				assert.ErrorIs(t, cron.ErrInvalidExpression, err)
+			})
+		}
+	})
+
+	t.Run("should keep the raw expression", func(t *testing.T) {
+		expr, err := cron.Parse(" @daily ")
+
+		assert.Nil(t, err)
+		assert.Equal(t, "@daily", expr.String())
+	})
+}
+
+func Test_Next(t *testing.T) {
+	reference := time.Date(2024, time.March, 15, 10, 30, 45, 0, time.UTC) // A friday
+
+	tests := []struct {
+		expr     string
+		expected time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.March, 15, 10, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, time.March, 15, 10, 45, 0, 0, time.UTC)},
+		{"0 22 * * *", time.Date(2024, time.March, 15, 22, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2024, time.March, 16, 3, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.March, 16, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.March, 15, 11, 0, 0, 0, time.UTC)},
+		{"0 0 * * mon-wed", time.Date(2024, time.March, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1 jan *", time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, time.February, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * sun", time.Date(2024, time.March, 17, 0, 0, 0, 0, time.UTC)}, // Either the day of month or the weekday
+		{"0 12 20 * *", time.Date(2024, time.March, 20, 12, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			expr, err := cron.Parse(tt.expr)
+
+			assert.Nil(t, err)
+			assert.Equal(t, tt.expected, expr.Next(reference))
+		})
+	}
+
+	t.Run("should return the zero time if it never matches", func(t *testing.T) {
+		expr, err := cron.Parse("0 0 30 2 *")
+
+		assert.Nil(t, err)
+		assert.Zero(t, expr.Next(reference))
+	})
+
+	t.Run("should return the next n occurrences", func(t *testing.T) {
+		expr, err := cron.Parse("0 22 * * *")
+
+		assert.Nil(t, err)
+		assert.DeepEqual(t, []time.Time{
+			time.Date(2024, time.March, 15, 22, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 16, 22, 0, 0, 0, time.UTC),
+			time.Date(2024, time.March, 17, 22, 0, 0, 0, time.UTC),
+		}, expr.NextN(reference, 3))
+	})
+}
@@ -3,15 +3,20 @@ package http
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"path"
+	"strings"
 
 	"github.com/YuukanOO/seelf/pkg/apperr"
 	"github.com/YuukanOO/seelf/pkg/log"
 	"github.com/gin-gonic/gin"
 )
 
-var ErrUnexpected = apperr.New("unexpected_error") // Error returned when an infrastructure error occurs
+var (
+	ErrUnexpected      = apperr.New("unexpected_error")  // Error returned when an infrastructure error occurs
+	ErrTooManyRequests = apperr.New("too_many_requests") // Error returned when a client has been throttled
+)
 
 // Tiny interface to represents needed contrat in order to use helpers provided by this package.
 type Server interface {
@@ -78,6 +83,88 @@ func Ok[TOut any](ctx *gin.Context, data TOut) error {
 	return nil
 }
 
+// Copy the given reader to the response, flushing as soon as data is available so
+// it could be used to follow a stream. It returns when the reader is exhausted or
+// the client has gone away.
+func Stream(ctx *gin.Context, contentType string, reader io.Reader) error {
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	buf := make([]byte, 32*1024)
+
+	for {
+		n, err := reader.Read(buf)
+
+		if n > 0 {
+			if _, werr := ctx.Writer.Write(buf[:n]); werr != nil {
+				return nil // Client has gone away, nothing more to do
+			}
+
+			ctx.Writer.Flush()
+		}
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) && ctx.Request.Context().Err() == nil {
+				ctx.Error(err)
+			}
+
+			return nil
+		}
+	}
+}
+
+// Prepare the response to send server-sent events. Once called, errors could not be
+// sent with the appropriate status anymore.
+func EventStream(ctx *gin.Context) {
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	ctx.Header("X-Accel-Buffering", "no") // Prevent proxies such as nginx to buffer the response
+	ctx.Status(http.StatusOK)
+	ctx.Writer.Flush()
+}
+
+// Sends a single server-sent event and flush it right away. The id and name are optional.
+func SendEvent(ctx *gin.Context, id string, name string, data string) error {
+	var b strings.Builder
+
+	if id != "" {
+		b.WriteString("id: " + id + "\n")
+	}
+
+	if name != "" {
+		b.WriteString("event: " + name + "\n")
+	}
+
+	for _, line := range strings.Split(data, "\n") {
+		b.WriteString("data: " + line + "\n")
+	}
+
+	b.WriteString("\n")
+
+	if _, err := ctx.Writer.WriteString(b.String()); err != nil {
+		return err
+	}
+
+	ctx.Writer.Flush()
+
+	return nil
+}
+
+// Sends a server-sent comment, ignored by clients, mostly used to keep the connection
+// alive when no event has been sent for a while.
+func SendComment(ctx *gin.Context, comment string) error {
+	if _, err := ctx.Writer.WriteString(": " + comment + "\n\n"); err != nil {
+		return err
+	}
+
+	ctx.Writer.Flush()
+
+	return nil
+}
+
 // Handle the given non-nil error and sets the status code based on error type.
 func HandleError(s Server, ctx *gin.Context, err error) {
 	var (
@@ -90,8 +177,11 @@ func HandleError(s Server, ctx *gin.Context, err error) {
 		status = http.StatusBadRequest // Default to HTTP 400
 		data = err
 
-		if errors.Is(err, apperr.ErrNotFound) {
+		switch {
+		case errors.Is(err, apperr.ErrNotFound):
 			status = http.StatusNotFound // But if it's a not found, that's an HTTP 404
+		case errors.Is(err, ErrTooManyRequests):
+			status = http.StatusTooManyRequests
 		}
 	} else {
 		s.Logger().Errorw(err.Error(), "error", err)
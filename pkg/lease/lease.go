@@ -0,0 +1,163 @@
+package lease
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/log"
+)
+
+type (
+	// Adapter used to persist leases. It must be shared by every instance competing
+	// for the same lease.
+	Store interface {
+		Setup() error                                                                     // Setup the store
+		Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) // Acquire or renew the lease, returns true if the owner holds it
+		Release(ctx context.Context, name, owner string) error                            // Release the lease if held by the given owner
+	}
+
+	// Elects a single leader among instances sharing the same store. Leadership is held
+	// as long as the leader renews its lease before it expires.
+	Elector struct {
+		store    Store
+		logger   log.Logger
+		name     string
+		owner    string
+		ttl      time.Duration
+		elected  func(context.Context)
+		mu       sync.RWMutex
+		isLeader bool
+		resign   context.CancelFunc // Cancel the context given to the elected function, set while leader
+		running  sync.WaitGroup     // Elected functions currently running
+		done     chan struct{}
+		stopped  chan struct{}
+	}
+)
+
+// Builds a new elector competing for the lease with the given name on behalf of the given owner.
+// The elected function is called in its own goroutine each time this instance becomes the leader
+// so it never delays lease renewals. Its context is cancelled as soon as the leadership is lost.
+func NewElector(store Store, logger log.Logger, name, owner string, ttl time.Duration, elected func(context.Context)) *Elector {
+	return &Elector{
+		store:   store,
+		logger:  logger,
+		name:    name,
+		owner:   owner,
+		ttl:     ttl,
+		elected: elected,
+	}
+}
+
+// Try to acquire the lease right away so the caller knows if it is the leader when this
+// method returns, then keep renewing (or trying to acquire) it in the background.
+func (e *Elector) Start() {
+	e.done = make(chan struct{})
+	e.stopped = make(chan struct{})
+
+	e.campaign()
+
+	go func() {
+		defer close(e.stopped)
+
+		// Renew the lease a few times before it expires so a missed renewal does not cost the leadership
+		ticker := time.NewTicker(e.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-e.done:
+				return
+			case <-ticker.C:
+				e.campaign()
+			}
+		}
+	}()
+}
+
+// Stop campaigning, wait for the elected function to return and release the lease so another
+// instance could take over right away.
+func (e *Elector) Stop() {
+	if e.done == nil {
+		return
+	}
+
+	close(e.done)
+	<-e.stopped
+	e.done = nil
+
+	wasLeader := e.IsLeader()
+	e.setLeader(false)
+	e.running.Wait()
+
+	if !wasLeader {
+		return
+	}
+
+	if err := e.store.Release(context.Background(), e.name, e.owner); err != nil {
+		e.logger.Errorw("error while releasing leadership",
+			"lease", e.name,
+			"error", err)
+	}
+}
+
+// Returns true if this instance currently holds the lease.
+func (e *Elector) IsLeader() bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	return e.isLeader
+}
+
+func (e *Elector) campaign() {
+	acquired, err := e.store.Acquire(context.Background(), e.name, e.owner, e.ttl)
+
+	if err != nil {
+		e.logger.Errorw("error while acquiring leadership",
+			"lease", e.name,
+			"error", err)
+		// Could not tell if the lease is still held, the safest is to consider it has been lost
+		acquired = false
+	}
+
+	wasLeader := e.IsLeader()
+	e.setLeader(acquired)
+
+	switch {
+	case acquired && !wasLeader:
+		e.logger.Infow("this instance is now the leader",
+			"lease", e.name,
+			"owner", e.owner)
+
+		if e.elected != nil {
+			ctx, cancel := context.WithCancel(context.Background())
+
+			e.mu.Lock()
+			e.resign = cancel
+			e.mu.Unlock()
+
+			e.running.Add(1)
+
+			go func() {
+				defer e.running.Done()
+				e.elected(ctx)
+			}()
+		}
+	case !acquired && wasLeader:
+		e.logger.Warnw("this instance is not the leader anymore",
+			"lease", e.name,
+			"owner", e.owner)
+	}
+}
+
+func (e *Elector) setLeader(value bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.isLeader = value
+
+	if !value && e.resign != nil {
+		e.resign()
+		e.resign = nil
+	}
+}
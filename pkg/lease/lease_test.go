@@ -0,0 +1,232 @@
+package lease_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/lease"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+)
+
+const ttl = 60 * time.Millisecond
+
+func Test_Elector(t *testing.T) {
+
+	arrange := func(tb testing.TB, store lease.Store, owner string) (*lease.Elector, <-chan context.Context) {
+		elections := make(chan context.Context, 10)
+		elector := lease.NewElector(store, must.Panic(log.NewLogger()), "leader", owner, ttl, func(ctx context.Context) {
+			elections <- ctx
+		})
+
+		tb.Cleanup(elector.Stop)
+
+		return elector, elections
+	}
+
+	t.Run("should be elected right away if the lease is not held", func(t *testing.T) {
+		elector, elections := arrange(t, newStore(), "one")
+
+		elector.Start()
+
+		assert.True(t, elector.IsLeader())
+		waitFor(t, elections)
+	})
+
+	t.Run("should not be elected while the lease is held by another instance", func(t *testing.T) {
+		store := newStore()
+		leader, _ := arrange(t, store, "one")
+		follower, elections := arrange(t, store, "two")
+
+		leader.Start()
+		follower.Start()
+
+		time.Sleep(2 * ttl)
+
+		assert.True(t, leader.IsLeader())
+		assert.False(t, follower.IsLeader())
+		assert.HasLength(t, 0, drain(elections))
+	})
+
+	t.Run("should take over the lease once it has expired", func(t *testing.T) {
+		store := newStore()
+		leader, leaderElections := arrange(t, store, "one")
+		follower, followerElections := arrange(t, store, "two")
+
+		leader.Start()
+		follower.Start()
+
+		ctx := waitFor(t, leaderElections)
+
+		store.unreachable("one")
+
+		waitFor(t, followerElections)
+		assert.True(t, follower.IsLeader())
+		assert.False(t, leader.IsLeader())
+		assert.ErrorIs(t, context.Canceled, ctx.Err())
+	})
+
+	t.Run("should call the elected function again when the leadership is regained", func(t *testing.T) {
+		store := newStore()
+		elector, elections := arrange(t, store, "one")
+
+		elector.Start()
+
+		first := waitFor(t, elections)
+
+		store.unreachable("one")
+
+		<-first.Done()
+		assert.False(t, elector.IsLeader())
+
+		store.reachable("one")
+
+		second := waitFor(t, elections)
+		assert.True(t, elector.IsLeader())
+		assert.Nil(t, second.Err())
+	})
+
+	t.Run("should not delay lease renewals while the elected function is running", func(t *testing.T) {
+		store := newStore()
+		block := make(chan struct{})
+		elector := lease.NewElector(store, must.Panic(log.NewLogger()), "leader", "one", ttl, func(context.Context) {
+			<-block
+		})
+
+		elector.Start()
+		defer elector.Stop()
+		defer close(block)
+
+		time.Sleep(2 * ttl)
+
+		assert.True(t, elector.IsLeader())
+		assert.True(t, store.acquisitions("one") > 1)
+	})
+
+	t.Run("should release the lease when stopped", func(t *testing.T) {
+		store := newStore()
+		elector, elections := arrange(t, store, "one")
+
+		elector.Start()
+		ctx := waitFor(t, elections)
+
+		elector.Stop()
+
+		assert.False(t, elector.IsLeader())
+		assert.ErrorIs(t, context.Canceled, ctx.Err())
+
+		acquired, err := store.Acquire(context.Background(), "leader", "two", ttl)
+
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+}
+
+func waitFor(t testing.TB, elections <-chan context.Context) context.Context {
+	t.Helper()
+
+	select {
+	case ctx := <-elections:
+		return ctx
+	case <-time.After(10 * ttl):
+		t.Fatal("should have been elected")
+		return nil
+	}
+}
+
+func drain(elections <-chan context.Context) []context.Context {
+	var result []context.Context
+
+	for {
+		select {
+		case ctx := <-elections:
+			result = append(result, ctx)
+		default:
+			return result
+		}
+	}
+}
+
+var errUnreachable = errors.New("unreachable")
+
+type (
+	// In memory store mimicking the sql one. Owners can be made unreachable to
+	// simulate an instance which could not renew its lease anymore.
+	store struct {
+		mu        sync.Mutex
+		holder    string
+		expiresAt time.Time
+		down      map[string]bool
+		renewals  map[string]int
+	}
+)
+
+func newStore() *store {
+	return &store{
+		down:     make(map[string]bool),
+		renewals: make(map[string]int),
+	}
+}
+
+func (s *store) Setup() error { return nil }
+
+func (s *store) Acquire(_ context.Context, _, owner string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down[owner] {
+		return false, errUnreachable
+	}
+
+	now := time.Now()
+
+	if s.holder != "" && s.holder != owner && now.Before(s.expiresAt) {
+		return false, nil
+	}
+
+	s.holder = owner
+	s.expiresAt = now.Add(ttl)
+	s.renewals[owner]++
+
+	return true, nil
+}
+
+func (s *store) Release(_ context.Context, _, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.down[owner] {
+		return errUnreachable
+	}
+
+	if s.holder == owner {
+		s.holder = ""
+	}
+
+	return nil
+}
+
+func (s *store) unreachable(owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.down[owner] = true
+}
+
+func (s *store) reachable(owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.down, owner)
+}
+
+func (s *store) acquisitions(owner string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.renewals[owner]
+}
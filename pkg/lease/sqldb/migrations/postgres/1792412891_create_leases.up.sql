@@ -0,0 +1,6 @@
+CREATE TABLE leases (
+    name TEXT NOT NULL
+    ,owner TEXT NOT NULL
+    ,expires_at TIMESTAMP NOT NULL
+    ,CONSTRAINT pk_leases PRIMARY KEY(name)
+);
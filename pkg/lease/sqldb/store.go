@@ -0,0 +1,61 @@
+package sqldb
+
+import (
+	"context"
+	"embed"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/lease"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+)
+
+var (
+	//go:embed migrations/*/*.sql
+	migrations embed.FS
+
+	Migrations = sqldb.NewMigrationsModule("lease", "migrations", migrations)
+)
+
+type store struct {
+	db *sqldb.Database
+}
+
+// Builds a new adapter persisting leases in the given database.
+func NewLeasesStore(db *sqldb.Database) lease.Store {
+	return &store{db}
+}
+
+func (s *store) Setup() error {
+	return s.db.Migrate(Migrations)
+}
+
+func (s *store) Acquire(ctx context.Context, name, owner string, ttl time.Duration) (bool, error) {
+	now := time.Now().UTC()
+
+	// The lease is taken if it does not exist yet, renewed if already held by the owner
+	// or stolen if it has expired. In any other case, no row is affected.
+	r, err := s.db.ExecContext(ctx, `
+		INSERT INTO leases (name, owner, expires_at) VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE
+		SET
+			owner = excluded.owner
+			,expires_at = excluded.expires_at
+		WHERE leases.owner = excluded.owner OR leases.expires_at < ?`,
+		name, owner, now.Add(ttl), now)
+
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := r.RowsAffected()
+
+	return affected > 0, err
+}
+
+func (s *store) Release(ctx context.Context, name, owner string) error {
+	_, err := s.db.ExecContext(ctx, `
+		DELETE FROM leases
+		WHERE name = ? AND owner = ?`, name, owner)
+
+	return err
+}
@@ -0,0 +1,108 @@
+package sqldb_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/bus/memory"
+	"github.com/YuukanOO/seelf/pkg/lease"
+	leasesqldb "github.com/YuukanOO/seelf/pkg/lease/sqldb"
+	"github.com/YuukanOO/seelf/pkg/log"
+	"github.com/YuukanOO/seelf/pkg/must"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb"
+	"github.com/YuukanOO/seelf/pkg/storage/sqlite"
+)
+
+func Test_LeasesStore(t *testing.T) {
+	arrange := func(tb testing.TB) lease.Store {
+		dialect, dsn := sqlite.Dialect, "file:"+filepath.Join(tb.TempDir(), "seelf.db")
+
+		if url := os.Getenv("SEELF_TEST_DATABASE_URL"); url != "" {
+			dialect, dsn = postgres.Dialect, postgres.PrepareTestSchema(tb, url)
+		}
+
+		db, err := sqldb.Open(dialect, dsn, must.Panic(log.NewLogger()), memory.NewBus())
+
+		if err != nil {
+			tb.Fatal(err)
+		}
+
+		tb.Cleanup(func() { db.Close() })
+
+		store := leasesqldb.NewLeasesStore(db)
+
+		if err = store.Setup(); err != nil {
+			tb.Fatal(err)
+		}
+
+		return store
+	}
+
+	t.Run("should acquire a lease not held by anyone", func(t *testing.T) {
+		store := arrange(t)
+
+		acquired, err := store.Acquire(context.Background(), "leader", "one", time.Minute)
+
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should renew a lease held by the same owner", func(t *testing.T) {
+		store := arrange(t)
+
+		_, err := store.Acquire(context.Background(), "leader", "one", time.Minute)
+		assert.Nil(t, err)
+
+		acquired, err := store.Acquire(context.Background(), "leader", "one", time.Minute)
+
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should not acquire a lease held by another owner", func(t *testing.T) {
+		store := arrange(t)
+
+		_, err := store.Acquire(context.Background(), "leader", "one", time.Minute)
+		assert.Nil(t, err)
+
+		acquired, err := store.Acquire(context.Background(), "leader", "two", time.Minute)
+
+		assert.Nil(t, err)
+		assert.False(t, acquired)
+	})
+
+	t.Run("should acquire an expired lease held by another owner", func(t *testing.T) {
+		store := arrange(t)
+
+		_, err := store.Acquire(context.Background(), "leader", "one", -time.Minute)
+		assert.Nil(t, err)
+
+		acquired, err := store.Acquire(context.Background(), "leader", "two", time.Minute)
+
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("should release a lease only if held by the given owner", func(t *testing.T) {
+		store := arrange(t)
+
+		_, err := store.Acquire(context.Background(), "leader", "one", time.Minute)
+		assert.Nil(t, err)
+		assert.Nil(t, store.Release(context.Background(), "leader", "two"))
+
+		acquired, err := store.Acquire(context.Background(), "leader", "two", time.Minute)
+		assert.Nil(t, err)
+		assert.False(t, acquired)
+
+		assert.Nil(t, store.Release(context.Background(), "leader", "one"))
+
+		acquired, err = store.Acquire(context.Background(), "leader", "two", time.Minute)
+		assert.Nil(t, err)
+		assert.True(t, acquired)
+	})
+}
@@ -1,7 +1,9 @@
 package ostools
 
 import (
+	"bufio"
 	"errors"
+	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -58,3 +60,48 @@ func RemovePattern(pattern string) error {
 
 	return nil
 }
+
+// Read lines of the given file starting at the given byte offset and returns them
+// alongside the offset right after the last returned line. Lines not terminated by a
+// line feed yet are only returned if partial is true, which is useful to tail a file
+// still being written. A missing file is not considered as an error and returns no lines.
+func ReadLines(name string, offset int64, partial bool) (lines []string, next int64, err error) {
+	next = offset
+	file, err := os.Open(name)
+
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, next, nil
+		}
+
+		return nil, next, err
+	}
+
+	defer file.Close()
+
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return nil, next, err
+	}
+
+	reader := bufio.NewReader(file)
+
+	for {
+		line, err := reader.ReadString('\n')
+
+		if err != nil {
+			if !errors.Is(err, io.EOF) {
+				return lines, next, err
+			}
+
+			if partial && line != "" {
+				lines = append(lines, line)
+				next += int64(len(line))
+			}
+
+			return lines, next, nil
+		}
+
+		lines = append(lines, line[:len(line)-1])
+		next += int64(len(line))
+	}
+}
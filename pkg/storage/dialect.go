@@ -0,0 +1,16 @@
+package storage
+
+import (
+	"database/sql"
+
+	"github.com/golang-migrate/migrate/v4/database"
+)
+
+// Represents a flavor of SQL spoken by a database. Stores are written using `?` placeholders
+// and SQL understood by every supported dialects, the dialect takes care of the rest.
+type Dialect interface {
+	Name() string                                               // Name of the dialect, also the directory containing its migrations
+	DriverName() string                                         // Name of the database/sql driver to use
+	Rebind(string) string                                       // Rewrite `?` placeholders of a query to the ones expected by the driver
+	Migrator(db *sql.DB, table string) (database.Driver, error) // Builds the driver used to apply migrations and track them in the given table
+}
@@ -1,5 +1,13 @@
 package storage
 
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+)
+
+var ErrUnsupportedVersion = errors.New("unsupported version")
+
 type (
 	// Function used to map from a raw value to a discriminated type.
 	DiscriminatedMapperFunc[T any] func(string) (T, error)
@@ -7,13 +15,21 @@ type (
 	// Function used to extract a discriminator from a value.
 	DiscriminatorFunc[T any] func(T) string
 
+	// Function used to migrate a raw value from one version to the next one.
+	Upcaster func(string) (string, error)
+
 	// Mapper struct to be able to rehydrate discriminated types.
 	// Discriminated types represents an extensible type known through a discriminator value. Building a
 	// specific mapper makes it easy to reconstruct a specific type from a discriminator and raw data.
 	// Without it, retrieving dynamic types from the database is a nightmare. With this solution though,
 	// it's not that bad ;)
+	//
+	// When raw values are persisted for a long time, their shape may change between two releases. Each
+	// change should be described by an upcaster migrating a raw value to the next version so values
+	// persisted alongside their version could still be rehydrated with FromVersion.
 	DiscriminatedMapper[T any] struct {
 		known     map[string]DiscriminatedMapperFunc[T]
+		upcasters map[string][]Upcaster
 		extractor DiscriminatorFunc[T]
 	}
 )
@@ -24,6 +40,7 @@ func NewDiscriminatedMapper[T any](
 ) *DiscriminatedMapper[T] {
 	return &DiscriminatedMapper[T]{
 		known:     make(map[string]DiscriminatedMapperFunc[T]),
+		upcasters: make(map[string][]Upcaster),
 		extractor: extractor,
 	}
 }
@@ -42,14 +59,76 @@ func (m *DiscriminatedMapper[T]) Register(concreteType T, mapper DiscriminatedMa
 	m.known[discriminator] = mapper
 }
 
-// Rehydrate a discriminated type from a raw value.
+// Register an upcaster migrating raw values of the given concrete type from the given version
+// to the next one. Every type starts at version 0 and each upcaster bumps its current version by one
+// so they must be registered in order. It will panic if that's not the case since it's a dev error.
+func (m *DiscriminatedMapper[T]) Upcast(concreteType T, from int, upcaster Upcaster) {
+	discriminator := m.extractor(concreteType)
+
+	if current := len(m.upcasters[discriminator]); from != current {
+		panic("upcaster registered for version " + strconv.Itoa(from) + " of " + discriminator +
+			" but the current version is " + strconv.Itoa(current))
+	}
+
+	m.upcasters[discriminator] = append(m.upcasters[discriminator], upcaster)
+}
+
+// Returns the current version of the given discriminator, the one of raw values produced by
+// the running code, which should be persisted alongside them.
+func (m *DiscriminatedMapper[T]) Version(discriminator string) int {
+	return len(m.upcasters[discriminator])
+}
+
+// Rehydrate a discriminated type from a raw value of the current version.
 func (m *DiscriminatedMapper[T]) From(discriminator, value string) (T, error) {
+	return m.FromVersion(discriminator, m.Version(discriminator), value)
+}
+
+// Rehydrate a discriminated type from a raw value of the given version, applying needed
+// upcasters to migrate it to the current version first.
+func (m *DiscriminatedMapper[T]) FromVersion(discriminator string, version int, value string) (T, error) {
+	var t T
+
 	mapper, found := m.known[discriminator]
 
 	if !found {
-		var t T
 		return t, ErrCouldNotUnmarshalGivenType
 	}
 
+	upcasters := m.upcasters[discriminator]
+
+	// Values persisted by a newer release could not be downgraded
+	if version < 0 || version > len(upcasters) {
+		return t, ErrUnsupportedVersion
+	}
+
+	for _, upcaster := range upcasters[version:] {
+		var err error
+
+		if value, err = upcaster(value); err != nil {
+			return t, err
+		}
+	}
+
 	return mapper(value)
 }
+
+// Builds an upcaster working on a JSON object, which is the most common representation
+// of raw values, to rename, remove or add fields.
+func UpcastJSON(fn func(map[string]any) error) Upcaster {
+	return func(value string) (string, error) {
+		var data map[string]any
+
+		if err := json.Unmarshal([]byte(value), &data); err != nil {
+			return "", err
+		}
+
+		if err := fn(data); err != nil {
+			return "", err
+		}
+
+		b, err := json.Marshal(data)
+
+		return string(b), err
+	}
+}
@@ -1,6 +1,7 @@
 package storage_test
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/YuukanOO/seelf/pkg/assert"
@@ -59,4 +60,73 @@ func Test_Discriminated(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, type2{"data2"}, t2.(type2))
 	})
+
+	t.Run("should panic if an upcaster is not registered for the current version", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Errorf("expected panic, got none")
+			}
+		}()
+
+		mapper.Upcast(type2{}, 1, func(data string) (string, error) { return data, nil })
+	})
+
+	t.Run("could upcast values persisted with older versions", func(t *testing.T) {
+		mapper.Upcast(type2{}, 0, func(data string) (string, error) { return data + "-v1", nil })
+		mapper.Upcast(type2{}, 1, func(data string) (string, error) { return data + "-v2", nil })
+
+		t.Run("should expose the current version", func(t *testing.T) {
+			assert.Equal(t, 0, mapper.Version("type1"))
+			assert.Equal(t, 2, mapper.Version("type2"))
+		})
+
+		t.Run("should apply every upcasters from the given version", func(t *testing.T) {
+			fromFirst, err := mapper.FromVersion("type2", 0, "data")
+
+			assert.Nil(t, err)
+			assert.Equal(t, type2{"data-v1-v2"}, fromFirst.(type2))
+
+			fromSecond, err := mapper.FromVersion("type2", 1, "data")
+
+			assert.Nil(t, err)
+			assert.Equal(t, type2{"data-v2"}, fromSecond.(type2))
+		})
+
+		t.Run("should not upcast values of the current version", func(t *testing.T) {
+			current, err := mapper.From("type2", "data")
+
+			assert.Nil(t, err)
+			assert.Equal(t, type2{"data"}, current.(type2))
+		})
+
+		t.Run("should error if the version is newer than the current one", func(t *testing.T) {
+			_, err := mapper.FromVersion("type2", 3, "data")
+
+			assert.ErrorIs(t, storage.ErrUnsupportedVersion, err)
+		})
+	})
+}
+
+func Test_UpcastJSON(t *testing.T) {
+	t.Run("should update the JSON object", func(t *testing.T) {
+		upcaster := storage.UpcastJSON(func(data map[string]any) error {
+			data["title"] = data["name"]
+			delete(data, "name")
+			return nil
+		})
+
+		data, err := upcaster(`{"name":"john"}`)
+
+		assert.Nil(t, err)
+		assert.Equal(t, `{"title":"john"}`, data)
+	})
+
+	t.Run("should return the error of the upcaster", func(t *testing.T) {
+		upcastErr := errors.New("some error")
+		upcaster := storage.UpcastJSON(func(map[string]any) error { return upcastErr })
+
+		_, err := upcaster(`{}`)
+
+		assert.ErrorIs(t, upcastErr, err)
+	})
 }
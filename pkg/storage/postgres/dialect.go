@@ -0,0 +1,57 @@
+package postgres
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+// PostgreSQL dialect, used when seelf is given a postgres:// database url.
+var Dialect storage.Dialect = dialect{}
+
+type dialect struct{}
+
+func (dialect) Name() string       { return "postgres" }
+func (dialect) DriverName() string { return "pgx" }
+
+// Rewrite `?` placeholders to the positional `$n` ones. Question marks inside quoted
+// strings or identifiers are left untouched.
+func (dialect) Rebind(query string) string {
+	var (
+		b     strings.Builder
+		n     int
+		quote rune
+	)
+
+	b.Grow(len(query) + 10)
+
+	for _, c := range query {
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			quote = c
+		case c == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+
+		b.WriteRune(c)
+	}
+
+	return b.String()
+}
+
+func (dialect) Migrator(db *sql.DB, table string) (database.Driver, error) {
+	return pgx.WithInstance(db, &pgx.Config{
+		MigrationsTable: table,
+	})
+}
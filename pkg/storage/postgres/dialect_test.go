@@ -0,0 +1,20 @@
+package postgres_test
+
+import (
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/storage/postgres"
+)
+
+func Test_Dialect(t *testing.T) {
+	t.Run("should rewrite placeholders to positional ones", func(t *testing.T) {
+		assert.Equal(t, "SELECT id FROM users WHERE email = $1 AND id <> $2",
+			postgres.Dialect.Rebind("SELECT id FROM users WHERE email = ? AND id <> ?"))
+	})
+
+	t.Run("should not rewrite question marks in quoted strings or identifiers", func(t *testing.T) {
+		assert.Equal(t, `SELECT '?', "what?" FROM jobs WHERE "group" = $1 AND name = 'why?'`,
+			postgres.Dialect.Rebind(`SELECT '?', "what?" FROM jobs WHERE "group" = ? AND name = 'why?'`))
+	})
+}
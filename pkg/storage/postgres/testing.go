@@ -0,0 +1,50 @@
+//go:build !release
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/YuukanOO/seelf/pkg/id"
+)
+
+// Creates an isolated schema in the database targeted by the given url and returns the
+// connection string to use to work inside it. The schema is dropped when the test ends.
+func PrepareTestSchema(tb testing.TB, databaseUrl string) string {
+	schema := "test_" + strings.ToLower(id.New[string]()) // Lowercased since unquoted identifiers in the search_path are folded
+
+	db, err := sql.Open(Dialect.DriverName(), databaseUrl)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	if _, err = db.ExecContext(context.Background(), `CREATE SCHEMA "`+schema+`"`); err != nil {
+		db.Close()
+		tb.Fatal(err)
+	}
+
+	tb.Cleanup(func() {
+		defer db.Close()
+
+		if _, err := db.ExecContext(context.Background(), `DROP SCHEMA "`+schema+`" CASCADE`); err != nil {
+			tb.Error(err)
+		}
+	})
+
+	u, err := url.Parse(databaseUrl)
+
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
@@ -10,7 +10,11 @@ import (
 	"github.com/YuukanOO/seelf/pkg/storage"
 )
 
-const countClause = "COUNT(*)" // Count clause needed for pagination.
+// Wrapper around a query to count its rows, needed for pagination.
+const (
+	countQueryPrefix = "SELECT COUNT(*) FROM ("
+	countQuerySuffix = ") paginated"
+)
 
 var ErrPaginationNotSupported = errors.New("pagination not supported for this query. Did you forget to build the query using the Select function?")
 
@@ -224,16 +228,13 @@ func (q *queryBuilder[T]) Paginate(
 		return result, ErrPaginationNotSupported
 	}
 
-	// Replace field names with the count clause to retrieve the total number of elements for the query
-	fields := q.parts[1]
-	q.parts[1] = countClause
-
-	if err = ex.QueryRowContext(ctx, q.String(), q.arguments...).Scan(&result.Total); err != nil {
+	// Wrap the query to retrieve the total number of elements. Some dialects do not allow
+	// an ORDER BY on a column which is not part of an aggregate so fields could not just be replaced.
+	if err = ex.QueryRowContext(ctx, countQueryPrefix+q.String()+countQuerySuffix, q.arguments...).Scan(&result.Total); err != nil {
 		return result, err
 	}
 
-	// Restore the original fields, append the limit/offset clause and run the query
-	q.parts[1] = fields
+	// Append the limit/offset clause and run the query
 	q.F("LIMIT ? OFFSET ?", perPage, (result.Page-1)*perPage)
 
 	result.IsLastPage = result.Total <= result.Page*perPage
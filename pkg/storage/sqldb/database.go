@@ -1,24 +1,24 @@
-package sqlite
+package sqldb
 
 import (
 	"context"
 	"database/sql"
 	"io/fs"
+	"path"
 
 	"github.com/YuukanOO/seelf/pkg/bus"
 	"github.com/YuukanOO/seelf/pkg/event"
 	"github.com/YuukanOO/seelf/pkg/log"
-	"github.com/YuukanOO/seelf/pkg/storage/sqlite/builder"
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/YuukanOO/seelf/pkg/storage/sqldb/builder"
 	"github.com/golang-migrate/migrate/v4"
-	"github.com/golang-migrate/migrate/v4/database/sqlite3"
 	"github.com/golang-migrate/migrate/v4/source/iofs"
-	_ "github.com/mattn/go-sqlite3"
 )
 
 const (
-	dbDriverName                     = "sqlite3"
 	migrateSourceName                = "embed"
-	transactionContextKey contextKey = "sqlitetx"
+	migrationsTableSuffix            = "_schema_migrations"
+	transactionContextKey contextKey = "sqldbtx"
 )
 
 var _ builder.Executor = (*Database)(nil) // Ensure Database implements the Executor interface
@@ -27,23 +27,24 @@ type (
 	// Represents a single module for database migrations.
 	MigrationsModule struct {
 		name string // Name of the module, used as a prefix for the migrations history table.
-		dir  string // Relative directory in the fs containing *.sql migrations files.
+		dir  string // Relative directory in the fs containing one sub directory of *.sql migrations files per dialect.
 		fs   fs.FS
 	}
 
-	// Handle to a sqlite database with useful helper methods on it :)
+	// Handle to a SQL database with useful helper methods on it :)
 	Database struct {
-		conn   *sql.DB
-		bus    bus.Dispatcher
-		logger log.Logger
+		conn    *sql.DB
+		dialect storage.Dialect
+		bus     bus.Dispatcher
+		logger  log.Logger
 	}
 
 	contextKey string
 )
 
-// Opens a connection to a sqlite database file.
-func Open(dsn string, logger log.Logger, bus bus.Dispatcher) (*Database, error) {
-	db, err := sql.Open(dbDriverName, dsn)
+// Opens a connection to a database speaking the given dialect.
+func Open(dialect storage.Dialect, dsn string, logger log.Logger, bus bus.Dispatcher) (*Database, error) {
+	db, err := sql.Open(dialect.DriverName(), dsn)
 
 	if err != nil {
 		return nil, err
@@ -53,7 +54,12 @@ func Open(dsn string, logger log.Logger, bus bus.Dispatcher) (*Database, error)
 		return nil, err
 	}
 
-	return &Database{db, bus, logger}, nil
+	return &Database{db, dialect, bus, logger}, nil
+}
+
+// Dialect spoken by the underlying database.
+func (db *Database) Dialect() storage.Dialect {
+	return db.dialect
 }
 
 // Close the underlying database.
@@ -64,28 +70,27 @@ func (db *Database) Close() error {
 // Migrates the opened database to the latest version.
 func (db *Database) Migrate(modules ...MigrationsModule) error {
 	for _, module := range modules {
-		source, err := iofs.New(module.fs, module.dir)
+		source, err := iofs.New(module.fs, path.Join(module.dir, db.dialect.Name()))
 
 		if err != nil {
 			return err
 		}
 
-		driver, err := sqlite3.WithInstance(db.conn, &sqlite3.Config{
-			MigrationsTable: module.name + "_" + sqlite3.DefaultMigrationsTable,
-		})
+		driver, err := db.dialect.Migrator(db.conn, module.name+migrationsTableSuffix)
 
 		if err != nil {
 			return err
 		}
 
-		migrator, err := migrate.NewWithInstance(migrateSourceName, source, dbDriverName, driver)
+		migrator, err := migrate.NewWithInstance(migrateSourceName, source, db.dialect.DriverName(), driver)
 
 		if err != nil {
 			return err
 		}
 
 		db.logger.Debugw("migrating database",
-			"module", module.name)
+			"module", module.name,
+			"dialect", db.dialect.Name())
 
 		if err := migrator.Up(); err != nil && err != migrate.ErrNoChange {
 			return err
@@ -131,15 +136,15 @@ func Transaction(ctx context.Context) *sql.Tx {
 }
 
 func (db *Database) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
-	return db.tryGetTransaction(ctx).ExecContext(ctx, query, args...)
+	return db.tryGetTransaction(ctx).ExecContext(ctx, db.dialect.Rebind(query), args...)
 }
 
 func (db *Database) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
-	return db.tryGetTransaction(ctx).QueryContext(ctx, query, args...)
+	return db.tryGetTransaction(ctx).QueryContext(ctx, db.dialect.Rebind(query), args...)
 }
 
 func (db *Database) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
-	return db.tryGetTransaction(ctx).QueryRowContext(ctx, query, args...)
+	return db.tryGetTransaction(ctx).QueryRowContext(ctx, db.dialect.Rebind(query), args...)
 }
 
 // Retrieve the executor from the given context. This is needed to execute query
@@ -166,7 +171,8 @@ func (db *Database) tryGetTransaction(ctx context.Context) builder.Executor {
 // Helpers to handle database writes from an array of event sources and handle events dispatching.
 // It will open and manage a transaction if none exist in the given context. This way,
 // we make sure event handlers participates in the same transaction so they are resolved as
-// a whole.
+// a whole. Asynchronous subscribers (see bus.OnAsync) only write the event to the outbox
+// here, so it will be delivered once the transaction has been committed.
 //
 // There's no way to add this method to the DB without type conversion so this is the easiest way
 // for now. Without the generics, I will always have to convert an array of entities to []event.Source
@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"database/sql"
+
+	"github.com/YuukanOO/seelf/pkg/storage"
+	"github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/sqlite3"
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// SQLite dialect, the default one since it only needs a file to work with.
+var Dialect storage.Dialect = dialect{}
+
+type dialect struct{}
+
+func (dialect) Name() string               { return "sqlite" }
+func (dialect) DriverName() string         { return "sqlite3" }
+func (dialect) Rebind(query string) string { return query }
+
+func (dialect) Migrator(db *sql.DB, table string) (database.Driver, error) {
+	return sqlite3.WithInstance(db, &sqlite3.Config{
+		MigrationsTable: table,
+	})
+}
@@ -0,0 +1,155 @@
+// Package throttle provides a tiny in memory attempts tracker used to slow down
+// brute-force attacks on sensitive endpoints such as the login one.
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+type (
+	// Options used to configure a Tracker.
+	Options struct {
+		MaxAttempts int           // Number of consecutive failures before a key gets locked
+		Delay       time.Duration // Base delay applied after a failure, doubled on each consecutive one
+		Lockout     time.Duration // How long a key stays locked once MaxAttempts has been reached
+	}
+
+	// Result of a failed attempt registration, mostly useful for logging purposes.
+	Failure struct {
+		Attempts int  // Highest number of consecutive failures among the given keys
+		Locked   bool // Whether at least one of the given keys is now locked
+	}
+
+	// Keep track of failed attempts per key (client ip, email, ...) and apply an
+	// exponential delay between each consecutive failure. Once the maximum number of
+	// attempts has been reached, the key is locked for the configured duration.
+	//
+	// Multiple goroutines can use the same tracker at the same time.
+	Tracker struct {
+		mu        sync.Mutex
+		options   Options
+		entries   map[string]*entry
+		lastPrune time.Time
+	}
+
+	entry struct {
+		failures int
+		until    time.Time // Time at which the next attempt is allowed
+	}
+)
+
+// Builds a new tracker with the given options.
+func NewTracker(options Options) *Tracker {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+
+	return &Tracker{
+		options:   options,
+		entries:   make(map[string]*entry),
+		lastPrune: time.Now(),
+	}
+}
+
+// Check if a new attempt is allowed for every given keys. If not, returns the
+// duration to wait before trying again.
+func (t *Tracker) Allowed(keys ...string) (time.Duration, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var (
+		now  = time.Now()
+		wait time.Duration
+	)
+
+	t.prune(now)
+
+	for _, key := range keys {
+		e, found := t.entries[key]
+
+		if !found {
+			continue
+		}
+
+		if remaining := e.until.Sub(now); remaining > wait {
+			wait = remaining
+		}
+	}
+
+	return wait, wait <= 0
+}
+
+// Register a failed attempt for every given keys.
+func (t *Tracker) Failed(keys ...string) (f Failure) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+
+	for _, key := range keys {
+		e, found := t.entries[key]
+
+		if !found || e.isStale(now, t.options.Lockout) {
+			e = &entry{}
+			t.entries[key] = e
+		}
+
+		e.failures++
+
+		if e.failures >= t.options.MaxAttempts {
+			e.until = now.Add(t.options.Lockout)
+			f.Locked = true
+		} else {
+			e.until = now.Add(t.delay(e.failures))
+		}
+
+		if e.failures > f.Attempts {
+			f.Attempts = e.failures
+		}
+	}
+
+	return f
+}
+
+// Register a successful attempt, forgetting everything about the given keys.
+func (t *Tracker) Succeeded(keys ...string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for _, key := range keys {
+		delete(t.entries, key)
+	}
+}
+
+// Compute the exponential delay for the given number of failures, never exceeding
+// the lockout duration.
+func (t *Tracker) delay(failures int) time.Duration {
+	d := t.options.Delay
+
+	for i := 1; i < failures && d < t.options.Lockout; i++ {
+		d *= 2
+	}
+
+	return min(d, t.options.Lockout)
+}
+
+// Remove entries which have not failed for a while so the tracker does not grow indefinitely.
+// An entry is considered stale once the lockout duration has elapsed after its last delay.
+func (t *Tracker) prune(now time.Time) {
+	if now.Sub(t.lastPrune) < t.options.Lockout {
+		return
+	}
+
+	t.lastPrune = now
+
+	for key, e := range t.entries {
+		if e.isStale(now, t.options.Lockout) {
+			delete(t.entries, key)
+		}
+	}
+}
+
+func (e *entry) isStale(now time.Time, lockout time.Duration) bool {
+	return now.Sub(e.until) > lockout
+}
@@ -0,0 +1,77 @@
+package throttle_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/YuukanOO/seelf/pkg/assert"
+	"github.com/YuukanOO/seelf/pkg/throttle"
+)
+
+func Test_Tracker(t *testing.T) {
+	t.Run("should allow attempts for unknown keys", func(t *testing.T) {
+		tracker := throttle.NewTracker(throttle.Options{MaxAttempts: 3, Delay: time.Second, Lockout: time.Minute})
+
+		wait, allowed := tracker.Allowed("ip:127.0.0.1")
+
+		assert.True(t, allowed)
+		assert.Equal(t, time.Duration(0), wait)
+	})
+
+	t.Run("should apply an exponential delay after each failure", func(t *testing.T) {
+		tracker := throttle.NewTracker(throttle.Options{MaxAttempts: 5, Delay: time.Second, Lockout: time.Minute})
+
+		f := tracker.Failed("ip:127.0.0.1")
+
+		assert.Equal(t, 1, f.Attempts)
+		assert.False(t, f.Locked)
+
+		wait, allowed := tracker.Allowed("ip:127.0.0.1")
+
+		assert.False(t, allowed)
+		assert.True(t, wait > 0 && wait <= time.Second)
+
+		tracker.Failed("ip:127.0.0.1")
+		wait, _ = tracker.Allowed("ip:127.0.0.1")
+
+		assert.True(t, wait > time.Second && wait <= 2*time.Second)
+	})
+
+	t.Run("should lock a key once the max attempts has been reached", func(t *testing.T) {
+		tracker := throttle.NewTracker(throttle.Options{MaxAttempts: 2, Delay: time.Millisecond, Lockout: time.Minute})
+
+		tracker.Failed("email:john@doe.com")
+		f := tracker.Failed("email:john@doe.com", "ip:127.0.0.1")
+
+		assert.Equal(t, 2, f.Attempts)
+		assert.True(t, f.Locked)
+
+		wait, allowed := tracker.Allowed("ip:10.0.0.1", "email:john@doe.com")
+
+		assert.False(t, allowed)
+		assert.True(t, wait > time.Second)
+	})
+
+	t.Run("should forget a key on success", func(t *testing.T) {
+		tracker := throttle.NewTracker(throttle.Options{MaxAttempts: 1, Delay: time.Second, Lockout: time.Minute})
+
+		tracker.Failed("ip:127.0.0.1")
+		tracker.Succeeded("ip:127.0.0.1")
+
+		_, allowed := tracker.Allowed("ip:127.0.0.1")
+
+		assert.True(t, allowed)
+	})
+
+	t.Run("should allow a new attempt once the delay has elapsed", func(t *testing.T) {
+		tracker := throttle.NewTracker(throttle.Options{MaxAttempts: 5, Delay: 5 * time.Millisecond, Lockout: time.Minute})
+
+		tracker.Failed("ip:127.0.0.1")
+
+		time.Sleep(10 * time.Millisecond)
+
+		_, allowed := tracker.Allowed("ip:127.0.0.1")
+
+		assert.True(t, allowed)
+	})
+}